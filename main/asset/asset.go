@@ -0,0 +1,303 @@
+//Package asset finds the files the game loads, such as images, shaders, sounds and data.  Files are looked up in a stack of
+//file systems, such as the directory of the game, an embed.FS or a zip archive, where the file systems mounted last are
+//searched first.  A mod pack mounted on top of the base assets replaces any file found under the same path.
+package asset
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/koinuri/game-project/main/global"
+)
+
+type layer struct {
+	fsys   fs.FS
+	closer io.Closer
+	//what the file system is, for error messages
+	name string
+}
+
+var (
+	mutex sync.RWMutex
+	//searched from the end, so the layers mounted last are found first
+	layers = []layer{{gameDirectory{}, nil, ""}}
+)
+
+//The directory of the game.  It is read through global.Directory on every call, since that is only known once the game
+//starts.
+type gameDirectory struct{}
+
+func (gameDirectory) Open(name string) (fs.File, error) {
+	return os.DirFS(global.Directory).Open(name)
+}
+
+//Puts the file system on top of the ones mounted before, such as an embed.FS.  Use fs.Sub first if the assets are in a
+//directory of the file system.
+func Mount(fsys fs.FS) {
+	if fsys == nil {
+		panic("Invalid argument.  The file system cannot be nil")
+	}
+
+	mount(layer{fsys, nil, fmt.Sprintf("the file system %T", fsys)})
+}
+
+func mount(l layer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	layers = append(layers, l)
+}
+
+//Mounts a directory on the disk.  A relative dir is relative to the directory of the game.
+func MountDirectory(dir string) fs.FS {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(global.Directory, dir)
+	}
+
+	fsys := os.DirFS(dir)
+	mount(layer{fsys, nil, fmt.Sprintf("the directory \"%v\"", dir)})
+	return fsys
+}
+
+//Mounts a zip archive, such as a mod pack.  A relative dir is relative to the directory of the game.  The archive stays open
+//until it is unmounted.
+func MountZip(dir string) fs.FS {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(global.Directory, dir)
+	}
+
+	archive, err := zip.OpenReader(dir)
+	if err != nil {
+		panic(fmt.Sprintf("Could not open the archive \"%v\".\n%v", dir, err))
+	}
+
+	mount(layer{archive, archive, fmt.Sprintf("the archive \"%v\"", dir)})
+	return archive
+}
+
+//Removes the file system from the stack, closing it if it was mounted by MountZip
+func Unmount(fsys fs.FS) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for i, l := range layers {
+		if l.fsys == fsys {
+			if l.closer != nil {
+				l.closer.Close()
+			}
+			layers = append(layers[:i], layers[i+1:]...)
+			return
+		}
+	}
+}
+
+//Removes every file system, including the directory of the game, so only what is mounted afterwards is searched.  Useful
+//when every asset is embedded in the executable.
+func UnmountAll() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for _, l := range layers {
+		if l.closer != nil {
+			l.closer.Close()
+		}
+	}
+	layers = nil
+}
+
+//Turns the names used by the game, such as "./images/a.png", into the form fs.FS expects
+func clean(name string) string {
+	return path.Clean(strings.TrimPrefix(name, "/"))
+}
+
+//Returns the name of the file with the file systems it is searched in, from the first one, for error messages such as
+//"sounds/a.ogg" in the archive "mod.zip", the directory of the game "/home/game"
+func Describe(name string) string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	if len(layers) == 0 {
+		return fmt.Sprintf("\"%v\" with no file system mounted", clean(name))
+	}
+
+	where := make([]string, len(layers))
+	for i, l := range layers {
+		where[len(layers)-1-i] = l.describe()
+	}
+	return fmt.Sprintf("\"%v\" in %v", clean(name), strings.Join(where, ", "))
+}
+
+func (l layer) describe() string {
+	if _, succ := l.fsys.(gameDirectory); succ {
+		return fmt.Sprintf("the directory of the game \"%v\"", global.Directory)
+	}
+	return l.name
+}
+
+//Returns the mounted file systems, searched from the first one
+func search() []fs.FS {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	found := make([]fs.FS, len(layers))
+	for i, l := range layers {
+		found[len(layers)-1-i] = l.fsys
+	}
+	return found
+}
+
+//Opens the file from the topmost file system holding it
+func Open(name string) (fs.File, error) {
+	name = clean(name)
+
+	for _, fsys := range search() {
+		file, err := fsys.Open(name)
+		if err == nil {
+			return file, nil
+		}
+		//a file that exists but cannot be read is not hidden by the layers under it
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+//Reads the whole file from the topmost file system holding it
+func ReadFile(name string) ([]byte, error) {
+	file, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+//Opens the file for reading in any order, as the audio streams need.  Files that cannot seek, such as the ones in a zip
+//archive, are read into memory first.
+func OpenSeeker(name string) (io.ReadSeekCloser, error) {
+	file, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if seeker, succ := file.(io.ReadSeekCloser); succ {
+		return seeker, nil
+	}
+
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	return nopCloser{bytes.NewReader(data)}, nil
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+//Returns information about the file from the topmost file system holding it
+func Stat(name string) (fs.FileInfo, error) {
+	name = clean(name)
+
+	for _, fsys := range search() {
+		info, err := fs.Stat(fsys, name)
+		if err == nil {
+			return info, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+//Returns the entries of the directory across every file system, sorted by name.  An entry found in several file systems
+//is the one of the topmost.
+func ReadDir(name string) ([]fs.DirEntry, error) {
+	name = clean(name)
+
+	entries := make(map[string]fs.DirEntry)
+	found := false
+	for _, fsys := range search() {
+		list, err := fs.ReadDir(fsys, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		found = true
+		for _, e := range list {
+			if _, succ := entries[e.Name()]; !succ {
+				entries[e.Name()] = e
+			}
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	list := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(a, b int) bool {
+		return list[a].Name() < list[b].Name()
+	})
+	return list, nil
+}
+
+//Returns the stack of file systems as one fs.FS, for code that works with the standard library, such as template.ParseFS
+func FS() fs.FS {
+	return layered{}
+}
+
+type layered struct{}
+
+func (layered) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return Open(name)
+}
+
+func (layered) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	return ReadFile(name)
+}
+
+func (layered) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return ReadDir(name)
+}
+
+func (layered) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	return Stat(name)
+}
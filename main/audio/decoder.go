@@ -0,0 +1,122 @@
+package audio
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/jfreymuth/oggvorbis"
+	"github.com/koinuri/game-project/main/asset"
+)
+
+//Reads the samples of an audio file as float32 values in [-1, 1], with the channels of a frame next to each other
+type decoder interface {
+	format() (channels, sampleRate int)
+	//Returns the number of frames in the file
+	length() int64
+	//Reads as many whole frames as fit in p and returns the number of values read
+	read(p []float32) (int, error)
+	seek(frame int64) error
+	//Returns the loop markers stored in the file as the first frame of the loop and the frame after its end, or 0 and 0 if
+	//the file has none
+	loopPoints() (int64, int64)
+}
+
+//Opens the asset and picks the decoder from its extension
+func openDecoder(dir string) (decoder, io.ReadSeekCloser, error) {
+	file, err := asset.OpenSeeker(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var d decoder
+	switch strings.ToLower(path.Ext(dir)) {
+	case ".wav":
+		d, err = newWavDecoder(file)
+	case ".ogg":
+		d, err = newOggDecoder(file)
+	default:
+		err = fmt.Errorf("unsupported format %v, expected .wav or .ogg", path.Ext(dir))
+	}
+
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return d, file, nil
+}
+
+type oggDecoder struct {
+	reader    *oggvorbis.Reader
+	loopStart int64
+	loopEnd   int64
+}
+
+func newOggDecoder(file io.Reader) (*oggDecoder, error) {
+	r, err := oggvorbis.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &oggDecoder{reader: r}
+	d.readLoopComments()
+	return d, nil
+}
+
+//Reads the LOOPSTART and LOOPLENGTH or LOOPEND comments many music tools write into .ogg files
+func (d *oggDecoder) readLoopComments() {
+	var start, length, end int64 = -1, -1, -1
+	for _, comment := range d.reader.CommentHeader().Comments {
+		pair := strings.SplitN(comment, "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+
+		value, err := strconv.ParseInt(strings.TrimSpace(pair[1]), 10, 64)
+		if err != nil {
+			continue
+		}
+		switch strings.ToUpper(pair[0]) {
+		case "LOOPSTART":
+			start = value
+		case "LOOPLENGTH":
+			length = value
+		case "LOOPEND":
+			end = value
+		}
+	}
+
+	if start < 0 {
+		return
+	}
+	if length > 0 {
+		end = start + length
+	}
+	if end <= start || end > d.reader.Length() {
+		end = d.reader.Length()
+	}
+	d.loopStart, d.loopEnd = start, end
+}
+
+func (d *oggDecoder) loopPoints() (int64, int64) {
+	return d.loopStart, d.loopEnd
+}
+
+func (d *oggDecoder) format() (int, int) {
+	return d.reader.Channels(), d.reader.SampleRate()
+}
+
+func (d *oggDecoder) length() int64 {
+	return d.reader.Length()
+}
+
+func (d *oggDecoder) read(p []float32) (int, error) {
+	ch := d.reader.Channels()
+	return d.reader.Read(p[:len(p)/ch*ch])
+}
+
+func (d *oggDecoder) seek(frame int64) error {
+	return d.reader.SetPosition(frame)
+}
@@ -0,0 +1,548 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+type bus uint32
+
+const (
+	//Background music
+	BusBGM bus = iota
+	//Sound effects
+	BusSE
+	//Voice lines
+	BusVoice
+	busCount
+)
+
+//Number of frames mixed at a time by Start
+const mixBlock = 1024
+
+//Mixes every voice playing into one stereo signal and sends it to an output.  Voices are grouped into buses, so the music,
+//effects and voice lines can be turned up and down separately.
+type Mixer struct {
+	mutex       sync.Mutex
+	output      Output
+	sampleRate  int
+	master      float64
+	buses       [busCount]float64
+	voices      []*Voice
+	buffer      []float32
+	running     bool
+	stopped     chan bool
+	err         error
+	duckLevel   float64
+	duckAttack  float64
+	duckRelease float64
+	duck        float64
+}
+
+//Creates a mixer sending its audio to the output, such as InitDeviceOutput(44100).
+func InitMixer(output Output) *Mixer {
+	m := &Mixer{
+		output:     output,
+		sampleRate: output.GetSampleRate(),
+		master:     1,
+		voices:     make([]*Voice, 0),
+		duckLevel:  1,
+		duck:       1,
+	}
+	for i := range m.buses {
+		m.buses[i] = 1
+	}
+	return m
+}
+
+func (m *Mixer) GetSampleRate() int {
+	return m.sampleRate
+}
+
+func (m *Mixer) SetMasterVolume(volume float64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.master = volume
+}
+
+func (m *Mixer) GetMasterVolume() float64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.master
+}
+
+func (m *Mixer) SetBusVolume(b bus, volume float64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.buses[b] = volume
+}
+
+func (m *Mixer) GetBusVolume(b bus) float64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.buses[b]
+}
+
+//Turns the music down while a voice line plays, so the line can be heard over it.
+//	*SetDucking(level, attack, release)
+//Where:
+//	level is the volume the BGM bus is turned down to, such as .3.  1 turns ducking off.
+//	attack and release are the seconds taken to turn the music down when a line starts, and back up once every line
+//	has finished.
+func (m *Mixer) SetDucking(level, attack, release float64) {
+	if level < 0 || level > 1 || attack < 0 || release < 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a level within [0, 1] and times of 0 or more, found %v, %v and %v", level, attack, release))
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.duckLevel = level
+	m.duckAttack = attack
+	m.duckRelease = release
+}
+
+//Moves the ducking of the BGM bus towards where it should be after the frames given
+func (m *Mixer) updateDucking(frames int) {
+	target := 1.0
+	for _, v := range m.voices {
+		if v.bus == BusVoice && !v.done && !v.paused {
+			target = m.duckLevel
+			break
+		}
+	}
+
+	//the music moves the whole way between 1 and the ducked level in the time given
+	seconds := m.duckRelease
+	if target < m.duck {
+		seconds = m.duckAttack
+	}
+	if seconds <= 0 || m.duckLevel >= 1 {
+		m.duck = target
+		return
+	}
+
+	change := float64(frames) / float64(m.sampleRate) / seconds * (1 - m.duckLevel)
+	if target < m.duck {
+		m.duck = math.Max(target, m.duck-change)
+	} else {
+		m.duck = math.Min(target, m.duck+change)
+	}
+}
+
+//Starts playing the source on the bus and returns the voice playing it.
+func (m *Mixer) Play(source Source, b bus) *Voice {
+	if source == nil {
+		panic("Invalid argument.  The source cannot be nil")
+	}
+
+	v := m.newVoice(source, b)
+	m.add(v)
+	return v
+}
+
+//Creates a voice without starting it, so it can be set up before it is first heard
+func (m *Mixer) newVoice(source Source, b bus) *Voice {
+	v := &Voice{mixer: m, source: source, bus: b, volume: 1, pitch: 1}
+	v.loopStart, v.loopEnd = source.GetLoopPoints()
+	return v
+}
+
+func (m *Mixer) add(v *Voice) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.voices = append(m.voices, v)
+}
+
+//Stops every voice
+func (m *Mixer) StopAll() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, v := range m.voices {
+		v.done = true
+	}
+}
+
+//Returns the voices that have not finished yet
+func (m *Mixer) GetVoices() []*Voice {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	voices := make([]*Voice, 0, len(m.voices))
+	for _, v := range m.voices {
+		if !v.done {
+			voices = append(voices, v)
+		}
+	}
+	return voices
+}
+
+//Mixes the next frames into out, which holds two samples per frame, and returns the voices that finished on the way
+func (m *Mixer) mix(out []float32) []*Voice {
+	for i := range out {
+		out[i] = 0
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.updateDucking(len(out) / 2)
+
+	finished := make([]*Voice, 0)
+	playing := m.voices[:0]
+	for _, v := range m.voices {
+		if !v.done && !v.paused {
+			volume := m.master * m.buses[v.bus] * v.volume
+			if v.bus == BusBGM {
+				volume *= m.duck
+			}
+			v.mix(out, volume)
+			if v.done {
+				finished = append(finished, v)
+			}
+		}
+		if !v.done {
+			playing = append(playing, v)
+		}
+	}
+
+	//clear the voices dropped so they can be freed
+	for i := len(playing); i < len(m.voices); i++ {
+		m.voices[i] = nil
+	}
+	m.voices = playing
+
+	return finished
+}
+
+//Mixes the next frames and writes them to the output.  Start does this on its own, so only call it to drive outputs by hand,
+//such as a FileOutput when testing.
+func (m *Mixer) Render(frames int) error {
+	if cap(m.buffer) < frames*2 {
+		m.buffer = make([]float32, frames*2)
+	}
+	out := m.buffer[:frames*2]
+
+	//the callbacks run without the lock, so they can start or stop voices
+	for _, v := range m.mix(out) {
+		if v.onComplete != nil {
+			v.onComplete()
+		}
+	}
+
+	return m.output.Write(out)
+}
+
+//Keeps mixing and writing to the output in the background until Stop is called.  Outputs that do not block, such as
+//NullOutput and FileOutput, are written as fast as the mixer can go, so use Render for them instead.
+func (m *Mixer) Start() {
+	m.mutex.Lock()
+	if m.running {
+		m.mutex.Unlock()
+		return
+	}
+	m.running = true
+	m.stopped = make(chan bool)
+	m.mutex.Unlock()
+
+	go func() {
+		defer close(m.stopped)
+		for {
+			m.mutex.Lock()
+			running := m.running
+			m.mutex.Unlock()
+			if !running {
+				return
+			}
+
+			if err := m.Render(mixBlock); err != nil {
+				m.mutex.Lock()
+				m.err = err
+				m.running = false
+				m.mutex.Unlock()
+				return
+			}
+		}
+	}()
+}
+
+//Stops mixing in the background and waits for the last frames to be written
+func (m *Mixer) Stop() {
+	m.mutex.Lock()
+	stopped := m.stopped
+	m.running = false
+	m.mutex.Unlock()
+
+	if stopped != nil {
+		<-stopped
+	}
+}
+
+//Returns the error that stopped the background mixing, if any
+func (m *Mixer) GetError() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.err
+}
+
+//Stops the mixer and closes its output
+func (m *Mixer) Close() error {
+	m.Stop()
+	return m.output.Close()
+}
+
+//A source being played by the mixer.
+type Voice struct {
+	mixer      *Mixer
+	source     Source
+	bus        bus
+	volume     float64
+	pan        float64
+	pitch      float64
+	position   float64
+	loop       bool
+	loopStart  int64
+	loopEnd    int64
+	paused     bool
+	done       bool
+	onComplete func()
+	buffer     []float32
+}
+
+func (v *Voice) GetSource() Source {
+	return v.source
+}
+
+func (v *Voice) GetBus() bus {
+	return v.bus
+}
+
+func (v *Voice) SetVolume(volume float64) {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	v.volume = volume
+}
+
+func (v *Voice) GetVolume() float64 {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	return v.volume
+}
+
+//Returns a tween fading the volume of the voice to the volume given over the seconds given.  Play it with an Animator.
+func (v *Voice) FadeTo(volume, seconds float64) *framework.Tween {
+	return framework.InitTween(v, framework.TweenVolume, seconds, volume)
+}
+
+//Sets where the voice is heard, from -1 for left only to 1 for right only.  0 is the center.
+func (v *Voice) SetPan(pan float64) {
+	if pan < -1 || pan > 1 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a pan within [-1, 1], found %v", pan))
+	}
+
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	v.pan = pan
+}
+
+func (v *Voice) GetPan() float64 {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	return v.pan
+}
+
+//Sets how fast the source is played.  2 plays it twice as fast and an octave higher, .5 half as fast and an octave lower.
+func (v *Voice) SetPitch(pitch float64) {
+	if pitch <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive pitch, found %v", pitch))
+	}
+
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	v.pitch = pitch
+}
+
+func (v *Voice) GetPitch() float64 {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	return v.pitch
+}
+
+//Sets whether the voice goes back to the start of its loop once it reaches the end of it, instead of finishing
+func (v *Voice) SetLoop(loop bool) {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	v.loop = loop
+}
+
+func (v *Voice) IsLooping() bool {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	return v.loop
+}
+
+//Sets the part repeated while looping, from the frame start up to but not including the frame end.  They are copied from
+//the source when the voice starts.
+func (v *Voice) SetLoopPoints(start, end int64) {
+	checkLoopPoints(v.source, start, end)
+
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	v.loopStart, v.loopEnd = start, end
+}
+
+func (v *Voice) GetLoopPoints() (int64, int64) {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	return v.loopStart, v.loopEnd
+}
+
+func (v *Voice) Pause() {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	v.paused = true
+}
+
+func (v *Voice) Resume() {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	v.paused = false
+}
+
+//Stops the voice for good.  The function set by OnComplete is not called.
+func (v *Voice) Stop() {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	v.done = true
+}
+
+func (v *Voice) IsPlaying() bool {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	return !v.done && !v.paused
+}
+
+func (v *Voice) IsDone() bool {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	return v.done
+}
+
+//Sets the function called once the voice reaches the end without looping.  It is called from the goroutine of the mixer.
+func (v *Voice) OnComplete(f func()) {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	v.onComplete = f
+}
+
+//Moves the voice to the time given in seconds
+func (v *Voice) Seek(seconds float64) {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	v.position = math.Max(0, seconds*float64(v.source.GetSampleRate()))
+}
+
+//Returns how far into the source the voice is, in seconds
+func (v *Voice) GetTime() float64 {
+	v.mixer.mutex.Lock()
+	defer v.mixer.mutex.Unlock()
+	return v.position / float64(v.source.GetSampleRate())
+}
+
+//Returns the gain of the left and right channel.  Mono sources are panned keeping the same loudness across, stereo sources
+//are balanced by turning down the other side.
+func (v *Voice) gains(volume float64) (float32, float32) {
+	if v.source.GetChannels() == 1 {
+		angle := (v.pan + 1) * math.Pi / 4
+		return float32(volume * math.Cos(angle)), float32(volume * math.Sin(angle))
+	}
+
+	left, right := volume, volume
+	if v.pan > 0 {
+		left *= 1 - v.pan
+	} else {
+		right *= 1 + v.pan
+	}
+	return float32(left), float32(right)
+}
+
+//Adds the next frames of the voice to out, resampling the source to the rate of the mixer
+func (v *Voice) mix(out []float32, volume float64) {
+	frames := len(out) / 2
+	ch := v.source.GetChannels()
+	step := v.pitch * float64(v.source.GetSampleRate()) / float64(v.mixer.sampleRate)
+	end := float64(v.source.GetLength())
+	restart := 0.0
+	if v.loop && v.loopEnd > v.loopStart {
+		end = float64(v.loopEnd)
+		restart = float64(v.loopStart)
+	}
+	left, right := v.gains(volume)
+
+	done := 0
+	for done < frames && !v.done {
+		//mix up to the end of the source or the loop, then loop or finish
+		count := frames - done
+		if remaining := int(math.Ceil((end - v.position) / step)); remaining < count {
+			count = remaining
+		}
+
+		if count > 0 {
+			first := int64(v.position)
+			need := int(v.position+float64(count)*step) - int(first) + 2
+			if cap(v.buffer) < need*ch {
+				v.buffer = make([]float32, need*ch)
+			}
+			buf := v.buffer[:need*ch]
+
+			n := v.source.readAt(buf, first)
+			if n == 0 {
+				//the source ended early, such as a stream failing to decode
+				v.position = end
+			}
+
+			//while looping, the frame after the end is the start of the loop, so blending into it joins the loop smoothly
+			if last := int(int64(end) - first); v.loop && end > restart && n > 0 && last < need && last <= n {
+				if v.source.readAt(buf[last*ch:(last+1)*ch], int64(restart)) == 1 {
+					n = last + 1
+				}
+			}
+
+			for i := 0; i < count && n > 0; i++ {
+				p := v.position - float64(first)
+				j := int(p)
+				if j >= n {
+					j = n - 1
+				}
+				k := j + 1
+				if k >= n {
+					k = n - 1
+				}
+				t := float32(p - float64(j))
+
+				a, b := buf[j*ch:], buf[k*ch:]
+				l := a[0] + (b[0]-a[0])*t
+				r := l
+				if ch > 1 {
+					r = a[1] + (b[1]-a[1])*t
+				}
+
+				out[(done+i)*2] += l * left
+				out[(done+i)*2+1] += r * right
+				v.position += step
+			}
+			done += count
+		}
+
+		if v.position >= end {
+			if v.loop && end > restart {
+				//keep the part of a frame that went past the end, so the loop does not drift
+				v.position = restart + math.Mod(v.position-end, end-restart)
+			} else {
+				v.done = true
+			}
+		}
+	}
+}
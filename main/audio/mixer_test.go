@@ -0,0 +1,250 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+//Gain of both sides of a mono voice in the center, which keeps the same loudness as a voice on one side
+const center = math.Sqrt2 / 2
+
+func TestMixerRender(t *testing.T) {
+	ramp := InitSoundFromSamples([]float32{0, .1, .2, .3, .4, .5, .6, .7}, 1, 100)
+	stereo := InitSoundFromSamples([]float32{.2, .4, .2, .4, .2, .4, .2, .4}, 2, 100)
+	fast := InitSoundFromSamples([]float32{0, .1, .2, .3, .4, .5, .6, .7}, 1, 200)
+
+	cases := []struct {
+		name      string
+		sound     *Sound
+		master    float64
+		busVolume float64
+		volume    float64
+		pan       float64
+		pitch     float64
+		left      []float32
+		right     []float32
+	}{
+		{
+			name: "mono in the center", sound: ramp, master: 1, busVolume: 1, volume: 1, pan: 0, pitch: 1,
+			left:  []float32{0, .1 * center, .2 * center, .3 * center},
+			right: []float32{0, .1 * center, .2 * center, .3 * center},
+		},
+		{
+			name: "mono on the left", sound: ramp, master: 1, busVolume: 1, volume: 1, pan: -1, pitch: 1,
+			left:  []float32{0, .1, .2, .3},
+			right: []float32{0, 0, 0, 0},
+		},
+		{
+			name: "volumes multiply", sound: ramp, master: .5, busVolume: .5, volume: .5, pan: -1, pitch: 1,
+			left:  []float32{0, .0125, .025, .0375},
+			right: []float32{0, 0, 0, 0},
+		},
+		{
+			name: "stereo balanced to the right", sound: stereo, master: 1, busVolume: 1, volume: 1, pan: .5, pitch: 1,
+			left:  []float32{.1, .1, .1, .1, 0, 0},
+			right: []float32{.4, .4, .4, .4, 0, 0},
+		},
+		{
+			name: "pitch up skips frames and ends sooner", sound: ramp, master: 1, busVolume: 1, volume: 1, pan: -1, pitch: 2,
+			left:  []float32{0, .2, .4, .6, 0, 0},
+			right: []float32{0, 0, 0, 0, 0, 0},
+		},
+		{
+			name: "pitch down blends between frames", sound: ramp, master: 1, busVolume: 1, volume: 1, pan: -1, pitch: .5,
+			left:  []float32{0, .05, .1, .15, .2, .25},
+			right: []float32{0, 0, 0, 0, 0, 0},
+		},
+		{
+			name: "sources are resampled to the mixer", sound: fast, master: 1, busVolume: 1, volume: 1, pan: -1, pitch: 1,
+			left:  []float32{0, .2, .4, .6, 0},
+			right: []float32{0, 0, 0, 0, 0},
+		},
+	}
+
+	for _, c := range cases {
+		out := InitNullOutput(100)
+		out.SetRecording(true)
+
+		m := InitMixer(out)
+		m.SetMasterVolume(c.master)
+		m.SetBusVolume(BusSE, c.busVolume)
+		//the other buses must not change a voice on the effects bus
+		m.SetBusVolume(BusBGM, 0)
+		m.SetBusVolume(BusVoice, 0)
+
+		v := m.Play(c.sound, BusSE)
+		v.SetVolume(c.volume)
+		v.SetPan(c.pan)
+		v.SetPitch(c.pitch)
+
+		if err := m.Render(len(c.left)); err != nil {
+			t.Fatalf("%v: %v", c.name, err)
+		}
+
+		samples := out.GetSamples()
+		if len(samples) != len(c.left)*2 {
+			t.Fatalf("%v: expected %v samples, found %v", c.name, len(c.left)*2, len(samples))
+		}
+		for i := range c.left {
+			l, r := samples[i*2], samples[i*2+1]
+			if math.Abs(float64(l-c.left[i])) > 1e-5 || math.Abs(float64(r-c.right[i])) > 1e-5 {
+				t.Errorf("%v: frame %v is (%v, %v), expected (%v, %v)", c.name, i, l, r, c.left[i], c.right[i])
+			}
+		}
+	}
+}
+
+func TestMixerSumsVoices(t *testing.T) {
+	out := InitNullOutput(100)
+	out.SetRecording(true)
+	m := InitMixer(out)
+
+	m.Play(InitSoundFromSamples([]float32{.5, .5, .5, .5}, 2, 100), BusBGM)
+	v := m.Play(InitSoundFromSamples([]float32{.25, -.25}, 2, 100), BusSE)
+	v.SetVolume(2)
+
+	if err := m.Render(2); err != nil {
+		t.Fatal(err)
+	}
+	want := []float32{1, 0, .5, .5}
+	for i, s := range out.GetSamples() {
+		if math.Abs(float64(s-want[i])) > 1e-5 {
+			t.Errorf("sample %v is %v, expected %v", i, s, want[i])
+		}
+	}
+
+	if len(m.GetVoices()) != 0 {
+		t.Errorf("expected every voice to be done, found %v playing", len(m.GetVoices()))
+	}
+	if out.GetFrames() != 2 {
+		t.Errorf("expected 2 frames written, found %v", out.GetFrames())
+	}
+}
+
+func TestMixerLoops(t *testing.T) {
+	ramp := []float32{0, .1, .2, .3, .4, .5, .6, .7}
+
+	cases := []struct {
+		name      string
+		loop      bool
+		soundLoop [2]int64
+		voiceLoop [2]int64
+		pitch     float64
+		left      []float32
+	}{
+		{
+			name: "the whole sound without loop points", loop: true, pitch: 1,
+			left: []float32{0, .1, .2, .3, .4, .5, .6, .7, 0, .1},
+		},
+		{
+			name: "the loop points of the sound", loop: true, soundLoop: [2]int64{2, 5}, pitch: 1,
+			left: []float32{0, .1, .2, .3, .4, .2, .3, .4, .2, .3},
+		},
+		{
+			name: "the loop points of the voice over the ones of the sound", loop: true, soundLoop: [2]int64{2, 5},
+			voiceLoop: [2]int64{6, 8}, pitch: 1,
+			left: []float32{0, .1, .2, .3, .4, .5, .6, .7, .6, .7},
+		},
+		{
+			name: "loop points without looping", loop: false, soundLoop: [2]int64{2, 5}, pitch: 1,
+			left: []float32{0, .1, .2, .3, .4, .5, .6, .7, 0, 0},
+		},
+		{
+			name: "the seam blends into the start of the loop", loop: true, soundLoop: [2]int64{2, 5}, pitch: .5,
+			left: []float32{0, .05, .1, .15, .2, .25, .3, .35, .4, .3, .2, .25},
+		},
+	}
+
+	for _, c := range cases {
+		out := InitNullOutput(100)
+		out.SetRecording(true)
+		m := InitMixer(out)
+
+		s := InitSoundFromSamples(ramp, 1, 100)
+		if c.soundLoop[1] > 0 {
+			s.SetLoopPoints(c.soundLoop[0], c.soundLoop[1])
+		}
+		v := m.Play(s, BusSE)
+		if c.voiceLoop[1] > 0 {
+			v.SetLoopPoints(c.voiceLoop[0], c.voiceLoop[1])
+		}
+		v.SetLoop(c.loop)
+		v.SetPan(-1)
+		v.SetPitch(c.pitch)
+
+		if err := m.Render(len(c.left)); err != nil {
+			t.Fatalf("%v: %v", c.name, err)
+		}
+		samples := out.GetSamples()
+		for i := range c.left {
+			if l := samples[i*2]; math.Abs(float64(l-c.left[i])) > 1e-5 {
+				t.Errorf("%v: frame %v is %v, expected %v", c.name, i, l, c.left[i])
+			}
+		}
+		if v.IsDone() == c.loop {
+			t.Errorf("%v: expected the voice to be done %v, found %v", c.name, !c.loop, v.IsDone())
+		}
+	}
+}
+
+func TestMixerDucking(t *testing.T) {
+	cases := []struct {
+		name    string
+		level   float64
+		attack  float64
+		release float64
+		//frames of the voice line, which starts with the music
+		line int
+		//volume of the music at some of the frames
+		music map[int]float32
+	}{
+		{
+			name: "fades down and back up", level: .5, attack: .1, release: .2, line: 20,
+			music: map[int]float32{0: .95, 4: .75, 9: .5, 19: .5, 20: .525, 29: .75, 39: 1, 45: 1},
+		},
+		{
+			name: "cuts without fade times", level: .3, attack: 0, release: 0, line: 5,
+			music: map[int]float32{0: .3, 4: .3, 5: 1, 10: 1},
+		},
+		{
+			name: "does nothing at full level", level: 1, attack: .1, release: .1, line: 5,
+			music: map[int]float32{0: 1, 4: 1, 5: 1},
+		},
+	}
+
+	for _, c := range cases {
+		out := InitNullOutput(100)
+		out.SetRecording(true)
+		m := InitMixer(out)
+		m.SetDucking(c.level, c.attack, c.release)
+
+		//the music is a constant 1 on the left, so the left channel is its volume
+		music := m.Play(InitSoundFromSamples(constant(1, 100), 1, 100), BusBGM)
+		music.SetLoop(true)
+		music.SetPan(-1)
+		line := m.Play(InitSoundFromSamples(make([]float32, c.line), 1, 100), BusVoice)
+		line.SetPan(1)
+
+		//one frame at a time, as the ducking moves once per block
+		for i := 0; i < 50; i++ {
+			if err := m.Render(1); err != nil {
+				t.Fatalf("%v: %v", c.name, err)
+			}
+		}
+		samples := out.GetSamples()
+		for frame, want := range c.music {
+			if l := samples[frame*2]; math.Abs(float64(l-want)) > 1e-5 {
+				t.Errorf("%v: the music is at %v at frame %v, expected %v", c.name, l, frame, want)
+			}
+		}
+	}
+}
+
+//Returns n samples of the value
+func constant(value float32, n int) []float32 {
+	samples := make([]float32, n)
+	for i := range samples {
+		samples[i] = value
+	}
+	return samples
+}
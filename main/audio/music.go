@@ -0,0 +1,208 @@
+package audio
+
+import (
+	"fmt"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Plays background music on the BGM bus, crossfading from one track to the next.  Tracks started with Play loop, so
+//tracks with loop points play their intro once and then repeat their loop.  Tracks started with PlayList play once each,
+//one after another.
+//The fades are tweens, so the player has to be updated every frame.
+type MusicPlayer struct {
+	mixer     *Mixer
+	animator  framework.Animator
+	volume    float64
+	crossfade float64
+	current   *Voice
+	fades     map[*Voice]*framework.Tween
+	playlist  []Source
+	index     int
+	repeat    bool
+}
+
+//Creates a music player.
+//	*InitMusicPlayer(mixer)
+//	*InitMusicPlayer(mixer, crossfade)
+//Where:
+//	crossfade is the seconds taken to fade from one track into the next.  It is defaulted to 1.
+func InitMusicPlayer(mixer *Mixer, crossfade ...float64) *MusicPlayer {
+	p := &MusicPlayer{
+		mixer:     mixer,
+		animator:  framework.InitAnimator(),
+		volume:    1,
+		crossfade: 1,
+		fades:     make(map[*Voice]*framework.Tween),
+	}
+
+	switch len(crossfade) {
+	case 0:
+	case 1:
+		p.SetCrossfade(crossfade[0])
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one float64, found %v.", len(crossfade)))
+	}
+
+	return p
+}
+
+func (p *MusicPlayer) SetCrossfade(seconds float64) {
+	if seconds < 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected 0 seconds or more, found %v", seconds))
+	}
+	p.crossfade = seconds
+}
+
+func (p *MusicPlayer) GetCrossfade() float64 {
+	return p.crossfade
+}
+
+//Sets the volume of the music.  A fade in that is still going on is cut short.
+func (p *MusicPlayer) SetVolume(volume float64) {
+	p.volume = volume
+	if p.current != nil {
+		p.stopFade(p.current)
+		p.current.SetVolume(volume)
+	}
+}
+
+func (p *MusicPlayer) GetVolume() float64 {
+	return p.volume
+}
+
+//Returns the voice of the track playing, or nil if there is none
+func (p *MusicPlayer) GetCurrent() *Voice {
+	return p.current
+}
+
+//Crossfades into the source and loops it until something else is played.
+//	*Play(source)
+//	*Play(source, fade)
+//Where:
+//	fade is the seconds of the crossfade.  It is defaulted to the crossfade of the player, and 0 cuts straight to the source.
+func (p *MusicPlayer) Play(source Source, fade ...float64) {
+	p.playlist = nil
+	p.start(source, true, p.fadeTime(fade))
+}
+
+//Plays the sources one after another, crossfading between them.  The list stops after the last source unless SetRepeat is
+//on.
+func (p *MusicPlayer) PlayList(sources ...Source) {
+	if len(sources) == 0 {
+		panic("Invalid number of arguments.  Expected at least one source, found 0.")
+	}
+
+	p.playlist = sources
+	p.index = 0
+	p.start(sources[0], false, p.crossfade)
+}
+
+//Sets whether the playlist starts over after its last source
+func (p *MusicPlayer) SetRepeat(repeat bool) {
+	p.repeat = repeat
+}
+
+//Crossfades into the next source of the playlist, or fades out if the playlist is over
+func (p *MusicPlayer) Next() {
+	if p.playlist == nil {
+		return
+	}
+
+	p.index++
+	if p.index >= len(p.playlist) {
+		if !p.repeat {
+			p.playlist = nil
+			p.Stop()
+			return
+		}
+		p.index = 0
+	}
+
+	p.start(p.playlist[p.index], false, p.crossfade)
+}
+
+//Fades out the music.
+//	*Stop()
+//	*Stop(fade)
+//Where:
+//	fade is the seconds of the fade.  It is defaulted to the crossfade of the player, and 0 stops the music at once.
+func (p *MusicPlayer) Stop(fade ...float64) {
+	p.playlist = nil
+	if p.current != nil {
+		p.fade(p.current, 0, p.fadeTime(fade), true)
+		p.current = nil
+	}
+}
+
+//Advances the fades and moves on through the playlist.  Call it once per frame.
+func (p *MusicPlayer) Update(dt float64) {
+	p.animator.Update(dt)
+
+	if p.playlist == nil || p.current == nil {
+		return
+	}
+
+	//start the next track early enough that the crossfade ends as the current one does
+	v := p.current
+	remaining := (v.GetSource().GetDuration() - v.GetTime()) / v.GetPitch()
+	if v.IsDone() || remaining <= p.crossfade {
+		p.Next()
+	}
+}
+
+func (p *MusicPlayer) fadeTime(fade []float64) float64 {
+	switch len(fade) {
+	case 0:
+		return p.crossfade
+	case 1:
+		return fade[0]
+	}
+	panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one float64, found %v.", len(fade)))
+}
+
+//Fades out the current track and fades in a voice playing the source
+func (p *MusicPlayer) start(source Source, loop bool, fade float64) {
+	if p.current != nil {
+		p.fade(p.current, 0, fade, true)
+	}
+
+	//the voice is set up before the mixer sees it, so it never plays a block at full volume
+	v := p.mixer.newVoice(source, BusBGM)
+	v.loop = loop
+	v.volume = 0
+	p.mixer.add(v)
+
+	p.fade(v, p.volume, fade, false)
+	p.current = v
+}
+
+//Fades the voice to the volume, stopping it at the end if asked to.  A fade already going on for the voice is replaced.
+func (p *MusicPlayer) fade(v *Voice, volume, seconds float64, stop bool) {
+	p.stopFade(v)
+
+	if seconds <= 0 {
+		v.SetVolume(volume)
+		if stop {
+			v.Stop()
+		}
+		return
+	}
+
+	t := v.FadeTo(volume, seconds)
+	t.OnComplete(func() {
+		delete(p.fades, v)
+		if stop {
+			v.Stop()
+		}
+	})
+	p.fades[v] = t
+	p.animator.Play(t)
+}
+
+func (p *MusicPlayer) stopFade(v *Voice) {
+	if t, succ := p.fades[v]; succ {
+		p.animator.Stop(t)
+		delete(p.fades, v)
+	}
+}
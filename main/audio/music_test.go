@@ -0,0 +1,103 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMusicPlayerCrossfade(t *testing.T) {
+	m := InitMixer(InitNullOutput(100))
+	p := InitMusicPlayer(m, .4)
+
+	a := InitSoundFromSamples(constant(.5, 1000), 1, 100)
+	b := InitSoundFromSamples(constant(.5, 1000), 1, 100)
+
+	p.Play(a)
+	first := p.GetCurrent()
+	p.Update(.4)
+	p.Play(b)
+	second := p.GetCurrent()
+
+	cases := []struct {
+		dt     float64
+		first  float64
+		second float64
+	}{
+		{0, 1, 0},
+		{.1, .75, .25},
+		{.1, .5, .5},
+		{.2, 0, 1},
+	}
+
+	for i, c := range cases {
+		p.Update(c.dt)
+		if math.Abs(first.GetVolume()-c.first) > 1e-9 || math.Abs(second.GetVolume()-c.second) > 1e-9 {
+			t.Errorf("step %v: the volumes are %v and %v, expected %v and %v", i, first.GetVolume(), second.GetVolume(), c.first, c.second)
+		}
+	}
+
+	if !first.IsDone() {
+		t.Errorf("expected the track faded out to be stopped")
+	}
+	if !second.IsLooping() || p.GetCurrent() != second {
+		t.Errorf("expected the track played to loop and be the current one")
+	}
+}
+
+func TestMusicPlayerPlaylist(t *testing.T) {
+	a := InitSoundFromSamples(constant(.5, 100), 1, 100)
+	b := InitSoundFromSamples(constant(.5, 50), 1, 100)
+
+	cases := []struct {
+		name   string
+		repeat bool
+		//source of the current track after every tenth of a second, nil once the playlist is over.  The next track starts
+		//as many seconds before the end as the crossfade lasts.
+		current []Source
+	}{
+		{
+			name: "plays once", repeat: false,
+			current: []Source{a, a, a, a, a, a, a, b, b, b, nil, nil, nil, nil},
+		},
+		{
+			name: "repeats", repeat: true,
+			current: []Source{a, a, a, a, a, a, a, b, b, b, a, a, a, a},
+		},
+	}
+
+	for _, c := range cases {
+		m := InitMixer(InitNullOutput(100))
+		p := InitMusicPlayer(m, .2)
+		p.SetRepeat(c.repeat)
+		p.PlayList(a, b)
+
+		for i, want := range c.current {
+			if err := m.Render(10); err != nil {
+				t.Fatalf("%v: %v", c.name, err)
+			}
+			p.Update(.1)
+
+			var got Source
+			if v := p.GetCurrent(); v != nil {
+				got = v.GetSource()
+				if v.IsLooping() {
+					t.Errorf("%v: expected the tracks of a playlist to play once", c.name)
+				}
+			}
+			if got != want {
+				t.Fatalf("%v: the current track after %v seconds is %v, expected %v", c.name, float64(i+1)/10, name(got, a, b), name(want, a, b))
+			}
+		}
+	}
+}
+
+//Returns the name of the source in the failures of the tests
+func name(s, a, b Source) string {
+	switch s {
+	case a:
+		return "a"
+	case b:
+		return "b"
+	}
+	return "none"
+}
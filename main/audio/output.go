@@ -0,0 +1,215 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/oto"
+	"github.com/koinuri/game-project/main/global"
+)
+
+//Where the mixer sends the mixed audio.  Samples are stereo, with the left and right sample of a frame next to each other.
+type Output interface {
+	GetSampleRate() int
+	Write(samples []float32) error
+	Close() error
+}
+
+//Converts a mixed sample into a 16 bit integer, clipping anything louder than the output can play
+func toInt16(s float32) int16 {
+	if s > 1 {
+		s = 1
+	} else if s < -1 {
+		s = -1
+	}
+	return int16(s * 32767)
+}
+
+//Output that throws the audio away, for running without a sound card.  It keeps count of the frames written, and keeps the
+//samples too while recording, so tests can check what the mixer made.
+type NullOutput struct {
+	sampleRate int
+	frames     int64
+	recording  bool
+	samples    []float32
+}
+
+func InitNullOutput(sampleRate int) *NullOutput {
+	return &NullOutput{sampleRate: sampleRate}
+}
+
+func (o *NullOutput) GetSampleRate() int {
+	return o.sampleRate
+}
+
+func (o *NullOutput) Write(samples []float32) error {
+	o.frames += int64(len(samples) / 2)
+	if o.recording {
+		o.samples = append(o.samples, samples...)
+	}
+	return nil
+}
+
+func (o *NullOutput) Close() error {
+	return nil
+}
+
+//Returns the number of frames written so far
+func (o *NullOutput) GetFrames() int64 {
+	return o.frames
+}
+
+//Sets whether the samples written from now on are kept.  Stopping throws away the samples kept so far.
+func (o *NullOutput) SetRecording(recording bool) {
+	o.recording = recording
+	if !recording {
+		o.samples = nil
+	}
+}
+
+func (o *NullOutput) IsRecording() bool {
+	return o.recording
+}
+
+//Returns the samples written while recording, with the left and right sample of a frame next to each other
+func (o *NullOutput) GetSamples() []float32 {
+	samples := make([]float32, len(o.samples))
+	copy(samples, o.samples)
+	return samples
+}
+
+//Output that writes the audio into a 16 bit .wav file, for checking what the mixer does without a sound card.
+type FileOutput struct {
+	file       *os.File
+	sampleRate int
+	size       int64
+	buffer     []byte
+}
+
+//Creates the .wav file, relative to the directory of the game.  The file is complete once the output is closed.
+func InitFileOutput(dir string, sampleRate int) *FileOutput {
+	file, err := os.Create(filepath.Join(global.Directory, dir))
+	if err != nil {
+		panic(fmt.Sprintf("Could not create the file \"%v\".\n%v", filepath.Join(global.Directory, dir), err))
+	}
+
+	o := &FileOutput{file: file, sampleRate: sampleRate}
+	if err := o.writeHeader(); err != nil {
+		file.Close()
+		panic(fmt.Sprintf("Could not write to the file \"%v\".\n%v", filepath.Join(global.Directory, dir), err))
+	}
+	return o
+}
+
+//Writes the header of the file.  The sizes in it are only known once everything is written, so it is written again on Close.
+func (o *FileOutput) writeHeader() error {
+	header := make([]byte, 44)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(36+o.size))
+	copy(header[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], wavPCM)
+	binary.LittleEndian.PutUint16(header[22:], 2)
+	binary.LittleEndian.PutUint32(header[24:], uint32(o.sampleRate))
+	binary.LittleEndian.PutUint32(header[28:], uint32(o.sampleRate*4))
+	binary.LittleEndian.PutUint16(header[32:], 4)
+	binary.LittleEndian.PutUint16(header[34:], 16)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], uint32(o.size))
+
+	if _, err := o.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := o.file.Write(header)
+	return err
+}
+
+func (o *FileOutput) GetSampleRate() int {
+	return o.sampleRate
+}
+
+func (o *FileOutput) Write(samples []float32) error {
+	if cap(o.buffer) < len(samples)*2 {
+		o.buffer = make([]byte, len(samples)*2)
+	}
+	b := o.buffer[:len(samples)*2]
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(b[i*2:], uint16(toInt16(s)))
+	}
+
+	if _, err := o.file.Seek(44+o.size, io.SeekStart); err != nil {
+		return err
+	}
+	n, err := o.file.Write(b)
+	o.size += int64(n)
+	return err
+}
+
+func (o *FileOutput) Close() error {
+	if err := o.writeHeader(); err != nil {
+		o.file.Close()
+		return err
+	}
+	return o.file.Close()
+}
+
+//Output that plays the audio on the sound card.  Writing blocks until the sound card has room, which keeps the mixer in
+//time with what is heard.
+type DeviceOutput struct {
+	context    *oto.Context
+	player     *oto.Player
+	sampleRate int
+	buffer     []byte
+}
+
+//Opens the sound card.
+//	*InitDeviceOutput(sampleRate)
+//	*InitDeviceOutput(sampleRate, bufferFrames)
+//Where:
+//	sampleRate is the number of frames played in a second, such as 44100.
+//	bufferFrames is how many frames the sound card holds ahead of what is heard.  Smaller buffers make sounds start sooner
+//	but may crackle on slow machines.  It is defaulted to 2048.
+func InitDeviceOutput(sampleRate int, bufferFrames ...int) *DeviceOutput {
+	frames := 2048
+	switch len(bufferFrames) {
+	case 0:
+	case 1:
+		frames = bufferFrames[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one int, found %v.", len(bufferFrames)))
+	}
+
+	context, err := oto.NewContext(sampleRate, 2, 2, frames*4)
+	if err != nil {
+		panic(fmt.Sprintf("Could not open the sound card.\n%v", err))
+	}
+
+	return &DeviceOutput{context: context, player: context.NewPlayer(), sampleRate: sampleRate}
+}
+
+func (o *DeviceOutput) GetSampleRate() int {
+	return o.sampleRate
+}
+
+func (o *DeviceOutput) Write(samples []float32) error {
+	if cap(o.buffer) < len(samples)*2 {
+		o.buffer = make([]byte, len(samples)*2)
+	}
+	b := o.buffer[:len(samples)*2]
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(b[i*2:], uint16(toInt16(s)))
+	}
+
+	_, err := o.player.Write(b)
+	return err
+}
+
+func (o *DeviceOutput) Close() error {
+	if err := o.player.Close(); err != nil {
+		return err
+	}
+	return o.context.Close()
+}
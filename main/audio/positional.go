@@ -0,0 +1,255 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+type attenuation uint32
+
+const (
+	//Same volume at any distance
+	AttenuationNone attenuation = iota
+	//Volume falls in a straight line from full at the minimum distance to silent at the maximum distance
+	AttenuationLinear
+	//Volume falls like the loudness of a real sound, quickly at first and slower further away
+	AttenuationInverse
+	//Volume falls by the same ratio every time the distance grows by the same ratio
+	AttenuationExponential
+)
+
+//Where sounds are heard from, usually the center of the camera.  Emitters are louder the closer they are to the listener,
+//and panned to the side they are on.
+type Listener struct {
+	mixer    *Mixer
+	x        float64
+	y        float64
+	panWidth float64
+	target   *framework.Object
+	emitters []*Emitter
+}
+
+//Creates a listener at (0, 0) hearing the emitters through the mixer.
+func InitListener(mixer *Mixer) *Listener {
+	if mixer == nil {
+		panic("Invalid argument.  The mixer cannot be nil")
+	}
+	return &Listener{mixer: mixer, panWidth: 800, emitters: make([]*Emitter, 0)}
+}
+
+//Moves the listener, updating every emitter heard by it
+func (l *Listener) Move(x, y float64) {
+	l.x = x
+	l.y = y
+	for _, e := range l.emitters {
+		e.update()
+	}
+}
+
+func (l *Listener) GetPosition() (float64, float64) {
+	return l.x, l.y
+}
+
+//Makes the listener follow the object, such as the player or the camera.  nil stops following.
+func (l *Listener) Follow(target *framework.Object) {
+	if l.target != nil {
+		l.target.RemoveMoveListener(l)
+	}
+
+	l.target = target
+	if target != nil {
+		target.AddMoveListener(l)
+		l.Move(target.GetPosition())
+	}
+}
+
+func (l *Listener) ObjectMoved(o *framework.Object) {
+	l.Move(o.GetPosition())
+}
+
+//Sets how far to the side an emitter has to be to be heard only from that side.  It is defaulted to 800, half the width of
+//the window.
+func (l *Listener) SetPanWidth(width float64) {
+	if width <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive width, found %v", width))
+	}
+	l.panWidth = width
+	for _, e := range l.emitters {
+		e.update()
+	}
+}
+
+func (l *Listener) GetPanWidth() float64 {
+	return l.panWidth
+}
+
+//Plays sounds from the position of an object.  The volume and pan of its voices follow the object and the listener as they
+//move.
+type Emitter struct {
+	target      *framework.Object
+	listener    *Listener
+	volume      float64
+	model       attenuation
+	minDistance float64
+	maxDistance float64
+	rolloff     float64
+	voices      []*Voice
+}
+
+//Creates an emitter attached to the object.
+//	*InitEmitter(target, listener)
+//Where:
+//	target is the object the sounds come from.
+//	listener is where the sounds are heard from.
+//The emitter uses AttenuationInverse with a minimum distance of 100, a maximum distance of 2000 and a rolloff of 1 until
+//SetAttenuation is called.
+func InitEmitter(target *framework.Object, listener *Listener) *Emitter {
+	if target == nil || listener == nil {
+		panic("Invalid argument.  The target and listener cannot be nil")
+	}
+
+	e := &Emitter{
+		target:      target,
+		listener:    listener,
+		volume:      1,
+		model:       AttenuationInverse,
+		minDistance: 100,
+		maxDistance: 2000,
+		rolloff:     1,
+		voices:      make([]*Voice, 0),
+	}
+
+	target.AddMoveListener(e)
+	listener.emitters = append(listener.emitters, e)
+	return e
+}
+
+//Sets how the volume falls with the distance to the listener.
+//	*SetAttenuation(model, minDistance, maxDistance)
+//	*SetAttenuation(model, minDistance, maxDistance, rolloff)
+//Where:
+//	model is AttenuationNone, AttenuationLinear, AttenuationInverse or AttenuationExponential.
+//	minDistance is the distance the sounds start getting quieter from.  Anything closer is heard at full volume.
+//	maxDistance is the distance the sounds stop getting quieter at.  With AttenuationLinear, they are silent from there.
+//	rolloff is how fast the volume falls.  It is defaulted to 1.
+func (e *Emitter) SetAttenuation(model attenuation, minDistance, maxDistance float64, rolloff ...float64) {
+	if minDistance <= 0 || maxDistance < minDistance {
+		panic(fmt.Sprintf("Invalid argument.  Expected 0 < minDistance <= maxDistance, found %v and %v", minDistance, maxDistance))
+	}
+
+	r := 1.0
+	switch len(rolloff) {
+	case 0:
+	case 1:
+		r = rolloff[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one rolloff, found %v.", len(rolloff)))
+	}
+
+	e.model = model
+	e.minDistance = minDistance
+	e.maxDistance = maxDistance
+	e.rolloff = r
+	e.update()
+}
+
+//Sets the volume of the emitter before attenuation.  Set this instead of the volume of its voices, since those are
+//overwritten whenever something moves.
+func (e *Emitter) SetVolume(volume float64) {
+	e.volume = volume
+	e.update()
+}
+
+func (e *Emitter) GetVolume() float64 {
+	return e.volume
+}
+
+func (e *Emitter) GetTarget() *framework.Object {
+	return e.target
+}
+
+//Starts playing the source from the emitter on the bus and returns the voice playing it.
+func (e *Emitter) Play(source Source, b bus) *Voice {
+	if source == nil {
+		panic("Invalid argument.  The source cannot be nil")
+	}
+
+	//the voice is set up before the mixer sees it, so it is never heard from the wrong place
+	v := e.listener.mixer.newVoice(source, b)
+	v.volume, v.pan = e.mix()
+	e.listener.mixer.add(v)
+
+	e.voices = append(e.prune(), v)
+	return v
+}
+
+func (e *Emitter) ObjectMoved(o *framework.Object) {
+	e.update()
+}
+
+//Stops every voice of the emitter and detaches it from its object and listener.  The emitter cannot be used afterwards.
+func (e *Emitter) Remove() {
+	for _, v := range e.voices {
+		v.Stop()
+	}
+	e.voices = nil
+
+	e.target.RemoveMoveListener(e)
+	for i, n := range e.listener.emitters {
+		if n == e {
+			e.listener.emitters = append(e.listener.emitters[:i], e.listener.emitters[i+1:]...)
+			break
+		}
+	}
+}
+
+//Returns the gain for the distance to the listener
+func (e *Emitter) gain(distance float64) float64 {
+	d := math.Max(e.minDistance, math.Min(distance, e.maxDistance))
+
+	switch e.model {
+	case AttenuationLinear:
+		if e.maxDistance == e.minDistance {
+			return 1
+		}
+		return math.Max(0, 1-e.rolloff*(d-e.minDistance)/(e.maxDistance-e.minDistance))
+	case AttenuationInverse:
+		return e.minDistance / (e.minDistance + e.rolloff*(d-e.minDistance))
+	case AttenuationExponential:
+		return math.Pow(d/e.minDistance, -e.rolloff)
+	}
+	return 1
+}
+
+//Returns the volume and pan for the positions of the emitter and the listener
+func (e *Emitter) mix() (float64, float64) {
+	x, y := e.target.GetPosition()
+	dx := x - e.listener.x
+	dy := y - e.listener.y
+
+	return e.volume * e.gain(math.Hypot(dx, dy)), math.Max(-1, math.Min(1, dx/e.listener.panWidth))
+}
+
+//Updates the volume and pan of the voices, and drops the voices that have finished
+func (e *Emitter) update() {
+	volume, pan := e.mix()
+
+	e.voices = e.prune()
+	for _, v := range e.voices {
+		v.SetVolume(volume)
+		v.SetPan(pan)
+	}
+}
+
+//Returns the voices of the emitter that are not done, so an emitter that never moves does not keep every sound it played
+func (e *Emitter) prune() []*Voice {
+	playing := e.voices[:0]
+	for _, v := range e.voices {
+		if !v.IsDone() {
+			playing = append(playing, v)
+		}
+	}
+	return playing
+}
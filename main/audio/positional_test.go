@@ -0,0 +1,115 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+func TestEmitterMix(t *testing.T) {
+	cases := []struct {
+		name     string
+		model    attenuation
+		min      float64
+		max      float64
+		rolloff  float64
+		volume   float64
+		x        float64
+		y        float64
+		wantGain float64
+		wantPan  float64
+	}{
+		{
+			name: "none", model: AttenuationNone, min: 100, max: 1000, rolloff: 1, volume: 1,
+			x: 600, wantGain: 1, wantPan: .75,
+		},
+		{
+			name: "inside the minimum", model: AttenuationInverse, min: 100, max: 1000, rolloff: 1, volume: 1,
+			x: 60, y: 80, wantGain: 1, wantPan: .075,
+		},
+		{
+			name: "linear halfway", model: AttenuationLinear, min: 100, max: 1100, rolloff: 1, volume: 1,
+			x: -600, wantGain: .5, wantPan: -.75,
+		},
+		{
+			name: "linear past the maximum", model: AttenuationLinear, min: 100, max: 1100, rolloff: 1, volume: 1,
+			x: -2000, wantGain: 0, wantPan: -1,
+		},
+		{
+			name: "inverse", model: AttenuationInverse, min: 100, max: 2000, rolloff: 1, volume: 1,
+			y: 300, wantGain: 1. / 3, wantPan: 0,
+		},
+		{
+			name: "inverse with a rolloff", model: AttenuationInverse, min: 100, max: 2000, rolloff: 2, volume: 1,
+			y: 300, wantGain: .2, wantPan: 0,
+		},
+		{
+			name: "inverse held at the maximum", model: AttenuationInverse, min: 100, max: 300, rolloff: 1, volume: 1,
+			y: 900, wantGain: 1. / 3, wantPan: 0,
+		},
+		{
+			name: "exponential", model: AttenuationExponential, min: 100, max: 2000, rolloff: 1, volume: 1,
+			x: 400, wantGain: .25, wantPan: .5,
+		},
+		{
+			name: "the volume of the emitter", model: AttenuationExponential, min: 100, max: 2000, rolloff: 1, volume: .5,
+			x: 400, wantGain: .125, wantPan: .5,
+		},
+	}
+
+	for _, c := range cases {
+		m := InitMixer(InitNullOutput(100))
+		l := InitListener(m)
+		o := framework.InitObject(float32(1), float32(1))
+		e := InitEmitter(&o, l)
+		e.SetAttenuation(c.model, c.min, c.max, c.rolloff)
+		e.SetVolume(c.volume)
+
+		//the voice is placed when it starts, and again as the object moves
+		o.Move(c.x, c.y)
+		moved := e.Play(InitSoundFromSamples(make([]float32, 10), 1, 100), BusSE)
+		o.Move(0, 0)
+		o.Move(c.x, c.y)
+
+		for _, v := range []*Voice{moved, e.Play(InitSoundFromSamples(make([]float32, 10), 1, 100), BusSE)} {
+			if math.Abs(v.GetVolume()-c.wantGain) > 1e-9 || math.Abs(v.GetPan()-c.wantPan) > 1e-9 {
+				t.Errorf("%v: the voice has a volume of %v and a pan of %v, expected %v and %v", c.name, v.GetVolume(), v.GetPan(), c.wantGain, c.wantPan)
+			}
+		}
+	}
+}
+
+func TestEmitterFollowsListener(t *testing.T) {
+	m := InitMixer(InitNullOutput(100))
+	l := InitListener(m)
+	o := framework.InitObject(float32(1), float32(1))
+	e := InitEmitter(&o, l)
+	e.SetAttenuation(AttenuationLinear, 100, 1100, 1)
+	v := e.Play(InitSoundFromSamples(make([]float32, 10), 1, 100), BusSE)
+
+	camera := framework.InitObject(float32(1), float32(1))
+	l.Follow(&camera)
+	camera.Move(-600, 0)
+
+	if math.Abs(v.GetVolume()-.5) > 1e-9 || math.Abs(v.GetPan()-.75) > 1e-9 {
+		t.Errorf("the voice has a volume of %v and a pan of %v, expected .5 and .75", v.GetVolume(), v.GetPan())
+	}
+}
+
+func TestEmitterDropsFinishedVoices(t *testing.T) {
+	m := InitMixer(InitNullOutput(100))
+	o := framework.InitObject(float32(1), float32(1))
+	e := InitEmitter(&o, InitListener(m))
+
+	//an emitter that never moves keeps only the voices still playing
+	for i := 0; i < 100; i++ {
+		e.Play(InitSoundFromSamples(make([]float32, 2), 1, 100), BusSE)
+		if err := m.Render(4); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(e.voices) != 1 {
+		t.Errorf("expected the emitter to hold 1 voice, found %v", len(e.voices))
+	}
+}
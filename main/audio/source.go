@@ -0,0 +1,219 @@
+package audio
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/koinuri/game-project/main/asset"
+)
+
+//Audio that can be played by a voice of the mixer, such as a Sound or a Stream.
+type Source interface {
+	GetChannels() int
+	GetSampleRate() int
+	//Returns the length in frames, where a frame holds one sample of every channel
+	GetLength() int64
+	//Returns the length in seconds
+	GetDuration() float64
+	//Returns the first frame of the part repeated by looping voices and the frame after it, or 0 and 0 to repeat everything
+	GetLoopPoints() (int64, int64)
+	//Copies the frames from the frame given into p and returns how many were copied
+	readAt(p []float32, frame int64) int
+}
+
+//Audio decoded entirely into memory.  Meant for short sounds such as effects, and can be played by any number of voices
+//at once.
+type Sound struct {
+	samples    []float32
+	channels   int
+	sampleRate int
+	loopStart  int64
+	loopEnd    int64
+}
+
+//Loads and decodes a .wav or .ogg file.  Loop markers stored in the file, such as the sampler chunk of a .wav file or the
+//LOOPSTART and LOOPLENGTH comments of an .ogg file, become the loop points of the sound.
+//	*InitSound(dir)
+//Where:
+//	dir is the path of the file, relative to the directory of the game.
+func InitSound(dir string) *Sound {
+	d, file, err := openDecoder(dir)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the sound %v.\n%v", asset.Describe(dir), err))
+	}
+	defer file.Close()
+
+	channels, rate := d.format()
+	samples := make([]float32, 0, d.length()*int64(channels))
+	buffer := make([]float32, 4096*channels)
+	for {
+		n, err := d.read(buffer)
+		samples = append(samples, buffer[:n]...)
+		if err == io.EOF || (n == 0 && err == nil) {
+			break
+		}
+		if err != nil {
+			panic(fmt.Sprintf("Could not decode the sound %v.\n%v", asset.Describe(dir), err))
+		}
+	}
+
+	start, end := d.loopPoints()
+	return &Sound{samples, channels, rate, start, end}
+}
+
+//Creates a sound from samples already in memory, with the channels of a frame next to each other.
+func InitSoundFromSamples(samples []float32, channels, sampleRate int) *Sound {
+	if channels < 1 || sampleRate < 1 || len(samples)%channels != 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected whole frames of %v channels at a positive sample rate, found %v samples at %v", channels, len(samples), sampleRate))
+	}
+	return &Sound{samples, channels, sampleRate, 0, 0}
+}
+
+func (s *Sound) GetChannels() int {
+	return s.channels
+}
+
+func (s *Sound) GetSampleRate() int {
+	return s.sampleRate
+}
+
+func (s *Sound) GetLength() int64 {
+	return int64(len(s.samples) / s.channels)
+}
+
+func (s *Sound) GetDuration() float64 {
+	return float64(s.GetLength()) / float64(s.sampleRate)
+}
+
+func (s *Sound) GetLoopPoints() (int64, int64) {
+	return s.loopStart, s.loopEnd
+}
+
+//Sets the part repeated by looping voices, from the frame start up to but not including the frame end.  Whatever comes
+//before start plays once as an intro.  Voices started before the change keep the old loop points.
+func (s *Sound) SetLoopPoints(start, end int64) {
+	checkLoopPoints(s, start, end)
+	s.loopStart, s.loopEnd = start, end
+}
+
+func checkLoopPoints(s Source, start, end int64) {
+	if start < 0 || end <= start || end > s.GetLength() {
+		panic(fmt.Sprintf("Invalid argument.  Expected 0 <= start < end <= %v, found %v and %v", s.GetLength(), start, end))
+	}
+}
+
+func (s *Sound) readAt(p []float32, frame int64) int {
+	start := frame * int64(s.channels)
+	if frame < 0 || start >= int64(len(s.samples)) {
+		return 0
+	}
+	return copy(p, s.samples[start:]) / s.channels
+}
+
+//Number of frames decoded at a time by a stream
+const streamBlock = 4096
+
+//Audio decoded from its file a little at a time while it plays.  Meant for long tracks such as music.  A stream can be
+//played by several voices at once, but each of them makes the others seek, so load a Sound for that instead.
+type Stream struct {
+	decoder    decoder
+	file       io.Closer
+	channels   int
+	sampleRate int
+	cache      []float32
+	cacheStart int64
+	cacheLen   int
+	next       int64
+	loopStart  int64
+	loopEnd    int64
+}
+
+//Opens a .wav or .ogg file to be streamed.  Call Close once no voice plays it anymore.  Loop markers are read from the file
+//like InitSound does.
+//	*InitStream(dir)
+//Where:
+//	dir is the path of the file, relative to the directory of the game.
+func InitStream(dir string) *Stream {
+	d, file, err := openDecoder(dir)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the stream %v.\n%v", asset.Describe(dir), err))
+	}
+
+	channels, rate := d.format()
+	start, end := d.loopPoints()
+	return &Stream{
+		decoder:    d,
+		file:       file,
+		channels:   channels,
+		sampleRate: rate,
+		cache:      make([]float32, streamBlock*channels),
+		loopStart:  start,
+		loopEnd:    end,
+	}
+}
+
+func (s *Stream) GetChannels() int {
+	return s.channels
+}
+
+func (s *Stream) GetSampleRate() int {
+	return s.sampleRate
+}
+
+func (s *Stream) GetLength() int64 {
+	return s.decoder.length()
+}
+
+func (s *Stream) GetDuration() float64 {
+	return float64(s.GetLength()) / float64(s.sampleRate)
+}
+
+func (s *Stream) GetLoopPoints() (int64, int64) {
+	return s.loopStart, s.loopEnd
+}
+
+//Sets the part repeated by looping voices, like Sound.SetLoopPoints
+func (s *Stream) SetLoopPoints(start, end int64) {
+	checkLoopPoints(s, start, end)
+	s.loopStart, s.loopEnd = start, end
+}
+
+//Closes the file of the stream
+func (s *Stream) Close() error {
+	return s.file.Close()
+}
+
+func (s *Stream) readAt(p []float32, frame int64) int {
+	ch := s.channels
+	total := len(p) / ch
+	n := 0
+
+	for n < total {
+		f := frame + int64(n)
+
+		//the frames asked for usually overlap with the last block decoded, since the voices read one frame ahead
+		if f >= s.cacheStart && f < s.cacheStart+int64(s.cacheLen) {
+			from := int(f-s.cacheStart) * ch
+			n += copy(p[n*ch:total*ch], s.cache[from:s.cacheLen*ch]) / ch
+			continue
+		}
+
+		if f != s.next {
+			if s.decoder.seek(f) != nil {
+				break
+			}
+			s.next = f
+		}
+
+		//a read error shows up as nothing being read, which ends the voice like the end of the file
+		read, _ := s.decoder.read(s.cache)
+		s.cacheStart = f
+		s.cacheLen = read / ch
+		s.next += int64(s.cacheLen)
+		if s.cacheLen == 0 {
+			break
+		}
+	}
+
+	return n
+}
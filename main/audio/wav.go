@@ -0,0 +1,229 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	wavPCM        = 1
+	wavFloat      = 3
+	wavExtensible = 0xFFFE
+)
+
+//Decodes uncompressed .wav files holding 8, 16, 24 or 32 bit integers or 32 bit floats
+type wavDecoder struct {
+	file       io.ReadSeeker
+	channels   int
+	sampleRate int
+	bits       int
+	float      bool
+	blockAlign int
+	dataStart  int64
+	frames     int64
+	position   int64
+	loopStart  int64
+	loopEnd    int64
+	bytes      []byte
+}
+
+func newWavDecoder(file io.ReadSeeker) (*wavDecoder, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(file, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, errors.New("not a RIFF WAVE file")
+	}
+
+	d := &wavDecoder{file: file}
+	foundFormat := false
+	foundData := false
+
+	//walk through every chunk, since the loop markers may come after the samples
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(file, chunk[:]); err != nil {
+			break
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, errors.New("the fmt chunk is too short")
+			}
+			body := make([]byte, size)
+			if _, err := io.ReadFull(file, body); err != nil {
+				return nil, err
+			}
+			if err := d.readFormat(body); err != nil {
+				return nil, err
+			}
+			foundFormat = true
+		case "data":
+			if !foundFormat {
+				return nil, errors.New("the data chunk comes before the fmt chunk")
+			}
+			start, err := file.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+			d.dataStart = start
+			d.frames = size / int64(d.blockAlign)
+			foundData = true
+			if _, err := file.Seek(size, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+		case "smpl":
+			body := make([]byte, size)
+			if _, err := io.ReadFull(file, body); err != nil {
+				return nil, err
+			}
+			d.readLoop(body)
+		default:
+			if _, err := file.Seek(size, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+		}
+
+		//chunks are padded to an even size
+		if size%2 == 1 {
+			if _, err := file.Seek(1, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !foundData {
+		return nil, errors.New("no data chunk found")
+	}
+	if d.loopEnd > d.frames {
+		d.loopEnd = d.frames
+	}
+	if _, err := file.Seek(d.dataStart, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+//Reads the first loop of the sampler chunk, where the end of the loop is the last frame played rather than the one after it
+func (d *wavDecoder) readLoop(body []byte) {
+	if len(body) < 36+24 || binary.LittleEndian.Uint32(body[28:32]) == 0 {
+		return
+	}
+
+	start := int64(binary.LittleEndian.Uint32(body[44:48]))
+	end := int64(binary.LittleEndian.Uint32(body[48:52])) + 1
+	if end > start {
+		d.loopStart, d.loopEnd = start, end
+	}
+}
+
+func (d *wavDecoder) loopPoints() (int64, int64) {
+	return d.loopStart, d.loopEnd
+}
+
+func (d *wavDecoder) readFormat(body []byte) error {
+	tag := binary.LittleEndian.Uint16(body[0:2])
+	d.channels = int(binary.LittleEndian.Uint16(body[2:4]))
+	d.sampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+	d.blockAlign = int(binary.LittleEndian.Uint16(body[12:14]))
+	d.bits = int(binary.LittleEndian.Uint16(body[14:16]))
+
+	//the extensible format keeps the real format in the first two bytes of its sub format
+	if tag == wavExtensible && len(body) >= 26 {
+		tag = binary.LittleEndian.Uint16(body[24:26])
+	}
+
+	switch {
+	case tag == wavPCM && (d.bits == 8 || d.bits == 16 || d.bits == 24 || d.bits == 32):
+	case tag == wavFloat && d.bits == 32:
+		d.float = true
+	default:
+		return fmt.Errorf("unsupported sample format %v with %v bits", tag, d.bits)
+	}
+
+	if d.channels < 1 || d.sampleRate < 1 || d.blockAlign < d.channels*d.bits/8 {
+		return errors.New("invalid channels, sample rate or block size")
+	}
+	return nil
+}
+
+func (d *wavDecoder) format() (int, int) {
+	return d.channels, d.sampleRate
+}
+
+func (d *wavDecoder) length() int64 {
+	return d.frames
+}
+
+func (d *wavDecoder) read(p []float32) (int, error) {
+	frames := int64(len(p) / d.channels)
+	if left := d.frames - d.position; frames > left {
+		frames = left
+	}
+	if frames <= 0 {
+		return 0, io.EOF
+	}
+
+	size := int(frames) * d.blockAlign
+	if cap(d.bytes) < size {
+		d.bytes = make([]byte, size)
+	}
+	b := d.bytes[:size]
+
+	read, err := io.ReadFull(d.file, b)
+	frames = int64(read / d.blockAlign)
+	if err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+
+	width := d.bits / 8
+	for f := 0; f < int(frames); f++ {
+		for c := 0; c < d.channels; c++ {
+			p[f*d.channels+c] = d.sample(b[f*d.blockAlign+c*width:])
+		}
+	}
+
+	d.position += frames
+	if frames == 0 && err == nil {
+		err = io.EOF
+	}
+	return int(frames) * d.channels, err
+}
+
+//Converts one sample into a float within [-1, 1]
+func (d *wavDecoder) sample(b []byte) float32 {
+	switch d.bits {
+	case 8:
+		//8 bit samples are the only unsigned ones
+		return (float32(b[0]) - 128) / 128
+	case 16:
+		return float32(int16(binary.LittleEndian.Uint16(b))) / 32768
+	case 24:
+		v := int32(b[0]) | int32(b[1])<<8 | int32(b[2])<<16
+		v = v << 8 >> 8
+		return float32(v) / 8388608
+	}
+
+	if d.float {
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	}
+	return float32(int32(binary.LittleEndian.Uint32(b))) / 2147483648
+}
+
+func (d *wavDecoder) seek(frame int64) error {
+	if frame < 0 || frame > d.frames {
+		return fmt.Errorf("frame %v is outside of the file", frame)
+	}
+	if _, err := d.file.Seek(d.dataStart+frame*int64(d.blockAlign), io.SeekStart); err != nil {
+		return err
+	}
+	d.position = frame
+	return nil
+}
@@ -0,0 +1,127 @@
+package collision
+
+import (
+	"fmt"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Shape attached to an Object (or any other Transformable) that follows it around.
+type Collider struct {
+	target  framework.Transformable
+	shape   Shape
+	offsetx float64
+	offsety float64
+	onEnter func(other *Collider, penetration Vector)
+	onStay  func(other *Collider, penetration Vector)
+	onExit  func(other *Collider)
+}
+
+//Attaches the shape to the target.
+//	*InitCollider(target, shape)
+//	*InitCollider(target, shape, x, y)
+//Where:
+//	target is usually a pointer to an Object.  The shape follows its position, rotation and scale.
+//	shape is one of the AABB, Rectangle, Circle or Polygon shapes.
+//	x and y move the shape away from the target's position, before rotation and scale are applied.
+func InitCollider(target framework.Transformable, shape Shape, offset ...float64) *Collider {
+	if target == nil || shape == nil {
+		panic("Invalid argument.  The target and the shape cannot be nil")
+	}
+
+	c := &Collider{target: target, shape: shape}
+
+	switch len(offset) {
+	case 0:
+	case 2:
+		c.offsetx = offset[0]
+		c.offsety = offset[1]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected 0 or 2 float64 for the offset, found %v.", len(offset)))
+	}
+
+	return c
+}
+
+func (c *Collider) GetTarget() framework.Transformable {
+	return c.target
+}
+
+func (c *Collider) GetShape() Shape {
+	return c.shape
+}
+
+//Sets the function called on the tick the collider starts overlapping another one.
+func (c *Collider) OnEnter(f func(other *Collider, penetration Vector)) {
+	c.onEnter = f
+}
+
+//Sets the function called on every following tick the collider keeps overlapping another one.
+func (c *Collider) OnStay(f func(other *Collider, penetration Vector)) {
+	c.onStay = f
+}
+
+//Sets the function called on the tick the collider stops overlapping another one.
+func (c *Collider) OnExit(f func(other *Collider)) {
+	c.onExit = f
+}
+
+//Returns the shape placed where the target currently is
+func (c *Collider) placed() placed {
+	x, y := c.target.GetPosition()
+	sx, sy := c.target.GetScale()
+	angle := c.target.GetAngle()
+
+	offset := Vector{c.offsetx * sx, c.offsety * sy}.Rotate(angle)
+
+	return c.shape.place(transform{
+		position: Vector{x, y}.Add(offset),
+		angle:    angle,
+		scalex:   sx,
+		scaley:   sy,
+	})
+}
+
+//Returns the smallest axis aligned rectangle holding the shape where the target currently is
+func (c *Collider) GetBounds() framework.Bound {
+	left, right, up, bottom := c.placed().extents()
+	return framework.Bound{
+		Left:   float32(left),
+		Right:  float32(right),
+		Up:     float32(up),
+		Bottom: float32(bottom),
+	}
+}
+
+//Tests whether the two colliders overlap.  If they do, the penetration is the shortest vector that moves a out of b.
+func Overlap(a, b *Collider) (Vector, bool) {
+	if !a.GetBounds().Overlaps(b.GetBounds()) {
+		return Vector{}, false
+	}
+	return intersect(a.placed(), b.placed())
+}
+
+//Tests whether the point is inside the collider.
+func (c *Collider) Contains(x, y float64) bool {
+	p := c.placed()
+	point := Vector{x, y}
+
+	if p.isCircle {
+		return point.Sub(p.center).Len() <= p.radius
+	}
+
+	//the point is inside a convex polygon if it is on the same side of every edge
+	sign := 0.0
+	for i, v := range p.points {
+		next := p.points[(i+1)%len(p.points)]
+		cross := next.Sub(v).Cross(point.Sub(v))
+		if cross == 0 {
+			continue
+		}
+		if sign != 0 && (cross > 0) != (sign > 0) {
+			return false
+		}
+		sign = cross
+	}
+	return true
+}
@@ -0,0 +1,233 @@
+package collision
+
+import (
+	"fmt"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+type quadNode struct {
+	bounds   framework.Bound
+	depth    int
+	items    []Bounded
+	children []*quadNode
+}
+
+//Index that splits crowded areas into four smaller areas.  Works well when the items are spread unevenly or differ a lot in
+//size.  Items outside the area of the tree are kept in its root.
+type Quadtree struct {
+	root     *quadNode
+	capacity int
+	maxDepth int
+	nodes    map[Bounded]*quadNode
+	watchers map[Bounded]*watcher
+}
+
+//Creates a quadtree.
+//	*InitQuadtree(bound)
+//	*InitQuadtree(bound, capacity, maxDepth)
+//Where:
+//	bound is the area the tree covers, such as the bounds of the level.
+//	capacity is how many items an area holds before it is split.  It is defaulted to 8.
+//	maxDepth is how many times an area can be split.  It is defaulted to 8.
+func InitQuadtree(bound framework.Bound, i ...int) *Quadtree {
+	capacity := 8
+	maxDepth := 8
+
+	switch len(i) {
+	case 0:
+	case 2:
+		capacity = i[0]
+		maxDepth = i[1]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected 0 or 2 int, found %v.", len(i)))
+	}
+
+	if capacity < 1 || maxDepth < 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a capacity of 1 or more and a depth of 0 or more, found %v and %v", capacity, maxDepth))
+	}
+
+	return &Quadtree{
+		&quadNode{bounds: bound, items: make([]Bounded, 0)},
+		capacity,
+		maxDepth,
+		make(map[Bounded]*quadNode),
+		make(map[Bounded]*watcher),
+	}
+}
+
+//Returns true if the inner bound is entirely inside the outer bound
+func holds(outer, inner framework.Bound) bool {
+	return inner.Left >= outer.Left && inner.Right <= outer.Right && inner.Bottom >= outer.Bottom && inner.Up <= outer.Up
+}
+
+func (n *quadNode) split() {
+	b := n.bounds
+	midx := (b.Left + b.Right) / 2
+	midy := (b.Up + b.Bottom) / 2
+
+	quarters := []framework.Bound{
+		{Left: b.Left, Right: midx, Up: b.Up, Bottom: midy},
+		{Left: midx, Right: b.Right, Up: b.Up, Bottom: midy},
+		{Left: b.Left, Right: midx, Up: midy, Bottom: b.Bottom},
+		{Left: midx, Right: b.Right, Up: midy, Bottom: b.Bottom},
+	}
+
+	n.children = make([]*quadNode, 4)
+	for i, q := range quarters {
+		n.children[i] = &quadNode{bounds: q, depth: n.depth + 1, items: make([]Bounded, 0)}
+	}
+}
+
+//Returns the child that entirely holds the bound, or nil if it sits across several of them
+func (n *quadNode) childFor(b framework.Bound) *quadNode {
+	for _, c := range n.children {
+		if holds(c.bounds, b) {
+			return c
+		}
+	}
+	return nil
+}
+
+//Puts the item in the deepest node holding it, splitting nodes that get too crowded
+func (t *Quadtree) place(n *quadNode, item Bounded) {
+	b := item.GetBounds()
+
+	for n.children != nil {
+		child := n.childFor(b)
+		if child == nil {
+			break
+		}
+		n = child
+	}
+
+	n.items = append(n.items, item)
+	t.nodes[item] = n
+
+	if n.children == nil && len(n.items) > t.capacity && n.depth < t.maxDepth {
+		n.split()
+
+		//move down the items that fit in a child
+		items := n.items
+		n.items = make([]Bounded, 0)
+		for _, it := range items {
+			if child := n.childFor(it.GetBounds()); child != nil {
+				t.place(child, it)
+			} else {
+				n.items = append(n.items, it)
+				t.nodes[it] = n
+			}
+		}
+	}
+}
+
+//Adds the item.  Items following an Object are updated every time the object moves.
+func (t *Quadtree) Insert(item Bounded) {
+	if _, succ := t.nodes[item]; succ {
+		t.Update(item)
+		return
+	}
+
+	t.place(t.root, item)
+	t.watchers[item] = watch(t, item)
+}
+
+func (t *Quadtree) unplace(item Bounded) {
+	n := t.nodes[item]
+	for i, it := range n.items {
+		if it == item {
+			n.items = append(n.items[:i], n.items[i+1:]...)
+			break
+		}
+	}
+	delete(t.nodes, item)
+}
+
+func (t *Quadtree) Remove(item Bounded) {
+	if _, succ := t.nodes[item]; !succ {
+		return
+	}
+
+	t.unplace(item)
+	unwatch(item, t.watchers[item])
+	delete(t.watchers, item)
+}
+
+func (t *Quadtree) Update(item Bounded) {
+	n, succ := t.nodes[item]
+	if !succ {
+		return
+	}
+
+	//the item can stay if it still fits in its node and cannot go any deeper
+	b := item.GetBounds()
+	if (n == t.root || holds(n.bounds, b)) && (n.children == nil || n.childFor(b) == nil) {
+		return
+	}
+
+	t.unplace(item)
+	t.place(t.root, item)
+}
+
+//Calls f on every item of the nodes accepted by visit
+func (n *quadNode) walk(visit func(n *quadNode) bool, f func(item Bounded)) {
+	if !visit(n) {
+		return
+	}
+
+	for _, item := range n.items {
+		f(item)
+	}
+	for _, c := range n.children {
+		c.walk(visit, f)
+	}
+}
+
+func (t *Quadtree) QueryRegion(b framework.Bound) []Bounded {
+	found := make([]Bounded, 0)
+
+	t.root.walk(func(n *quadNode) bool {
+		return n == t.root || n.bounds.Overlaps(b)
+	}, func(item Bounded) {
+		if item.GetBounds().Overlaps(b) {
+			found = append(found, item)
+		}
+	})
+
+	return found
+}
+
+func (t *Quadtree) QueryPoint(x, y float64) []Bounded {
+	found := make([]Bounded, 0)
+
+	t.root.walk(func(n *quadNode) bool {
+		return n == t.root || n.bounds.Contains(float32(x), float32(y))
+	}, func(item Bounded) {
+		if item.GetBounds().Contains(float32(x), float32(y)) {
+			found = append(found, item)
+		}
+	})
+
+	return found
+}
+
+func (t *Quadtree) Raycast(origin, direction Vector, length float64) []Bounded {
+	dir := direction.Normalize()
+	if dir.Len() == 0 {
+		return t.QueryPoint(origin.X, origin.Y)
+	}
+
+	items := make([]Bounded, 0)
+
+	t.root.walk(func(n *quadNode) bool {
+		if n == t.root {
+			return true
+		}
+		_, hit := rayBound(origin, dir, length, n.bounds)
+		return hit
+	}, func(item Bounded) {
+		items = append(items, item)
+	})
+
+	return sortRayHits(origin, dir, length, items)
+}
@@ -0,0 +1,264 @@
+package collision
+
+import (
+	"fmt"
+	"math"
+)
+
+//Position, rotation and scale a shape is placed with in the window
+type transform struct {
+	position Vector
+	angle    float64
+	scalex   float64
+	scaley   float64
+}
+
+//Shape of a collider, described around (0, 0) in the collider's own space.
+type Shape interface {
+	//Returns the shape placed in the window
+	place(t transform) placed
+}
+
+//Shape placed in the window.  Every shape ends up as either a circle or a convex polygon.
+type placed struct {
+	center   Vector
+	radius   float64
+	points   []Vector
+	isCircle bool
+}
+
+//Rectangle that never rotates, even when the collider's target does.
+type AABB struct {
+	width  float64
+	height float64
+}
+
+func InitAABB(width, height float64) *AABB {
+	if width <= 0 || height <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive size, found %v by %v", width, height))
+	}
+	return &AABB{width, height}
+}
+
+func (a *AABB) place(t transform) placed {
+	w := a.width / 2 * math.Abs(t.scalex)
+	h := a.height / 2 * math.Abs(t.scaley)
+	c := t.position
+
+	return placed{
+		center: c,
+		points: []Vector{
+			{c.X - w, c.Y - h},
+			{c.X + w, c.Y - h},
+			{c.X + w, c.Y + h},
+			{c.X - w, c.Y + h},
+		},
+	}
+}
+
+//Rectangle that rotates with the collider's target.
+type Rectangle struct {
+	width  float64
+	height float64
+}
+
+func InitRectangle(width, height float64) *Rectangle {
+	if width <= 0 || height <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive size, found %v by %v", width, height))
+	}
+	return &Rectangle{width, height}
+}
+
+func (r *Rectangle) place(t transform) placed {
+	w := r.width / 2
+	h := r.height / 2
+
+	return placePoints([]Vector{{-w, -h}, {w, -h}, {w, h}, {-w, h}}, t)
+}
+
+//Circle that grows with the larger of the collider's target's scales.
+type Circle struct {
+	radius float64
+}
+
+func InitCircle(radius float64) *Circle {
+	if radius <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive radius, found %v", radius))
+	}
+	return &Circle{radius}
+}
+
+func (c *Circle) place(t transform) placed {
+	scale := math.Max(math.Abs(t.scalex), math.Abs(t.scaley))
+	return placed{
+		center:   t.position,
+		radius:   c.radius * scale,
+		isCircle: true,
+	}
+}
+
+//Convex polygon that rotates with the collider's target.
+type Polygon struct {
+	points []Vector
+}
+
+//Creates a convex polygon from at least three points, given in clockwise or counter clockwise order.
+func InitPolygon(points ...Vector) *Polygon {
+	if len(points) < 3 {
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at least 3 points, found %v", len(points)))
+	}
+
+	//every turn along a convex polygon goes the same way
+	sign := 0.0
+	for i := range points {
+		a := points[i]
+		b := points[(i+1)%len(points)]
+		c := points[(i+2)%len(points)]
+
+		cross := b.Sub(a).Cross(c.Sub(b))
+		if cross == 0 {
+			continue
+		}
+		if sign != 0 && (cross > 0) != (sign > 0) {
+			panic("Invalid argument.  The polygon is not convex")
+		}
+		sign = cross
+	}
+	if sign == 0 {
+		panic("Invalid argument.  The points of the polygon are all on one line")
+	}
+
+	p := make([]Vector, len(points))
+	copy(p, points)
+	return &Polygon{p}
+}
+
+func (p *Polygon) place(t transform) placed {
+	return placePoints(p.points, t)
+}
+
+//Scales, rotates, then moves the points
+func placePoints(points []Vector, t transform) placed {
+	world := make([]Vector, len(points))
+	center := Vector{}
+	for i, p := range points {
+		world[i] = Vector{p.X * t.scalex, p.Y * t.scaley}.Rotate(t.angle).Add(t.position)
+		center = center.Add(world[i])
+	}
+
+	return placed{
+		center: center.Scale(1 / float64(len(points))),
+		points: world,
+	}
+}
+
+//Returns the left, right, top and bottom of the shape
+func (p placed) extents() (float64, float64, float64, float64) {
+	if p.isCircle {
+		return p.center.X - p.radius, p.center.X + p.radius, p.center.Y + p.radius, p.center.Y - p.radius
+	}
+
+	left, right := math.Inf(1), math.Inf(-1)
+	up, bottom := math.Inf(-1), math.Inf(1)
+	for _, v := range p.points {
+		left = math.Min(left, v.X)
+		right = math.Max(right, v.X)
+		up = math.Max(up, v.Y)
+		bottom = math.Min(bottom, v.Y)
+	}
+	return left, right, up, bottom
+}
+
+//Returns the lowest and highest value of the shape projected onto the axis
+func (p placed) project(axis Vector) (float64, float64) {
+	if p.isCircle {
+		c := p.center.Dot(axis)
+		return c - p.radius, c + p.radius
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range p.points {
+		d := v.Dot(axis)
+		min = math.Min(min, d)
+		max = math.Max(max, d)
+	}
+	return min, max
+}
+
+//Returns the normals of every edge of a polygon
+func (p placed) axes() []Vector {
+	if p.isCircle {
+		return nil
+	}
+
+	axes := make([]Vector, len(p.points))
+	for i, v := range p.points {
+		next := p.points[(i+1)%len(p.points)]
+		axes[i] = next.Sub(v).Perp().Normalize()
+	}
+	return axes
+}
+
+//Returns the vertex of the polygon closest to the point
+func (p placed) closest(v Vector) Vector {
+	best := p.points[0]
+	for _, pt := range p.points[1:] {
+		if pt.Sub(v).Len() < best.Sub(v).Len() {
+			best = pt
+		}
+	}
+	return best
+}
+
+//Tests the two shapes with the separating axis theorem.  Returns the shortest vector that moves a out of b, and whether they
+//overlap at all.
+func intersect(a, b placed) (Vector, bool) {
+	if a.isCircle && b.isCircle {
+		d := a.center.Sub(b.center)
+		dist := d.Len()
+		depth := a.radius + b.radius - dist
+		if depth <= 0 {
+			return Vector{}, false
+		}
+		if dist == 0 {
+			return Vector{depth, 0}, true
+		}
+		return d.Scale(depth / dist), true
+	}
+
+	axes := append(a.axes(), b.axes()...)
+
+	//a circle can only be separated from a polygon along the polygon's edges or towards its closest vertex
+	if a.isCircle {
+		axes = append(axes, a.center.Sub(b.closest(a.center)).Normalize())
+	} else if b.isCircle {
+		axes = append(axes, b.center.Sub(a.closest(b.center)).Normalize())
+	}
+
+	depth := math.Inf(1)
+	var normal Vector
+	for _, axis := range axes {
+		if axis.Len() == 0 {
+			continue
+		}
+
+		minA, maxA := a.project(axis)
+		minB, maxB := b.project(axis)
+		overlap := math.Min(maxA, maxB) - math.Max(minA, minB)
+		if overlap <= 0 {
+			return Vector{}, false
+		}
+
+		if overlap < depth {
+			depth = overlap
+			normal = axis
+		}
+	}
+
+	//make the normal point from b towards a
+	if a.center.Sub(b.center).Dot(normal) < 0 {
+		normal = normal.Scale(-1)
+	}
+
+	return normal.Scale(depth), true
+}
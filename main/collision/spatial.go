@@ -0,0 +1,123 @@
+package collision
+
+import (
+	"math"
+	"sort"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Anything that covers an area of the window, such as an Object or a Collider.
+type Bounded interface {
+	GetBounds() framework.Bound
+}
+
+//Finds the items in an area without testing every item.  Both SpatialHash and Quadtree follow it.
+type Index interface {
+	Insert(item Bounded)
+	Remove(item Bounded)
+	//Moves the item to where its bounds are now
+	Update(item Bounded)
+	//Returns every item whose bounds overlap the bound
+	QueryRegion(b framework.Bound) []Bounded
+	//Returns every item whose bounds hold the point
+	QueryPoint(x, y float64) []Bounded
+	//Returns every item whose bounds the ray crosses within length, closest first.  direction does not need to be normalized.
+	Raycast(origin, direction Vector, length float64) []Bounded
+}
+
+//Keeps an item up to date in an index while the object it follows moves
+type watcher struct {
+	index Index
+	item  Bounded
+}
+
+func (w *watcher) ObjectMoved(o *framework.Object) {
+	w.index.Update(w.item)
+}
+
+//Returns the object that moves the item, if there is one
+func followedObject(item Bounded) *framework.Object {
+	switch i := item.(type) {
+	case *framework.Object:
+		return i
+	case *Collider:
+		obj, _ := i.target.(*framework.Object)
+		return obj
+	}
+	return nil
+}
+
+//Tells the index about every move of the object the item follows, and returns the watcher to stop it with
+func watch(index Index, item Bounded) *watcher {
+	obj := followedObject(item)
+	if obj == nil {
+		return nil
+	}
+
+	w := &watcher{index, item}
+	obj.AddMoveListener(w)
+	return w
+}
+
+func unwatch(item Bounded, w *watcher) {
+	if obj := followedObject(item); obj != nil && w != nil {
+		obj.RemoveMoveListener(w)
+	}
+}
+
+//Returns the distance along the ray where it enters the bound, using the slab method
+func rayBound(origin, direction Vector, length float64, b framework.Bound) (float64, bool) {
+	near := 0.0
+	far := length
+
+	slabs := [2][3]float64{
+		{origin.X, direction.X, 0},
+		{origin.Y, direction.Y, 0},
+	}
+	lows := [2]float64{float64(b.Left), float64(b.Bottom)}
+	highs := [2]float64{float64(b.Right), float64(b.Up)}
+
+	for i, s := range slabs {
+		o, d := s[0], s[1]
+		if d == 0 {
+			if o < lows[i] || o > highs[i] {
+				return 0, false
+			}
+			continue
+		}
+
+		t1 := (lows[i] - o) / d
+		t2 := (highs[i] - o) / d
+		near = math.Max(near, math.Min(t1, t2))
+		far = math.Min(far, math.Max(t1, t2))
+		if near > far {
+			return 0, false
+		}
+	}
+
+	return near, true
+}
+
+//Keeps the items the ray hits, sorted from the closest
+func sortRayHits(origin, direction Vector, length float64, items []Bounded) []Bounded {
+	dir := direction.Normalize()
+	dist := make(map[Bounded]float64)
+	hits := make([]Bounded, 0)
+
+	for _, item := range items {
+		if d, hit := rayBound(origin, dir, length, item.GetBounds()); hit {
+			dist[item] = d
+			hits = append(hits, item)
+		}
+	}
+
+	sort.SliceStable(hits, func(a, b int) bool {
+		return dist[hits[a]] < dist[hits[b]]
+	})
+	return hits
+}
+
+func pointBound(x, y float64) framework.Bound {
+	return framework.Bound{Left: float32(x), Right: float32(x), Up: float32(y), Bottom: float32(y)}
+}
@@ -0,0 +1,264 @@
+package collision
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Side of the square area the benchmarks spread their colliders over
+const benchArea = 4000
+
+var benchCounts = []int{100, 500, 2000}
+
+//Index testing every item, which the spatial hash and the quadtree are measured against
+type bruteIndex struct {
+	items []Bounded
+}
+
+func (b *bruteIndex) Insert(item Bounded) {
+	b.items = append(b.items, item)
+}
+
+func (b *bruteIndex) Remove(item Bounded) {
+	for i, n := range b.items {
+		if n == item {
+			b.items = append(b.items[:i], b.items[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *bruteIndex) Update(item Bounded) {}
+
+func (b *bruteIndex) QueryRegion(bound framework.Bound) []Bounded {
+	found := make([]Bounded, 0)
+	for _, item := range b.items {
+		if item.GetBounds().Overlaps(bound) {
+			found = append(found, item)
+		}
+	}
+	return found
+}
+
+func (b *bruteIndex) QueryPoint(x, y float64) []Bounded {
+	found := make([]Bounded, 0)
+	for _, item := range b.items {
+		if item.GetBounds().Contains(float32(x), float32(y)) {
+			found = append(found, item)
+		}
+	}
+	return found
+}
+
+func (b *bruteIndex) Raycast(origin, direction Vector, length float64) []Bounded {
+	return sortRayHits(origin, direction, length, b.items)
+}
+
+var benchIndexes = []struct {
+	name string
+	init func() Index
+}{
+	{"brute", func() Index { return &bruteIndex{} }},
+	{"hash", func() Index { return InitSpatialHash(64) }},
+	{"quadtree", func() Index {
+		return InitQuadtree(framework.Bound{Left: 0, Right: benchArea, Up: benchArea, Bottom: 0})
+	}},
+}
+
+//Returns n colliders from 8 to 40 wide following objects spread over the area, the same ones for the same n
+func benchColliders(n int) ([]*Collider, []*framework.Object) {
+	r := rand.New(rand.NewSource(int64(n)))
+	colliders := make([]*Collider, n)
+	objects := make([]*framework.Object, n)
+
+	for i := range colliders {
+		o := framework.InitObject(float32(1), float32(1))
+		o.Move(r.Float64()*benchArea, r.Float64()*benchArea)
+		objects[i] = &o
+		colliders[i] = InitCollider(objects[i], InitAABB(8+r.Float64()*32, 8+r.Float64()*32))
+	}
+	return colliders, objects
+}
+
+//Returns n random queries over the area, the same ones every time
+func benchPoints(n int) []Vector {
+	r := rand.New(rand.NewSource(-1))
+	points := make([]Vector, n)
+	for i := range points {
+		points[i] = Vector{r.Float64() * benchArea, r.Float64() * benchArea}
+	}
+	return points
+}
+
+func benchIndex(b *testing.B, query func(index Index, p Vector)) {
+	points := benchPoints(256)
+
+	for _, n := range benchCounts {
+		colliders, _ := benchColliders(n)
+		for _, bi := range benchIndexes {
+			index := bi.init()
+			for _, c := range colliders {
+				index.Insert(c)
+			}
+
+			b.Run(fmt.Sprintf("%v/%v", bi.name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					query(index, points[i%len(points)])
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkQueryRegion(b *testing.B) {
+	benchIndex(b, func(index Index, p Vector) {
+		index.QueryRegion(framework.Bound{
+			Left:   float32(p.X - 100),
+			Right:  float32(p.X + 100),
+			Up:     float32(p.Y + 100),
+			Bottom: float32(p.Y - 100),
+		})
+	})
+}
+
+func BenchmarkQueryPoint(b *testing.B) {
+	benchIndex(b, func(index Index, p Vector) {
+		index.QueryPoint(p.X, p.Y)
+	})
+}
+
+func BenchmarkRaycast(b *testing.B) {
+	benchIndex(b, func(index Index, p Vector) {
+		index.Raycast(p, Vector{benchArea/2 - p.X, benchArea/2 - p.Y}, 1000)
+	})
+}
+
+//Finds the overlapping pairs with the world testing every collider against every other collider, and with each index
+func benchPairs(b *testing.B, moving bool) {
+	for _, n := range benchCounts {
+		indexes := append([]struct {
+			name string
+			init func() Index
+		}{{"naive", func() Index { return nil }}}, benchIndexes[1:]...)
+
+		for _, bi := range indexes {
+			colliders, objects := benchColliders(n)
+			w := InitWorld()
+			w.SetIndex(bi.init())
+			for _, c := range colliders {
+				w.Add(c)
+			}
+
+			b.Run(fmt.Sprintf("%v/%v", bi.name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if moving {
+						//every object steps back and forth, so they stay spread the same way
+						step := 2.0
+						if i%2 == 1 {
+							step = -2
+						}
+						for _, o := range objects {
+							x, y := o.GetPosition()
+							o.Move(x+step, y)
+						}
+					}
+					w.Update()
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkPairs(b *testing.B) {
+	benchPairs(b, false)
+}
+
+func BenchmarkPairsMoving(b *testing.B) {
+	benchPairs(b, true)
+}
+
+//The indexes must find the same items as testing every item, or the benchmarks compare different work
+func TestIndexesMatchBruteForce(t *testing.T) {
+	colliders, objects := benchColliders(500)
+	points := benchPoints(64)
+
+	indexes := make([]Index, len(benchIndexes))
+	for i, bi := range benchIndexes {
+		indexes[i] = bi.init()
+		for _, c := range colliders {
+			indexes[i].Insert(c)
+		}
+	}
+
+	//move, grow and turn some of the objects after inserting them, which the indexes only learn about through the objects
+	for i, o := range objects {
+		switch i % 4 {
+		case 0:
+			x, y := o.GetPosition()
+			o.Move(benchArea-x, y)
+		case 1:
+			o.Scale(4)
+		case 2:
+			o.RadianRotate(.5)
+		}
+	}
+
+	for _, p := range points {
+		region := framework.Bound{
+			Left:   float32(p.X - 100),
+			Right:  float32(p.X + 100),
+			Up:     float32(p.Y + 100),
+			Bottom: float32(p.Y - 100),
+		}
+		want := indexes[0].QueryRegion(region)
+		for i, index := range indexes[1:] {
+			if got := index.QueryRegion(region); !sameItems(got, want) {
+				t.Fatalf("%v found %v items in %v, expected %v", benchIndexes[i+1].name, len(got), region, len(want))
+			}
+		}
+
+		want = indexes[0].Raycast(p, Vector{1, 1}, 1000)
+		for i, index := range indexes[1:] {
+			got := index.Raycast(p, Vector{1, 1}, 1000)
+			if !sameItems(got, want) {
+				t.Fatalf("%v hit %v items from %v, expected %v", benchIndexes[i+1].name, len(got), p, len(want))
+			}
+			if !closestFirst(p, Vector{1, 1}, got) {
+				t.Fatalf("%v did not return the items hit from %v closest first", benchIndexes[i+1].name, p)
+			}
+		}
+	}
+}
+
+//Returns true if the ray reaches every item no later than the item after it
+func closestFirst(origin, direction Vector, items []Bounded) bool {
+	last := 0.0
+	for _, item := range items {
+		d, hit := rayBound(origin, direction.Normalize(), math.Inf(1), item.GetBounds())
+		if !hit || d < last {
+			return false
+		}
+		last = d
+	}
+	return true
+}
+
+func sameItems(a, b []Bounded) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	in := make(map[Bounded]bool)
+	for _, item := range a {
+		in[item] = true
+	}
+	for _, item := range b {
+		if !in[item] {
+			return false
+		}
+	}
+	return true
+}
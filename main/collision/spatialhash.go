@@ -0,0 +1,207 @@
+package collision
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+type cell struct {
+	x int
+	y int
+}
+
+//Index that splits the window into a grid of equal cells.  Works best when the items are about the size of a cell.
+type SpatialHash struct {
+	size     float64
+	cells    map[cell][]Bounded
+	items    map[Bounded][]cell
+	watchers map[Bounded]*watcher
+}
+
+//Creates a spatial hash whose cells are size by size.
+func InitSpatialHash(size float64) *SpatialHash {
+	if size <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive cell size, found %v", size))
+	}
+
+	return &SpatialHash{
+		size,
+		make(map[cell][]Bounded),
+		make(map[Bounded][]cell),
+		make(map[Bounded]*watcher),
+	}
+}
+
+//Returns the cell holding the point
+func (h *SpatialHash) cellAt(x, y float64) cell {
+	return cell{int(math.Floor(x / h.size)), int(math.Floor(y / h.size))}
+}
+
+//Returns every cell the bound touches
+func (h *SpatialHash) cellsOf(b framework.Bound) []cell {
+	min := h.cellAt(float64(b.Left), float64(b.Bottom))
+	max := h.cellAt(float64(b.Right), float64(b.Up))
+
+	cells := make([]cell, 0, (max.x-min.x+1)*(max.y-min.y+1))
+	for x := min.x; x <= max.x; x++ {
+		for y := min.y; y <= max.y; y++ {
+			cells = append(cells, cell{x, y})
+		}
+	}
+	return cells
+}
+
+//Adds the item.  Items following an Object are updated every time the object moves.
+func (h *SpatialHash) Insert(item Bounded) {
+	if _, succ := h.items[item]; succ {
+		h.Update(item)
+		return
+	}
+
+	h.place(item)
+	h.watchers[item] = watch(h, item)
+}
+
+func (h *SpatialHash) place(item Bounded) {
+	cells := h.cellsOf(item.GetBounds())
+	for _, c := range cells {
+		h.cells[c] = append(h.cells[c], item)
+	}
+	h.items[item] = cells
+}
+
+func (h *SpatialHash) unplace(item Bounded) {
+	for _, c := range h.items[item] {
+		list := h.cells[c]
+		for i, n := range list {
+			if n == item {
+				list = append(list[:i], list[i+1:]...)
+				break
+			}
+		}
+
+		if len(list) == 0 {
+			delete(h.cells, c)
+		} else {
+			h.cells[c] = list
+		}
+	}
+	delete(h.items, item)
+}
+
+func (h *SpatialHash) Remove(item Bounded) {
+	if _, succ := h.items[item]; !succ {
+		return
+	}
+
+	h.unplace(item)
+	unwatch(item, h.watchers[item])
+	delete(h.watchers, item)
+}
+
+func (h *SpatialHash) Update(item Bounded) {
+	old, succ := h.items[item]
+	if !succ {
+		return
+	}
+
+	//most moves stay within the same cells, so only replace the item when they change
+	cells := h.cellsOf(item.GetBounds())
+	if cells[0] == old[0] && cells[len(cells)-1] == old[len(old)-1] {
+		return
+	}
+
+	h.unplace(item)
+	h.place(item)
+}
+
+//Returns the items in the cells, each one once
+func (h *SpatialHash) collect(cells []cell) []Bounded {
+	seen := make(map[Bounded]bool)
+	items := make([]Bounded, 0)
+
+	for _, c := range cells {
+		for _, item := range h.cells[c] {
+			if !seen[item] {
+				seen[item] = true
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+func (h *SpatialHash) QueryRegion(b framework.Bound) []Bounded {
+	found := make([]Bounded, 0)
+	for _, item := range h.collect(h.cellsOf(b)) {
+		if item.GetBounds().Overlaps(b) {
+			found = append(found, item)
+		}
+	}
+	return found
+}
+
+func (h *SpatialHash) QueryPoint(x, y float64) []Bounded {
+	found := make([]Bounded, 0)
+	for _, item := range h.cells[h.cellAt(x, y)] {
+		if item.GetBounds().Contains(float32(x), float32(y)) {
+			found = append(found, item)
+		}
+	}
+	return found
+}
+
+func (h *SpatialHash) Raycast(origin, direction Vector, length float64) []Bounded {
+	dir := direction.Normalize()
+	if dir.Len() == 0 {
+		return h.QueryPoint(origin.X, origin.Y)
+	}
+
+	//walk through the cells the ray crosses, one cell border at a time
+	current := h.cellAt(origin.X, origin.Y)
+	end := h.cellAt(origin.X+dir.X*length, origin.Y+dir.Y*length)
+	cells := []cell{current}
+
+	stepx, stepy := 1, 1
+	if dir.X < 0 {
+		stepx = -1
+	}
+	if dir.Y < 0 {
+		stepy = -1
+	}
+
+	//distance along the ray to the next vertical and horizontal cell border, and between two borders
+	nextx, nexty := math.Inf(1), math.Inf(1)
+	deltax, deltay := math.Inf(1), math.Inf(1)
+	if dir.X != 0 {
+		border := float64(current.x) * h.size
+		if stepx > 0 {
+			border += h.size
+		}
+		nextx = (border - origin.X) / dir.X
+		deltax = h.size / math.Abs(dir.X)
+	}
+	if dir.Y != 0 {
+		border := float64(current.y) * h.size
+		if stepy > 0 {
+			border += h.size
+		}
+		nexty = (border - origin.Y) / dir.Y
+		deltay = h.size / math.Abs(dir.Y)
+	}
+
+	for current != end && math.Min(nextx, nexty) <= length {
+		if nextx < nexty {
+			current.x += stepx
+			nextx += deltax
+		} else {
+			current.y += stepy
+			nexty += deltay
+		}
+		cells = append(cells, current)
+	}
+
+	return sortRayHits(origin, dir, length, h.collect(cells))
+}
@@ -0,0 +1,54 @@
+package collision
+
+import "math"
+
+//Two dimensional vector used for positions, normals and penetrations.
+type Vector struct {
+	X float64
+	Y float64
+}
+
+func (v Vector) Add(o Vector) Vector {
+	return Vector{v.X + o.X, v.Y + o.Y}
+}
+
+func (v Vector) Sub(o Vector) Vector {
+	return Vector{v.X - o.X, v.Y - o.Y}
+}
+
+func (v Vector) Scale(s float64) Vector {
+	return Vector{v.X * s, v.Y * s}
+}
+
+func (v Vector) Dot(o Vector) float64 {
+	return v.X*o.X + v.Y*o.Y
+}
+
+//Returns the z component of the three dimensional cross product
+func (v Vector) Cross(o Vector) float64 {
+	return v.X*o.Y - v.Y*o.X
+}
+
+func (v Vector) Len() float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y)
+}
+
+//Returns the vector with a length of 1, or the zero vector if it has no length
+func (v Vector) Normalize() Vector {
+	l := v.Len()
+	if l == 0 {
+		return Vector{}
+	}
+	return Vector{v.X / l, v.Y / l}
+}
+
+//Returns the vector turned 90 degrees counter clockwise
+func (v Vector) Perp() Vector {
+	return Vector{-v.Y, v.X}
+}
+
+//Returns the vector rotated counter clockwise by the angle in radians
+func (v Vector) Rotate(angle float64) Vector {
+	sin, cos := math.Sincos(angle)
+	return Vector{v.X*cos - v.Y*sin, v.X*sin + v.Y*cos}
+}
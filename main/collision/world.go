@@ -0,0 +1,193 @@
+package collision
+
+type pair struct {
+	a *Collider
+	b *Collider
+}
+
+//Holds the colliders that can hit each other and reports when they start, keep, and stop overlapping.
+type World struct {
+	colliders []*Collider
+	order     map[*Collider]int
+	added     int
+	contacts  map[pair]bool
+	index     Index
+}
+
+func InitWorld() *World {
+	return &World{
+		make([]*Collider, 0),
+		make(map[*Collider]int),
+		0,
+		make(map[pair]bool),
+		nil,
+	}
+}
+
+//Sets the index used to find the colliders near each other, such as a SpatialHash or a Quadtree.  Without one, every
+//collider is tested against every other collider.
+func (w *World) SetIndex(index Index) {
+	if w.index != nil {
+		for _, c := range w.colliders {
+			w.index.Remove(c)
+		}
+	}
+
+	w.index = index
+	if index != nil {
+		for _, c := range w.colliders {
+			index.Insert(c)
+		}
+	}
+}
+
+func (w *World) Add(c *Collider) {
+	for _, n := range w.colliders {
+		if n == c {
+			return
+		}
+	}
+	w.order[c] = w.added
+	w.added++
+	w.colliders = append(w.colliders, c)
+
+	if w.index != nil {
+		w.index.Insert(c)
+	}
+}
+
+//Removes the collider from the world, firing the exit events of everything it was touching.
+func (w *World) Remove(c *Collider) {
+	for i, n := range w.colliders {
+		if n == c {
+			w.colliders = append(w.colliders[:i], w.colliders[i+1:]...)
+			break
+		}
+	}
+	delete(w.order, c)
+
+	if w.index != nil {
+		w.index.Remove(c)
+	}
+
+	for p := range w.contacts {
+		if p.a == c || p.b == c {
+			delete(w.contacts, p)
+			exit(p)
+		}
+	}
+}
+
+func (w *World) GetColliders() []*Collider {
+	return w.colliders
+}
+
+//Returns every pair of colliders that may overlap, with the collider added first on the left
+func (w *World) candidates() []pair {
+	pairs := make([]pair, 0)
+
+	if w.index == nil {
+		for i, a := range w.colliders {
+			for _, b := range w.colliders[i+1:] {
+				pairs = append(pairs, pair{a, b})
+			}
+		}
+		return pairs
+	}
+
+	for _, a := range w.colliders {
+		//colliders following an object are updated by the index when it moves, so only the others are brought up to date
+		if followedObject(a) == nil {
+			w.index.Update(a)
+		}
+	}
+
+	for _, a := range w.colliders {
+		for _, item := range w.index.QueryRegion(a.GetBounds()) {
+			//the index may be shared with things that are not colliders of this world
+			b, succ := item.(*Collider)
+			if !succ {
+				continue
+			}
+			if order, added := w.order[b]; added && w.order[a] < order {
+				pairs = append(pairs, pair{a, b})
+			}
+		}
+	}
+	return pairs
+}
+
+//Tests every collider against each other and fires the enter, stay and exit events.  Call it once per tick, after moving the
+//objects.
+func (w *World) Update() {
+	current := make(map[pair]bool)
+
+	for _, p := range w.candidates() {
+		penetration, hit := Overlap(p.a, p.b)
+		if !hit {
+			continue
+		}
+		current[p] = true
+
+		if w.contacts[p] {
+			if p.a.onStay != nil {
+				p.a.onStay(p.b, penetration)
+			}
+			if p.b.onStay != nil {
+				p.b.onStay(p.a, penetration.Scale(-1))
+			}
+		} else {
+			if p.a.onEnter != nil {
+				p.a.onEnter(p.b, penetration)
+			}
+			if p.b.onEnter != nil {
+				p.b.onEnter(p.a, penetration.Scale(-1))
+			}
+		}
+	}
+
+	for p := range w.contacts {
+		if !current[p] {
+			exit(p)
+		}
+	}
+
+	w.contacts = current
+}
+
+func exit(p pair) {
+	if p.a.onExit != nil {
+		p.a.onExit(p.b)
+	}
+	if p.b.onExit != nil {
+		p.b.onExit(p.a)
+	}
+}
+
+//Returns every collider overlapping the one given, with the vectors that move the collider out of each of them.
+func (w *World) Query(c *Collider) ([]*Collider, []Vector) {
+	hits := make([]*Collider, 0)
+	penetrations := make([]Vector, 0)
+
+	others := w.colliders
+	if w.index != nil {
+		others = make([]*Collider, 0)
+		for _, item := range w.index.QueryRegion(c.GetBounds()) {
+			if other, succ := item.(*Collider); succ {
+				others = append(others, other)
+			}
+		}
+	}
+
+	for _, other := range others {
+		if other == c {
+			continue
+		}
+		if penetration, hit := Overlap(c, other); hit {
+			hits = append(hits, other)
+			penetrations = append(penetrations, penetration)
+		}
+	}
+
+	return hits, penetrations
+}
@@ -0,0 +1,223 @@
+package framework
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/koinuri/game-project/main/asset"
+)
+
+//Sprite that flips through the frames of an animated GIF.  Every frame is a texture of its own, shown with the same
+//vertices, so it moves, scales and tints like any other sprite.  It is an Animation, so it can be played by an Animator.
+type AnimatedSprite struct {
+	Sprite
+	playback
+	textures []uint32
+	delays   []float64
+	frame    int
+	elapsed  float64
+}
+
+//Creates a sprite playing an animated GIF.
+//	*InitAnimatedSprite(dir)
+//	*InitAnimatedSprite(dir, Canvas)
+//	*InitAnimatedSprite(dir, origin)
+//	*InitAnimatedSprite(dir, Canvas, origin)
+//Any of them can end with TextureOptions.
+//Where:
+//	dir is the location of the GIF, relative to the executable file.
+//The sprite repeats as many times as the GIF says to, which can be changed with SetRepeat.
+func InitAnimatedSprite(i ...interface{}) *AnimatedSprite {
+	if len(i) == 0 {
+		panic("Invalid number of arguments.  Expected at least the location of the GIF")
+	}
+	dir, succ := i[0].(string)
+	if !succ {
+		panic(fmt.Sprintf("Invalid argument.  Expected string, got %T", i[0]))
+	}
+
+	var or origin = 4
+	var canvas Canvas
+	options := GetDefaultTextureOptions()
+	for _, item := range i[1:] {
+		switch v := item.(type) {
+		case origin:
+			or = v
+		case Canvas:
+			canvas = v
+		case TextureOptions:
+			options = withDefaults(v)
+		default:
+			panic(fmt.Sprintf("Invalid argument.  Expected Canvas, origin or TextureOptions, got %T", item))
+		}
+	}
+
+	frames, delays, loops, err := decodeGif(dir)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the file %v.\n%v", asset.Describe(dir), err))
+	}
+
+	textures := make([]uint32, len(frames))
+	var width, height int32
+	for f, frame := range frames {
+		var pix []uint8
+		pix, width, height = rasterize(frame, options.Premultiply)
+		textures[f] = createTexture(pix, width, height, options)
+	}
+
+	a := &AnimatedSprite{
+		Sprite:   createSprite(textures[0], float32(width), float32(height), canvas, or, mgl32.Vec4{0, 0, 1, 1}),
+		textures: textures,
+		delays:   delays,
+	}
+	if options.Premultiply {
+		a.blend = BlendPremultiplied
+		a.premultiplied = true
+	}
+	WatchAsset(dir, func() error {
+		return a.reload(dir, options)
+	})
+
+	//GIFs count the times played after the first, like SetRepeat, except that 0 means forever and -1 means once
+	switch {
+	case loops == 0:
+		a.SetRepeat(-1)
+	case loops > 0:
+		a.SetRepeat(loops)
+	}
+
+	return a
+}
+
+//Decodes every frame of the GIF as it is seen, with the frames before it drawn underneath as the GIF asks, along with how
+//many seconds each frame is shown and the loop count of the GIF
+func decodeGif(dir string) ([]image.Image, []float64, int, error) {
+	file, err := asset.Open(dir)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer file.Close()
+
+	g, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(g.Image) == 0 {
+		return nil, nil, 0, fmt.Errorf("the GIF has no frames")
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	canvas := image.NewNRGBA(bounds)
+	frames := make([]image.Image, len(g.Image))
+	delays := make([]float64, len(g.Image))
+
+	for f, img := range g.Image {
+		disposal := byte(0)
+		if f < len(g.Disposal) {
+			disposal = g.Disposal[f]
+		}
+
+		var previous *image.NRGBA
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewNRGBA(bounds)
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, img.Bounds(), img, img.Bounds().Min, draw.Over)
+		frame := image.NewNRGBA(bounds)
+		copy(frame.Pix, canvas.Pix)
+		frames[f] = frame
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, img.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+
+		//browsers show frames without a delay for a tenth of a second, so do the same
+		delays[f] = .1
+		if f < len(g.Delay) && g.Delay[f] > 0 {
+			delays[f] = float64(g.Delay[f]) / 100
+		}
+	}
+
+	return frames, delays, g.LoopCount, nil
+}
+
+//Uploads the frames of the GIF again, keeping the frame shown
+func (a *AnimatedSprite) reload(dir string, options TextureOptions) error {
+	frames, delays, _, err := decodeGif(dir)
+	if err != nil {
+		return err
+	}
+	if len(frames) != len(a.textures) {
+		return fmt.Errorf("the GIF has %v frames instead of %v, so the game has to be restarted", len(frames), len(a.textures))
+	}
+
+	for f, frame := range frames {
+		pix, width, height := rasterize(frame, options.Premultiply)
+		uploadTexture(a.textures[f], pix, width, height, options)
+	}
+	a.delays = delays
+	return nil
+}
+
+//Advances the animation by dt seconds.
+func (a *AnimatedSprite) Update(dt float64) float64 {
+	if a.done {
+		return dt
+	}
+
+	a.elapsed += dt
+	for a.elapsed >= a.delays[a.frame] {
+		a.elapsed -= a.delays[a.frame]
+		a.frame++
+
+		if a.frame >= len(a.textures) {
+			if !a.nextRepeat() {
+				//stay on the last frame once finished
+				a.frame = len(a.textures) - 1
+				a.texture = a.textures[a.frame]
+				leftover := a.elapsed
+				a.elapsed = 0
+				a.finish()
+				return leftover
+			}
+			a.frame = 0
+		}
+	}
+
+	a.texture = a.textures[a.frame]
+	return 0
+}
+
+//Rewinds the animation to its first frame.
+func (a *AnimatedSprite) Reset() {
+	a.frame = 0
+	a.elapsed = 0
+	a.played = 0
+	a.done = false
+	a.texture = a.textures[0]
+}
+
+//Shows the frame given and starts timing it from the beginning
+func (a *AnimatedSprite) SetFrame(frame int) {
+	if frame < 0 || frame >= len(a.textures) {
+		panic(fmt.Sprintf("Invalid argument.  Expected a frame within [0, %v), found %v", len(a.textures), frame))
+	}
+	a.frame = frame
+	a.elapsed = 0
+	a.texture = a.textures[frame]
+}
+
+func (a *AnimatedSprite) GetFrame() int {
+	return a.frame
+}
+
+func (a *AnimatedSprite) GetFrameCount() int {
+	return len(a.textures)
+}
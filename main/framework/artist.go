@@ -6,5 +6,19 @@ import "github.com/go-gl/mathgl/mgl32"
 type Artist interface {
 	GetDrawInfo() (uint32, uint32)
 	GetTransformation() mgl32.Mat4
-	applyTransformations(x, y, scalex, scaley, angle float32) Artist
+	GetTint() mgl32.Vec4
+	GetBlendMode() blendMode
+	GetMaterial() *Material
+	getMeshes() []mesh
+	applyTransformations(x, y, scalex, scaley, angle float32, tint mgl32.Vec4) Artist
+}
+
+//Vertex array drawn with one texture.  Sprites are a single quad, while tilemaps are made of many meshes.
+type mesh struct {
+	vao     uint32
+	texture uint32
+	count   int32
+	opacity float32
+	//true if the colors of the texture are already multiplied by their alpha, whichever blend mode it is drawn with
+	premultiplied bool
 }
@@ -0,0 +1,28 @@
+package framework
+
+import "github.com/go-gl/gl/v4.5-core/gl"
+
+type blendMode uint32
+
+const (
+	BlendNormal blendMode = iota
+	BlendAdditive
+	BlendMultiply
+	BlendScreen
+	BlendPremultiplied
+)
+
+//Sets the OpenGL blend function for the mode.  The fragment shader always outputs colors premultiplied by alpha, so every
+//mode uses the premultiplied form of its blend function.
+func setBlendMode(b blendMode) {
+	switch b {
+	case BlendAdditive:
+		gl.BlendFunc(gl.ONE, gl.ONE)
+	case BlendMultiply:
+		gl.BlendFunc(gl.DST_COLOR, gl.ONE_MINUS_SRC_ALPHA)
+	case BlendScreen:
+		gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_COLOR)
+	default:
+		gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	}
+}
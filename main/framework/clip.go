@@ -0,0 +1,60 @@
+package framework
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Artist drawn only within a rectangle, such as the contents of a scrolling panel
+type clippedArtist struct {
+	Artist
+	clip Bound
+}
+
+//Returns the artist drawn only where it is inside the bound, given in the window's 1600 by 900 coordinate system.  Clipping
+//an artist that is already clipped keeps the area inside both bounds.
+func Clip(a Artist, b Bound) Artist {
+	if c, succ := a.(*clippedArtist); succ {
+		return &clippedArtist{c.Artist, intersect(c.clip, b)}
+	}
+	return &clippedArtist{a, b}
+}
+
+func intersect(a, b Bound) Bound {
+	return Bound{
+		float32(math.Max(float64(a.Left), float64(b.Left))),
+		float32(math.Min(float64(a.Right), float64(b.Right))),
+		float32(math.Min(float64(a.Up), float64(b.Up))),
+		float32(math.Max(float64(a.Bottom), float64(b.Bottom))),
+	}
+}
+
+//The clip is in the coordinates of the window, so it stays where it is when the artist is placed in an object
+func (c *clippedArtist) applyTransformations(x, y, scalex, scaley, angle float32, tint mgl32.Vec4) Artist {
+	return &clippedArtist{c.Artist.applyTransformations(x, y, scalex, scaley, angle, tint), c.clip}
+}
+
+//Limits drawing to the bound, turning it into pixels of the framebuffer through the projection in use
+func scissor(b Bound) {
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+
+	p1 := projection.Mul4x1(mgl32.Vec4{b.Left, b.Bottom, 0, 1})
+	p2 := projection.Mul4x1(mgl32.Vec4{b.Right, b.Up, 0, 1})
+
+	//from [-1, 1] to the pixels of the viewport, in whichever order the projection puts them
+	x1 := float64(viewport[0]) + float64(p1[0]+1)/2*float64(viewport[2])
+	x2 := float64(viewport[0]) + float64(p2[0]+1)/2*float64(viewport[2])
+	y1 := float64(viewport[1]) + float64(p1[1]+1)/2*float64(viewport[3])
+	y2 := float64(viewport[1]) + float64(p2[1]+1)/2*float64(viewport[3])
+
+	left := math.Round(math.Min(x1, x2))
+	bottom := math.Round(math.Min(y1, y2))
+	width := math.Max(0, math.Round(math.Max(x1, x2))-left)
+	height := math.Max(0, math.Round(math.Max(y1, y2))-bottom)
+
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(int32(left), int32(bottom), int32(width), int32(height))
+}
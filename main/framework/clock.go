@@ -0,0 +1,79 @@
+package framework
+
+import "fmt"
+
+//Splits the time between frames into steps of a fixed length, so simulations such as physics behave the same at any frame
+//rate.
+type Clock struct {
+	step        float64
+	accumulator float64
+	last        float64
+	maxSteps    int
+	started     bool
+}
+
+//Creates a clock.
+//	*InitClock(step)
+//	*InitClock(step, maxSteps)
+//Where:
+//	step is the length of one step in seconds, such as 1.0 / 60.
+//	maxSteps is the most steps a single Tick returns, so a long stall does not make the game spend the next frames catching
+//	up.  It is defaulted to 5.
+func InitClock(step float64, maxSteps ...int) *Clock {
+	if step <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive step, found %v", step))
+	}
+
+	max := 5
+	switch len(maxSteps) {
+	case 0:
+	case 1:
+		max = maxSteps[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one int, found %v.", len(maxSteps)))
+	}
+
+	return &Clock{step: step, maxSteps: max}
+}
+
+//Advances the clock to the current time and returns how many steps have to be run.  Call it once per frame.
+func (c *Clock) Tick() int {
+	now := GetTime()
+	if !c.started {
+		c.started = true
+		c.last = now
+	}
+
+	dt := now - c.last
+	c.last = now
+
+	return c.Advance(dt)
+}
+
+//Advances the clock by dt seconds and returns how many steps have to be run.
+func (c *Clock) Advance(dt float64) int {
+	c.accumulator += dt
+
+	steps := 0
+	for c.accumulator >= c.step {
+		c.accumulator -= c.step
+		steps++
+	}
+
+	//drop the time that cannot be caught up with
+	if c.maxSteps > 0 && steps > c.maxSteps {
+		steps = c.maxSteps
+	}
+
+	return steps
+}
+
+//Returns the length of one step in seconds
+func (c *Clock) GetStep() float64 {
+	return c.step
+}
+
+//Returns how far the clock is into the next step, within [0, 1), for blending between the last two steps when drawing
+func (c *Clock) GetAlpha() float64 {
+	return c.accumulator / c.step
+}
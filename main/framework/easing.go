@@ -0,0 +1,108 @@
+package framework
+
+import "math"
+
+//Function that maps the progress of an animation in [0, 1] to the progress of the animated value.  Most curves stay within [0, 1],
+//but elastic and back curves overshoot on purpose.
+type Easing func(t float64) float64
+
+const (
+	backOvershoot   = 1.70158
+	elasticPeriod   = 0.3
+	bounceMagnitude = 7.5625
+)
+
+func Linear(t float64) float64 {
+	return t
+}
+
+func QuadIn(t float64) float64 {
+	return t * t
+}
+
+func QuadOut(t float64) float64 {
+	return t * (2 - t)
+}
+
+func QuadInOut(t float64) float64 {
+	return inOut(QuadIn, t)
+}
+
+func CubicIn(t float64) float64 {
+	return t * t * t
+}
+
+func CubicOut(t float64) float64 {
+	return outOf(CubicIn, t)
+}
+
+func CubicInOut(t float64) float64 {
+	return inOut(CubicIn, t)
+}
+
+func ElasticIn(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+
+	s := elasticPeriod / 4
+	return -math.Pow(2, 10*(t-1)) * math.Sin((t-1-s)*(2*math.Pi)/elasticPeriod)
+}
+
+func ElasticOut(t float64) float64 {
+	return outOf(ElasticIn, t)
+}
+
+func ElasticInOut(t float64) float64 {
+	return inOut(ElasticIn, t)
+}
+
+func BounceOut(t float64) float64 {
+	//the curve is made of four parabolas, each one smaller than the previous
+	switch {
+	case t < 1/2.75:
+		return bounceMagnitude * t * t
+	case t < 2/2.75:
+		t -= 1.5 / 2.75
+		return bounceMagnitude*t*t + 0.75
+	case t < 2.5/2.75:
+		t -= 2.25 / 2.75
+		return bounceMagnitude*t*t + 0.9375
+	default:
+		t -= 2.625 / 2.75
+		return bounceMagnitude*t*t + 0.984375
+	}
+}
+
+func BounceIn(t float64) float64 {
+	return outOf(BounceOut, t)
+}
+
+func BounceInOut(t float64) float64 {
+	return inOut(BounceIn, t)
+}
+
+func BackIn(t float64) float64 {
+	return t * t * ((backOvershoot+1)*t - backOvershoot)
+}
+
+func BackOut(t float64) float64 {
+	return outOf(BackIn, t)
+}
+
+func BackInOut(t float64) float64 {
+	return inOut(BackIn, t)
+}
+
+//Mirrors an ease-in curve into an ease-out curve (and the other way around)
+func outOf(e Easing, t float64) float64 {
+	return 1 - e(1-t)
+}
+
+//Plays the ease-in curve for the first half and the mirrored curve for the second half
+func inOut(e Easing, t float64) float64 {
+	if t < 0.5 {
+		return e(t*2) / 2
+	}
+	return 1 - e((1-t)*2)/2
+}
@@ -0,0 +1,152 @@
+package framework
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/koinuri/game-project/main/asset"
+)
+
+type watchedAsset struct {
+	modTime time.Time
+	size    int64
+	reloads []func() error
+}
+
+//Assets checked for changes, only filled while hot reloading is enabled so a release build does not keep them alive
+var hotReload = struct {
+	mutex    sync.Mutex
+	enabled  bool
+	interval float64
+	last     float64
+	assets   map[string]*watchedAsset
+}{assets: make(map[string]*watchedAsset)}
+
+//Reloads asset files as they change, for development.  Textures, shaders and timelines loaded afterwards are reloaded in
+//place, so the sprites and materials using them keep their transformations and values.  A texture whose size changes is
+//stretched over the sprite it was loaded for, since the sprite keeps its size.  Other files can be watched with
+//WatchAsset.  Call UpdateHotReload once per frame on the main thread.
+//	*EnableHotReload()
+//	*EnableHotReload(interval)
+//Where:
+//	interval is the seconds between checks of the files.  It is defaulted to 0.5.
+//Assets loaded before it is enabled are not watched, so enable it before loading anything.
+func EnableHotReload(interval ...float64) {
+	i := .5
+	switch len(interval) {
+	case 0:
+	case 1:
+		i = interval[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one float64, found %v.", len(interval)))
+	}
+
+	hotReload.mutex.Lock()
+	defer hotReload.mutex.Unlock()
+	hotReload.enabled = true
+	hotReload.interval = i
+}
+
+//Stops watching the asset files and forgets every asset watched so far
+func DisableHotReload() {
+	hotReload.mutex.Lock()
+	defer hotReload.mutex.Unlock()
+	hotReload.enabled = false
+	hotReload.assets = make(map[string]*watchedAsset)
+}
+
+func IsHotReloading() bool {
+	hotReload.mutex.Lock()
+	defer hotReload.mutex.Unlock()
+	return hotReload.enabled
+}
+
+//Calls reload on the main thread whenever the file changes, such as to read a data file again.  An error returned by reload
+//or a panic in it is logged, and the game carries on with what it had.  It does nothing unless hot reloading is enabled.
+func WatchAsset(dir string, reload func() error) {
+	if reload == nil {
+		panic("Invalid argument.  The reload function cannot be nil")
+	}
+
+	hotReload.mutex.Lock()
+	defer hotReload.mutex.Unlock()
+	if !hotReload.enabled {
+		return
+	}
+
+	a, succ := hotReload.assets[dir]
+	if !succ {
+		a = &watchedAsset{}
+		//a file that cannot be found yet counts as changed once it appears
+		if info, err := asset.Stat(dir); err == nil {
+			a.modTime = info.ModTime()
+			a.size = info.Size()
+		}
+		hotReload.assets[dir] = a
+	}
+	a.reloads = append(a.reloads, reload)
+}
+
+//Checks the watched files for changes and reloads the ones that changed.  Call it once per frame on the main thread.
+func UpdateHotReload() {
+	hotReload.mutex.Lock()
+	now := GetTime()
+	if !hotReload.enabled || now-hotReload.last < hotReload.interval {
+		hotReload.mutex.Unlock()
+		return
+	}
+	hotReload.last = now
+
+	//reloads run without the lock, since they may load and watch more assets
+	changed := make(map[string][]func() error)
+	for dir, a := range hotReload.assets {
+		info, err := asset.Stat(dir)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Equal(a.modTime) && info.Size() == a.size {
+			continue
+		}
+
+		a.modTime = info.ModTime()
+		a.size = info.Size()
+		changed[dir] = append([]func() error(nil), a.reloads...)
+	}
+	hotReload.mutex.Unlock()
+
+	for dir, reloads := range changed {
+		failed := false
+		for _, reload := range reloads {
+			if err := runReload(reload); err != nil {
+				log.Printf("Could not reload \"%v\".\n%v", dir, err)
+				failed = true
+			}
+		}
+		if !failed {
+			log.Printf("Reloaded \"%v\"", dir)
+		}
+	}
+}
+
+func runReload(reload func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return reload()
+}
+
+//Uploads the image into the texture again whenever its file changes
+func watchTexture(texture uint32, dir string, options TextureOptions) {
+	WatchAsset(dir, func() error {
+		pix, width, height, err := loadPixels(dir, options.Premultiply)
+		if err != nil {
+			return err
+		}
+		uploadTexture(texture, pix, width, height, options)
+		return nil
+	})
+}
@@ -4,16 +4,39 @@ import (
 	"fmt"
 	"image"
 	"image/draw"
-	"image/png"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 	"math"
-	"os"
-	"path"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
-	"github.com/koinuri/game-project/main/global"
+	"github.com/koinuri/game-project/main/asset"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
 )
 
+//How an image is turned into a texture and sampled.  The zero value keeps the colors as they are in the file and samples
+//the texture as the default texture options do, see SetDefaultTextureOptions.
+type TextureOptions struct {
+	//Multiplies the colors by their alpha when loading, so the edges of transparent areas do not darken or fringe when the
+	//texture is scaled.  Sprites loaded with it are drawn with BlendPremultiplied, and are still known to be premultiplied
+	//when given another blend mode.
+	Premultiply bool
+	//Stores the colors as sRGB, so OpenGL turns them linear when sampling.  Only useful when drawing into a framebuffer that
+	//turns them back into sRGB.
+	SRGB bool
+	//How the pixels are blended when the texture is scaled
+	Filter textureFilter
+	//What is sampled past the edges of the texture
+	Wrap textureWrap
+	//Whether smaller copies of the texture are used when it is drawn scaled down
+	Mipmaps textureMipmaps
+	//How many samples are taken along textures seen at an angle, such as a floor tilted by a camera.  0 uses the default, 1
+	//turns it off, and values above what the driver supports are lowered to it.
+	Anisotropy float32
+}
+
 type Sprite struct {
 	x              float32
 	y              float32
@@ -24,9 +47,28 @@ type Sprite struct {
 	scalex         float32
 	scaley         float32
 	angle          float32
-	origin         origin
+	r              float32
+	g              float32
+	b              float32
+	alpha          float32
+	blend          blendMode
+	premultiplied  bool
+	material       *Material
+	px             float32
+	py             float32
+	ax             float32
+	ay             float32
 	vao            uint32
+	vbo            uint32
 	texture        uint32
+	texRect        mgl32.Vec4
+	texWidth       float32
+	texHeight      float32
+	fullWidth      float32
+	fullHeight     float32
+	crop           mgl32.Vec4
+	flipx          bool
+	flipy          bool
 	transformation transformation
 }
 
@@ -35,14 +77,41 @@ type Sprite struct {
 // 	* New(string, Canvas)
 //  * New(string, Origin)
 //	* New(string, Canvas, Origin)
+//Any of them can end with TextureOptions.
 //Where:
-//	* String is the location of the image, relative to the executable file.
+//	* String is the location of the image, relative to the executable file.  PNG, JPEG, GIF, BMP and WebP images can be
+//	loaded, as well as any other format registered with image.RegisterFormat.
 //	* Canvas is the container in which the image will be stored in.  It will be defaulted to the container holding the entire window if it has not been specified.
 //	* Origin is where the coordinate system for this image is based on.  For example, TopLeft places the origin on the top left corner of the image, so if the image is moved to (0, 0), the top left of the image will be placed at (0, 0).  It will be defaulted to the center of the image if it has not been specified.
+//The image is decoded before InitSprite returns, which stalls the frame for large images.  Loader.LoadSprite decodes it in
+//the background instead.
 func InitSprite(i ...interface{}) Sprite {
+	dir, canvas, or, options := spriteArguments(i)
+
+	//create an image, then create vao and texture based on that image
+	pix, width, height, err := loadPixels(dir, options.Premultiply)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the file %v.\nDoes it exist?  If so, is it in a supported format?\n%v", asset.Describe(dir), err))
+	}
+	spr := createTextureSprite(pix, width, height, canvas, or, options)
+	watchTexture(spr.texture, dir, options)
+	return spr
+}
+
+//Reads the arguments of InitSprite
+func spriteArguments(i []interface{}) (string, Canvas, origin, TextureOptions) {
 	var dir string
 	var or origin = 4
 	var canvas Canvas
+	options := GetDefaultTextureOptions()
+
+	//texture options may follow any of the forms below
+	if len(i) > 1 {
+		if o, succ := i[len(i)-1].(TextureOptions); succ {
+			options = withDefaults(o)
+			i = i[:len(i)-1]
+		}
+	}
 
 	//The string must be provided if it's more than one
 	if len(i) >= 1 {
@@ -90,15 +159,27 @@ func InitSprite(i ...interface{}) Sprite {
 		panic("Invalid number of arguments.  Could not match with any of the possible argument numbers")
 	}
 
-	//create an image, then create vao and texture based on that image
-	img, err := createImage(dir)
-	if err != nil {
-		panic(fmt.Sprintf("Could not load the file \"%v\".\nDoes it exist?  If so, is it in .png format?", path.Join(global.Directory, dir), err))
+	return dir, canvas, or, options
+}
+
+//Uploads the pixels into a texture and creates a sprite showing all of it
+func createTextureSprite(pix []uint8, width, height int32, canvas Canvas, or origin, options TextureOptions) Sprite {
+	texture := createTexture(pix, width, height, options)
+
+	spr := createSprite(texture, float32(width), float32(height), canvas, or, mgl32.Vec4{0, 0, 1, 1})
+	if options.Premultiply {
+		spr.blend = BlendPremultiplied
+		spr.premultiplied = true
 	}
-	vao := createVao(img, &canvas)
-	texture := createTexture(img)
+	return spr
+}
+
+//Creates a sprite showing the texture, where imgWidth and imgHeight are the size of the texture in pixels and texRect is the
+//part of the texture shown, as (left, top, right, bottom) texture coordinates
+func createSprite(texture uint32, imgWidth, imgHeight float32, canvas Canvas, or origin, texRect mgl32.Vec4) Sprite {
+	vao, vbo := createVao(imgWidth, imgHeight, &canvas, texRect)
 
-	width, height := findWidthAndHeight(float32(img.Rect.Size().X), float32(img.Rect.Size().Y), canvas.Width, canvas.Height)
+	width, height := findWidthAndHeight(imgWidth, imgHeight, canvas.Width, canvas.Height)
 
 	spr := Sprite{
 		x:              0.0,
@@ -110,95 +191,102 @@ func InitSprite(i ...interface{}) Sprite {
 		scalex:         1.0,
 		scaley:         1.0,
 		angle:          0.0,
-		origin:         or,
+		r:              1.0,
+		g:              1.0,
+		b:              1.0,
+		alpha:          1.0,
+		blend:          BlendNormal,
+		ax:             .5,
+		ay:             .5,
 		vao:            vao,
+		vbo:            vbo,
 		texture:        texture,
+		texRect:        texRect,
+		texWidth:       imgWidth,
+		texHeight:      imgHeight,
+		fullWidth:      width,
+		fullHeight:     height,
+		crop:           mgl32.Vec4{0, 0, 1, 1},
 		transformation: InitTransformation(),
 	}
 
+	spr.px, spr.py = or.pivot()
 	spr.updateOrigin()
 
 	return spr
 }
 
-func createImage(dir string) (*image.RGBA, error) {
-	//read the image file, return empty rgba if error
-	imgFile, err := os.Open(path.Join(global.Directory, dir))
+//Decodes the image file with whichever registered decoder recognizes it
+func createImage(dir string) (image.Image, error) {
+	imgFile, err := asset.Open(dir)
 	if err != nil {
-		a := image.Rectangle{image.Point{0, 0}, image.Point{0, 0}}
-		return image.NewRGBA(a), err
+		return nil, err
 	}
 	defer imgFile.Close()
 
-	//decode the image file to use, return empty rgba is error
-	img, err := png.Decode(imgFile)
-	if err != nil {
-		a := image.Rectangle{image.Point{0, 0}, image.Point{0, 0}}
-		return image.NewRGBA(a), err
-	}
-
-	//create a rgba canvas to draw the stuff on
-	rgba := image.NewRGBA(img.Bounds())
-
-	//draw the image file based on image file
-	draw.Draw(rgba, rgba.Bounds(), img, image.ZP, draw.Over)
-
-	return rgba, nil
+	img, _, err := image.Decode(imgFile)
+	return img, err
 }
 
-func (s *Sprite) updateOrigin() {
-	sx := s.width / 2 * s.scalex
-	sy := s.height / 2 * s.scaley
-
-	//based on the origin, get the constant to place origin (negative, 0, or positive)
-	detx := math.Mod(float64(s.origin), 3.0) - 1
-	dety := math.Floor(float64(s.origin)/3.0) - 1
-
-	//origin at angle 0
-	ox := s.x - sx*float32(detx)
-	oy := s.y - sy*float32(dety)
+//Returns the pixels of the image as 4 bytes of RGBA each, starting from the top left, with the colors either as they are or
+//multiplied by their alpha.
+func rasterize(img image.Image, premultiply bool) ([]uint8, int32, int32) {
+	b := img.Bounds()
+	rect := image.Rect(0, 0, b.Dx(), b.Dy())
+
+	//image.RGBA holds premultiplied colors and image.NRGBA holds them as they are, so drawing into either converts them
+	if premultiply {
+		rgba := image.NewRGBA(rect)
+		draw.Draw(rgba, rect, img, b.Min, draw.Src)
+		return rgba.Pix, int32(b.Dx()), int32(b.Dy())
+	}
 
-	//difference between (x,y) and (ox,oy) and distance based on the numbers calculated
-	dx := s.x - ox
-	dy := s.y - oy
-	d := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+	nrgba := image.NewNRGBA(rect)
+	draw.Draw(nrgba, rect, img, b.Min, draw.Src)
+	return nrgba.Pix, int32(b.Dx()), int32(b.Dy())
+}
 
-	var oa float64
+//Loads an image file into a texture without creating a sprite, for example to bind it to a material.
+//	*LoadTexture(dir)
+//	*LoadTexture(dir, TextureOptions)
+func LoadTexture(dir string, options ...TextureOptions) uint32 {
+	o := textureOptions(options)
 
-	if d > 0 {
-		oa = math.Atan(float64(dy / dx))
-	} else {
-		oa = 0
+	pix, width, height, err := loadPixels(dir, o.Premultiply)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the file %v.\n%v", asset.Describe(dir), err))
 	}
+	texture := createTexture(pix, width, height, o)
+	watchTexture(texture, dir, o)
+	return texture
+}
 
-	//The real origin using the angle provided
-	if s.angle != 0 {
-		ox = d*float32(math.Cos(float64(s.angle)+oa)) + s.x
-		oy = d*float32(math.Sin(float64(s.angle)+oa)) + s.y
+//Decodes the image file into pixels ready to be uploaded.  It does not touch OpenGL, so it can run on any goroutine.
+func loadPixels(dir string, premultiply bool) ([]uint8, int32, int32, error) {
+	img, err := createImage(dir)
+	if err != nil {
+		return nil, 0, 0, err
 	}
-
-	s.ox = ox
-	s.oy = oy
+	pix, width, height := rasterize(img, premultiply)
+	return pix, width, height, nil
 }
 
-func createVao(img *image.RGBA, canvas *Canvas) uint32 {
-	//calculate the image's x and y depending on image aspect ratio
-	var x float32
-	var y float32
+//Places the center of the sprite so its pivot is at its position, rotating and scaling it around the pivot
+func (s *Sprite) updateOrigin() {
+	s.ox, s.oy = pivotCenter(s.x, s.y, s.width*s.scalex, s.height*s.scaley, s.px, s.py, s.angle)
+}
 
+func createVao(imgWidth, imgHeight float32, canvas *Canvas, texRect mgl32.Vec4) (uint32, uint32) {
 	//The width and height of image within canvas
-	w, h := findWidthAndHeight(float32(img.Rect.Size().X), float32(img.Rect.Size().Y), canvas.Width, canvas.Height)
-	x = w / 2
-	y = h / 2
+	w, h := findWidthAndHeight(imgWidth, imgHeight, canvas.Width, canvas.Height)
+	vao, vbo, _ := createQuad(w, h, texRect)
+	return vao, vbo
+}
 
-	//create vertices based on the calculated x's and y's and the coordinate of image each vertices should be associated to
-	//translate the vectors based on the canvas x's and y's
-	var vec []float32 = []float32{
-		x * -1, y, 0, 0.0, 0.0, //top left
-		x, y, 0, 1.0, 0.0, //top right
-		x * -1, y * -1, 0, 0.0, 1.0, //bottom left
-		x, y * -1, 0, 1.0, 1.0, //bottom right
-	}
+//Creates the vao of a quad of the size given, centered on (0, 0), and returns it with the vbo holding its vertices and the
+//ebo holding its indices
+func createQuad(w, h float32, texRect mgl32.Vec4) (uint32, uint32, uint32) {
+	vec := quadVertices(w, h, texRect)
 
 	//the indices to create rectangles using the vectors
 	var ind []uint32 = []uint32{
@@ -230,7 +318,22 @@ func createVao(img *image.RGBA, canvas *Canvas) uint32 {
 	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 5*4, gl.PtrOffset(3*4))
 	gl.EnableVertexAttribArray(1)
 
-	return vao
+	return vao, vbo, ebo
+}
+
+//Returns the vertices of a quad of the size given, centered on (0, 0), showing the texRect part of the texture
+func quadVertices(w, h float32, texRect mgl32.Vec4) []float32 {
+	//calculate the image's x and y depending on image aspect ratio
+	x := w / 2
+	y := h / 2
+
+	//create vertices based on the calculated x's and y's and the coordinate of image each vertices should be associated to
+	return []float32{
+		x * -1, y, 0, texRect[0], texRect[1], //top left
+		x, y, 0, texRect[2], texRect[1], //top right
+		x * -1, y * -1, 0, texRect[0], texRect[3], //bottom left
+		x, y * -1, 0, texRect[2], texRect[3], //bottom right
+	}
 }
 
 func findWidthAndHeight(imgWidth, imgHeight, canvasWidth, canvasHeight float32) (float32, float32) {
@@ -249,45 +352,48 @@ func findWidthAndHeight(imgWidth, imgHeight, canvasWidth, canvasHeight float32)
 
 	return w, h
 }
-func createTexture(img *image.RGBA) uint32 {
+//Uploads the pixels, 4 bytes of RGBA each, into a new texture
+func createTexture(pix []uint8, width, height int32, options TextureOptions) uint32 {
 	//initiate texture
 	var texture uint32
 	gl.GenTextures(1, &texture)
-	gl.BindTexture(gl.TEXTURE_2D, texture)
-
-	//set how the texture behaves when the shape created by vao is larger than texture.  It shouldn't happen so just setting it to repeat
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.REPEAT)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.REPEAT)
-
-	//set how the texture should guess the pixels when shrinking/enlargening an image.  linear makes an average, blurry, nearest takes the nearest color, blocky
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	uploadTexture(texture, pix, width, height, options)
+	return texture
+}
 
-	gl.Enable(gl.BLEND)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+//Replaces the pixels of the texture, keeping its name so everything drawing it shows the new pixels
+func uploadTexture(texture uint32, pix []uint8, width, height int32, options TextureOptions) {
+	gl.BindTexture(gl.TEXTURE_2D, texture)
 
 	//bind the image to this texture
+	var format int32 = gl.RGBA8
+	if options.SRGB {
+		format = gl.SRGB8_ALPHA8
+	}
 	gl.TexImage2D(
 		gl.TEXTURE_2D,
 		0,
-		gl.RGBA,
-		int32(img.Rect.Size().X),
-		int32(img.Rect.Size().Y),
+		format,
+		width,
+		height,
 		0,
 		gl.RGBA,
 		gl.UNSIGNED_BYTE,
-		gl.Ptr(img.Pix))
+		gl.Ptr(pix))
 
-	//create mipmap, which makes smaller image crispy
-	gl.GenerateMipmap(gl.TEXTURE_2D)
-
-	return texture
+	//set how the texture should guess the pixels when shrinking/enlargening an image, after the image is there to build the
+	//mipmaps from
+	applySampling(options)
 }
 
 //Returns the vao and texture held by the sprite to draw
 func (s *Sprite) GetDrawInfo() (uint32, uint32) {
 	return s.vao, s.texture
 }
+func (s *Sprite) getMeshes() []mesh {
+	return []mesh{{s.vao, s.texture, 6, 1, s.premultiplied}}
+}
+
 func (s *Sprite) GetTransformation() mgl32.Mat4 {
 	s.updateOrigin()
 
@@ -295,6 +401,21 @@ func (s *Sprite) GetTransformation() mgl32.Mat4 {
 	return s.transformation.translation.Mul4(s.transformation.rotation.Mul4(s.transformation.scale))
 }
 
+//Returns the position of the sprite before its parent object's transformation is applied
+func (s *Sprite) GetPosition() (float64, float64) {
+	return float64(s.x), float64(s.y)
+}
+
+//Returns the horizontal and vertical scale of the sprite
+func (s *Sprite) GetScale() (float64, float64) {
+	return float64(s.scalex), float64(s.scaley)
+}
+
+//Returns the rotation of the sprite in radians
+func (s *Sprite) GetAngle() float64 {
+	return float64(s.angle)
+}
+
 func (s *Sprite) Move(x, y float64) {
 	s.x = float32(x)
 	s.y = float32(y)
@@ -327,6 +448,98 @@ func (s *Sprite) Scale(v ...float64) {
 	s.transformation.scale = mgl32.Diag4(mgl32.Vec4{x, y, 1, 1})
 }
 
+//Sets the point of the sprite that is placed at its position and that it rotates and scales around, where (0, 0) is the
+//top left corner and (1, 1) is the bottom right corner.  For example, (0.5, 0.9) keeps the feet of a character in place.
+//Values outside [0, 1] place the pivot outside the sprite.
+func (s *Sprite) SetPivot(x, y float64) {
+	s.px = float32(x)
+	s.py = float32(y)
+}
+
+func (s *Sprite) GetPivot() (float64, float64) {
+	return float64(s.px), float64(s.py)
+}
+
+//Sets the pivot to one of the nine origins, such as BottomCenter
+func (s *Sprite) SetOrigin(or origin) {
+	s.px, s.py = or.pivot()
+}
+
+//Sets the point of the rectangle of the object holding the sprite that the position of the sprite is measured from, where
+//(0, 0) is the top left corner and (1, 1) is the bottom right corner.  It is defaulted to the center.  A sprite anchored to
+//(1, 0) and moved to (-20, -20) stays 20 pixels inside the top right corner when the object is resized.
+func (s *Sprite) SetAnchor(x, y float64) {
+	s.ax = float32(x)
+	s.ay = float32(y)
+}
+
+func (s *Sprite) GetAnchor() (float64, float64) {
+	return float64(s.ax), float64(s.ay)
+}
+
+//Shows only part of the image, given in pixels from its top left corner.  The sprite shrinks to the part shown, keeping the
+//size of its pixels.  Copies made with Copy share the vertices of the sprite, so they are cropped as well.
+func (s *Sprite) Crop(x, y, width, height float64) {
+	if x < 0 || y < 0 || width <= 0 || height <= 0 || x+width > float64(s.texWidth) || y+height > float64(s.texHeight) {
+		panic(fmt.Sprintf("Invalid argument.  Expected a rectangle within the %vx%v image, found (%v, %v, %v, %v)", s.texWidth, s.texHeight, x, y, width, height))
+	}
+
+	s.crop = mgl32.Vec4{
+		float32(x) / s.texWidth,
+		float32(y) / s.texHeight,
+		float32(width) / s.texWidth,
+		float32(height) / s.texHeight,
+	}
+	s.updateVertices()
+}
+
+//Returns the part of the image shown, in pixels from its top left corner
+func (s *Sprite) GetCrop() (float64, float64, float64, float64) {
+	return float64(s.crop[0] * s.texWidth), float64(s.crop[1] * s.texHeight), float64(s.crop[2] * s.texWidth), float64(s.crop[3] * s.texHeight)
+}
+
+//Shows the whole image again
+func (s *Sprite) ResetCrop() {
+	s.crop = mgl32.Vec4{0, 0, 1, 1}
+	s.updateVertices()
+}
+
+//Mirrors the image horizontally, vertically or both.  The sprite keeps its pivot, so a character flipped to face the other
+//way keeps standing on the same spot.
+func (s *Sprite) SetFlip(horizontal, vertical bool) {
+	s.flipx = horizontal
+	s.flipy = vertical
+	s.updateVertices()
+}
+
+func (s *Sprite) GetFlip() (bool, bool) {
+	return s.flipx, s.flipy
+}
+
+//Rewrites the vertices for the crop and flip of the sprite
+func (s *Sprite) updateVertices() {
+	s.width = s.fullWidth * s.crop[2]
+	s.height = s.fullHeight * s.crop[3]
+
+	//the crop within the part of the texture the sprite was created with
+	r := s.texRect
+	left := r[0] + (r[2]-r[0])*s.crop[0]
+	right := r[0] + (r[2]-r[0])*(s.crop[0]+s.crop[2])
+	top := r[1] + (r[3]-r[1])*s.crop[1]
+	bottom := r[1] + (r[3]-r[1])*(s.crop[1]+s.crop[3])
+
+	if s.flipx {
+		left, right = right, left
+	}
+	if s.flipy {
+		top, bottom = bottom, top
+	}
+
+	vec := quadVertices(s.width, s.height, mgl32.Vec4{left, top, right, bottom})
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(vec)*4, gl.Ptr(vec))
+}
+
 func (s *Sprite) RadianRotate(angle float64) {
 	s.angle = float32(angle)
 
@@ -354,16 +567,83 @@ func (s *Sprite) Copy() Sprite {
 		s.scalex,
 		s.scaley,
 		s.angle,
-		s.origin,
+		s.r,
+		s.g,
+		s.b,
+		s.alpha,
+		s.blend,
+		s.premultiplied,
+		s.material,
+		s.px,
+		s.py,
+		s.ax,
+		s.ay,
 		s.vao,
+		s.vbo,
 		s.texture,
+		s.texRect,
+		s.texWidth,
+		s.texHeight,
+		s.fullWidth,
+		s.fullHeight,
+		s.crop,
+		s.flipx,
+		s.flipy,
 		s.transformation,
 	}
 }
 
-func (s *Sprite) applyTransformations(x, y, scalex, scaley, angle float32) Artist {
+//Returns the color and opacity the texture is multiplied by
+func (s *Sprite) GetTint() mgl32.Vec4 {
+	return mgl32.Vec4{s.r, s.g, s.b, s.alpha}
+}
+
+//Sets the color the texture is multiplied by.  Each value is within [0, 1], and white (1, 1, 1) leaves the texture as is.
+func (s *Sprite) SetColor(r, g, b float64) {
+	s.r = float32(r)
+	s.g = float32(g)
+	s.b = float32(b)
+}
+
+func (s *Sprite) GetColor() (float64, float64, float64) {
+	return float64(s.r), float64(s.g), float64(s.b)
+}
+
+//Sets the opacity of the sprite, from 0 (invisible) to 1 (opaque)
+func (s *Sprite) SetAlpha(a float64) {
+	s.alpha = float32(a)
+}
+
+func (s *Sprite) GetAlpha() float64 {
+	return float64(s.alpha)
+}
+
+//Sets how the sprite is blended with what has already been drawn behind it
+func (s *Sprite) SetBlendMode(b blendMode) {
+	s.blend = b
+}
+
+func (s *Sprite) GetBlendMode() blendMode {
+	return s.blend
+}
+
+//Sets the material the sprite is drawn with.  nil draws the sprite with the default shader.
+func (s *Sprite) SetMaterial(m *Material) {
+	s.material = m
+}
+
+func (s *Sprite) GetMaterial() *Material {
+	return s.material
+}
+
+func (s *Sprite) applyTransformations(x, y, scalex, scaley, angle float32, tint mgl32.Vec4) Artist {
 	spr := s.Copy()
 
+	spr.r *= tint[0]
+	spr.g *= tint[1]
+	spr.b *= tint[2]
+	spr.alpha *= tint[3]
+
 	sx2 := spr.scalex * scalex
 	sy2 := spr.scaley * scaley
 	spr.Scale(float64(sx2), float64(sy2))
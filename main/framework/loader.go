@@ -0,0 +1,331 @@
+package framework
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/koinuri/game-project/main/asset"
+)
+
+//Loads assets without stalling the frame.  Files are decoded on background goroutines, several at once, while the parts
+//that need OpenGL, such as creating the textures, are queued and run on the main thread whenever Update is called.
+//Every load returns a handle that is resolved once the asset is ready, and the loader counts how many loads are done so
+//a loading screen can show the progress.
+type Loader struct {
+	slots   chan struct{}
+	signal  chan struct{}
+	mutex   sync.Mutex
+	uploads []func()
+	total   int
+	loaded  int
+}
+
+//Creates a loader.
+//	*InitLoader()
+//	*InitLoader(workers)
+//Where:
+//	workers is the most files decoded at the same time.  It is defaulted to the number of CPUs.
+func InitLoader(workers ...int) *Loader {
+	n := runtime.NumCPU()
+	switch len(workers) {
+	case 0:
+	case 1:
+		n = workers[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one int, found %v.", len(workers)))
+	}
+
+	if n <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected at least one worker, found %v", n))
+	}
+
+	return &Loader{
+		slots:   make(chan struct{}, n),
+		signal:  make(chan struct{}, 1),
+		uploads: make([]func(), 0),
+	}
+}
+
+//Loads anything in two steps.  work runs on a background goroutine and returns the function to finish the load on the
+//main thread, which may be nil if there is nothing to do there.  A panic in work is turned into the error of the handle.
+func (l *Loader) Load(work func() (func(), error)) *Handle {
+	if work == nil {
+		panic("Invalid argument.  The work cannot be nil")
+	}
+
+	h := &Handle{done: make(chan struct{})}
+
+	l.mutex.Lock()
+	l.total++
+	l.mutex.Unlock()
+
+	go func() {
+		l.slots <- struct{}{}
+		upload, err := runLoad(work)
+		<-l.slots
+
+		l.queue(func() {
+			if err == nil && upload != nil {
+				err = upload()
+			}
+			h.resolve(err)
+		})
+	}()
+
+	return h
+}
+
+//Runs the work, recovering from a panic in it
+func runLoad(work func() (func(), error)) (upload func() error, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	finish, err := work()
+	if finish == nil {
+		return nil, err
+	}
+
+	//the upload may panic as well, such as a shader failing to compile
+	return func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+		finish()
+		return nil
+	}, err
+}
+
+func (l *Loader) queue(upload func()) {
+	l.mutex.Lock()
+	l.uploads = append(l.uploads, upload)
+	l.mutex.Unlock()
+
+	//wake Finish up if it is waiting
+	select {
+	case l.signal <- struct{}{}:
+	default:
+	}
+}
+
+//Loads an image into a texture in the background.
+//	*LoadTexture(dir)
+//	*LoadTexture(dir, TextureOptions)
+func (l *Loader) LoadTexture(dir string, options ...TextureOptions) *TextureHandle {
+	o := textureOptions(options)
+	t := &TextureHandle{}
+
+	t.Handle = l.Load(func() (func(), error) {
+		pix, width, height, err := loadPixels(dir, o.Premultiply)
+		if err != nil {
+			return nil, fmt.Errorf("could not load the file %v: %v", asset.Describe(dir), err)
+		}
+
+		return func() {
+			t.texture = createTexture(pix, width, height, o)
+			watchTexture(t.texture, dir, o)
+			t.width = width
+			t.height = height
+		}, nil
+	})
+
+	return t
+}
+
+//Loads a sprite in the background.  It takes the same arguments as InitSprite.
+func (l *Loader) LoadSprite(i ...interface{}) *SpriteHandle {
+	//the arguments are checked right away, so a mistake panics where it was made
+	dir, canvas, or, options := spriteArguments(i)
+	s := &SpriteHandle{}
+
+	s.Handle = l.Load(func() (func(), error) {
+		pix, width, height, err := loadPixels(dir, options.Premultiply)
+		if err != nil {
+			return nil, fmt.Errorf("could not load the file %v: %v", asset.Describe(dir), err)
+		}
+
+		return func() {
+			spr := createTextureSprite(pix, width, height, canvas, or, options)
+			s.sprite = &spr
+			watchTexture(spr.texture, dir, options)
+		}, nil
+	})
+
+	return s
+}
+
+//Finishes the loads that are done decoding.  Call it once per frame on the main thread.
+//	*Update()
+//	*Update(budget)
+//Where:
+//	budget is the most seconds to spend, so a frame with many large textures arriving does not stall.  At least one load is
+//	finished on every call.  It is defaulted to finishing every load that is ready.
+func (l *Loader) Update(budget ...float64) {
+	limit := -1.0
+	switch len(budget) {
+	case 0:
+	case 1:
+		limit = budget[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one float64, found %v.", len(budget)))
+	}
+
+	start := GetTime()
+	for {
+		l.mutex.Lock()
+		if len(l.uploads) == 0 {
+			l.mutex.Unlock()
+			return
+		}
+		upload := l.uploads[0]
+		l.uploads = l.uploads[1:]
+		l.mutex.Unlock()
+
+		upload()
+
+		l.mutex.Lock()
+		l.loaded++
+		l.mutex.Unlock()
+
+		if limit >= 0 && GetTime()-start >= limit {
+			return
+		}
+	}
+}
+
+//Blocks until every load started so far is done, finishing them on the calling thread, which has to be the main thread.
+func (l *Loader) Finish() {
+	for {
+		l.Update()
+		if l.IsDone() {
+			return
+		}
+		<-l.signal
+	}
+}
+
+//Returns the share of the loads done, within [0, 1].  It is 1 if nothing has been loaded.
+func (l *Loader) GetProgress() float64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.total == 0 {
+		return 1
+	}
+	return float64(l.loaded) / float64(l.total)
+}
+
+//Returns how many loads are done, including the ones that failed, and how many have been started
+func (l *Loader) GetCount() (int, int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.loaded, l.total
+}
+
+func (l *Loader) IsDone() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.loaded == l.total
+}
+
+//Forgets the loads done so far, so the progress of the next loading screen starts from 0.  Loads still going on are kept.
+func (l *Loader) ResetProgress() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.total -= l.loaded
+	l.loaded = 0
+}
+
+//The result of a load, resolved on the main thread once the load is done or has failed.
+type Handle struct {
+	done      chan struct{}
+	err       error
+	callbacks []func()
+}
+
+func (h *Handle) resolve(err error) {
+	h.err = err
+	close(h.done)
+
+	for _, f := range h.callbacks {
+		f()
+	}
+	h.callbacks = nil
+}
+
+func (h *Handle) IsReady() bool {
+	select {
+	case <-h.done:
+		return true
+	default:
+		return false
+	}
+}
+
+//Returns the error the load failed with, or nil if it succeeded or is not done yet
+func (h *Handle) GetError() error {
+	if !h.IsReady() {
+		return nil
+	}
+	return h.err
+}
+
+//Blocks until the load is done and returns its error.  Never call it on the main thread, since the load is finished there;
+//use Loader.Finish instead.
+func (h *Handle) Wait() error {
+	<-h.done
+	return h.err
+}
+
+//Calls f on the main thread once the load is done, or right away if it already is.  f is called even if the load failed,
+//so check GetError.
+func (h *Handle) OnReady(f func()) {
+	if h.IsReady() {
+		f()
+		return
+	}
+	h.callbacks = append(h.callbacks, f)
+}
+
+//The texture of a load started with Loader.LoadTexture
+type TextureHandle struct {
+	*Handle
+	texture uint32
+	width   int32
+	height  int32
+}
+
+//Returns the texture, or 0 until it is ready
+func (t *TextureHandle) GetTexture() uint32 {
+	if !t.IsReady() {
+		return 0
+	}
+	return t.texture
+}
+
+//Returns the size of the texture in pixels, or (0, 0) until it is ready
+func (t *TextureHandle) GetSize() (int32, int32) {
+	if !t.IsReady() {
+		return 0, 0
+	}
+	return t.width, t.height
+}
+
+//The sprite of a load started with Loader.LoadSprite
+type SpriteHandle struct {
+	*Handle
+	sprite *Sprite
+}
+
+//Returns the sprite, or nil until it is ready or if the load failed
+func (s *SpriteHandle) GetSprite() *Sprite {
+	if !s.IsReady() {
+		return nil
+	}
+	return s.sprite
+}
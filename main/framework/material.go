@@ -0,0 +1,200 @@
+package framework
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/koinuri/game-project/main/asset"
+)
+
+//Linked OpenGL program made of a vertex and a fragment shader.
+type Shader struct {
+	program uint32
+}
+
+//Loads and links a shader program from files.
+//	*InitShader(vertex, fragment)
+//Where:
+//	vertex and fragment are the locations of the shader sources, relative to the executable file.  An empty string uses the
+//	default shader for that stage, so effects that only change the colors only need a fragment shader.
+//Vertex shaders receive the position as attribute 0 (vp) and the texture coordinate as attribute 1 (tx), and should use the
+//transformation and projection uniforms the same way the default vertex shader does.  Fragment shaders receive the sprite's
+//texture in ourTexture, and the tint and premultiplied uniforms if they declare them.
+func InitShader(vertex, fragment string) *Shader {
+	vs, err := loadShaderSource(vertex, vertexShaderSource)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the vertex shader %v.\n%v", asset.Describe(vertex), err))
+	}
+
+	fs, err := loadShaderSource(fragment, fragmentShaderSource)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the fragment shader %v.\n%v", asset.Describe(fragment), err))
+	}
+
+	prog, err := createProgram(vs, fs)
+	if err != nil {
+		panic(err)
+	}
+
+	s := &Shader{prog}
+	s.watch(vertex, fragment, vertexShaderSource)
+	return s
+}
+
+func loadShaderSource(dir string, def string) (string, error) {
+	if dir == "" {
+		return def, nil
+	}
+
+	source, err := asset.ReadFile(dir)
+	if err != nil {
+		return "", err
+	}
+
+	//OpenGL expects null terminated strings
+	return string(source) + "\x00", nil
+}
+
+//Compiles the shader again whenever one of its files changes, keeping the old program if the new one fails
+func (s *Shader) watch(vertex, fragment, defaultVertex string) {
+	reload := func() error {
+		vs, err := loadShaderSource(vertex, defaultVertex)
+		if err != nil {
+			return err
+		}
+		fs, err := loadShaderSource(fragment, fragmentShaderSource)
+		if err != nil {
+			return err
+		}
+
+		prog, err := createProgram(vs, fs)
+		if err != nil {
+			return err
+		}
+
+		gl.DeleteProgram(s.program)
+		s.program = prog
+		return nil
+	}
+
+	for _, dir := range []string{vertex, fragment} {
+		if dir != "" {
+			WatchAsset(dir, reload)
+		}
+	}
+}
+
+//Returns the OpenGL program of the shader
+func (s *Shader) GetProgram() uint32 {
+	return s.program
+}
+
+type materialTexture struct {
+	name    string
+	texture uint32
+}
+
+//Shader together with the uniform values and extra textures used when drawing an artist with it.  Several materials can share
+//one shader, each with their own values.
+type Material struct {
+	shader   *Shader
+	uniforms map[string]interface{}
+	textures []materialTexture
+}
+
+func InitMaterial(shader *Shader) *Material {
+	if shader == nil {
+		panic("Invalid argument.  The shader cannot be nil")
+	}
+
+	return &Material{
+		shader,
+		make(map[string]interface{}),
+		make([]materialTexture, 0),
+	}
+}
+
+func (m *Material) GetShader() *Shader {
+	return m.shader
+}
+
+func (m *Material) SetInt(name string, v int32) {
+	m.uniforms[name] = v
+}
+
+func (m *Material) SetFloat(name string, v float32) {
+	m.uniforms[name] = v
+}
+
+func (m *Material) SetVec2(name string, x, y float32) {
+	m.uniforms[name] = mgl32.Vec2{x, y}
+}
+
+func (m *Material) SetVec3(name string, x, y, z float32) {
+	m.uniforms[name] = mgl32.Vec3{x, y, z}
+}
+
+func (m *Material) SetVec4(name string, x, y, z, w float32) {
+	m.uniforms[name] = mgl32.Vec4{x, y, z, w}
+}
+
+func (m *Material) SetMat4(name string, v mgl32.Mat4) {
+	m.uniforms[name] = v
+}
+
+//Binds a texture to the sampler uniform with the name given.  The sprite's own texture always stays in ourTexture, and the
+//textures set here take the texture units after it, in the order they were first set.
+func (m *Material) SetTexture(name string, texture uint32) {
+	for i, t := range m.textures {
+		if t.name == name {
+			m.textures[i].texture = texture
+			return
+		}
+	}
+	m.textures = append(m.textures, materialTexture{name, texture})
+}
+
+//Creates a material with the same shader, uniform values and textures that can be changed separately
+func (m *Material) Copy() *Material {
+	mat := InitMaterial(m.shader)
+	for name, v := range m.uniforms {
+		mat.uniforms[name] = v
+	}
+	mat.textures = append(mat.textures, m.textures...)
+	return mat
+}
+
+//Sets the uniforms and binds the textures of the material.  The material's shader must be in use.
+func (m *Material) apply() {
+	prog := m.shader.program
+
+	for name, v := range m.uniforms {
+		loc := gl.GetUniformLocation(prog, gl.Str(name+"\x00"))
+
+		switch u := v.(type) {
+		case int32:
+			gl.Uniform1i(loc, u)
+		case float32:
+			gl.Uniform1f(loc, u)
+		case mgl32.Vec2:
+			gl.Uniform2f(loc, u[0], u[1])
+		case mgl32.Vec3:
+			gl.Uniform3f(loc, u[0], u[1], u[2])
+		case mgl32.Vec4:
+			gl.Uniform4f(loc, u[0], u[1], u[2], u[3])
+		case mgl32.Mat4:
+			gl.UniformMatrix4fv(loc, 1, false, &u[0])
+		}
+	}
+
+	for i, t := range m.textures {
+		unit := int32(i + 1)
+		gl.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+		gl.BindTexture(gl.TEXTURE_2D, t.texture)
+
+		loc := gl.GetUniformLocation(prog, gl.Str(t.name+"\x00"))
+		gl.Uniform1i(loc, unit)
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+}
@@ -0,0 +1,422 @@
+package framework
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/koinuri/game-project/main/asset"
+)
+
+//Artist that stretches a bordered image, such as the frame of a dialog box or a button, to any size.  The image is cut into
+//a grid of nine by its borders.  The corners keep their size, the edges stretch along one side and the middle stretches
+//both ways, so the borders never distort.
+type NineSlice struct {
+	x              float32
+	y              float32
+	width          float32
+	height         float32
+	ox             float32
+	oy             float32
+	px             float32
+	py             float32
+	ax             float32
+	ay             float32
+	scalex         float32
+	scaley         float32
+	angle          float32
+	r              float32
+	g              float32
+	b              float32
+	alpha          float32
+	blend          blendMode
+	premultiplied  bool
+	material       *Material
+	vao            uint32
+	vbo            uint32
+	ebo            uint32
+	texture        uint32
+	texWidth       float32
+	texHeight      float32
+	borders        mgl32.Vec4
+	transformation transformation
+	//the image, shared with the copies of the nine-slice, or nil for the white texture of the rectangles
+	image *sliceImage
+}
+
+//Texture loaded for a nine-slice, freed once the nine-slice and every copy of it are deleted
+type sliceImage struct {
+	texture uint32
+	refs    int
+}
+
+//Creates a nine-slice from an image.
+//	*InitNineSlice(dir, border)
+//	*InitNineSlice(dir, left, top, right, bottom)
+//Where:
+//	dir is the location of the image, relative to the executable file.
+//	border is the width of every border, in pixels of the image.
+//	left, top, right and bottom are the widths of each border, in pixels of the image.
+//The nine-slice starts at the size of the image, where every pixel of the image is one unit of the window's 1600 by 900
+//coordinate system, and is resized with SetSize.
+func InitNineSlice(dir string, borders ...float64) *NineSlice {
+	var b mgl32.Vec4
+	switch len(borders) {
+	case 1:
+		w := float32(borders[0])
+		b = mgl32.Vec4{w, w, w, w}
+	case 4:
+		b = mgl32.Vec4{float32(borders[0]), float32(borders[1]), float32(borders[2]), float32(borders[3])}
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected 1 or 4 borders, found %v.", len(borders)))
+	}
+
+	//the slices are sampled up to their edges, so the texture must not wrap around to the opposite side
+	options := GetDefaultTextureOptions()
+	options.Wrap = WrapClamp
+
+	pix, width, height, err := loadPixels(dir, options.Premultiply)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the file %v.\n%v", asset.Describe(dir), err))
+	}
+
+	tw := float32(width)
+	th := float32(height)
+	if b[0] < 0 || b[1] < 0 || b[2] < 0 || b[3] < 0 || b[0]+b[2] > tw || b[1]+b[3] > th {
+		panic(fmt.Sprintf("Invalid argument.  Expected borders fitting in the %vx%v image, found %v", width, height, b))
+	}
+
+	img := &sliceImage{texture: createTexture(pix, width, height, options), refs: 1}
+	n := newNineSlice(img.texture, tw, th, b)
+	n.image = img
+	if options.Premultiply {
+		n.blend = BlendPremultiplied
+		n.premultiplied = true
+	}
+
+	WatchAsset(dir, func() error {
+		//the texture of a deleted image may already hold something else
+		if img.refs == 0 {
+			return nil
+		}
+		pix, width, height, err := loadPixels(dir, options.Premultiply)
+		if err != nil {
+			return err
+		}
+		uploadTexture(img.texture, pix, width, height, options)
+		return nil
+	})
+	return n
+}
+
+//Texture of a single white pixel, shared by every rectangle
+var whiteTexture uint32
+
+//Creates a rectangle of a plain color, white until SetColor is called.  It is a nine-slice without borders, so it is resized
+//with SetSize like any other.
+func InitRectangle(width, height float64) *NineSlice {
+	if whiteTexture == 0 {
+		whiteTexture = createTexture([]uint8{255, 255, 255, 255}, 1, 1, TextureOptions{Filter: FilterNearest, Wrap: WrapClamp, Mipmaps: MipmapsOff, Anisotropy: 1})
+	}
+
+	n := newNineSlice(whiteTexture, 1, 1, mgl32.Vec4{})
+	n.SetSize(width, height)
+	return n
+}
+
+//Creates a nine-slice of the texture, where texWidth and texHeight are its size in pixels, starting at that size
+func newNineSlice(texture uint32, texWidth, texHeight float32, borders mgl32.Vec4) *NineSlice {
+	n := &NineSlice{
+		width:          texWidth,
+		height:         texHeight,
+		ax:             .5,
+		ay:             .5,
+		px:             .5,
+		py:             .5,
+		scalex:         1,
+		scaley:         1,
+		r:              1,
+		g:              1,
+		b:              1,
+		alpha:          1,
+		blend:          BlendNormal,
+		texture:        texture,
+		texWidth:       texWidth,
+		texHeight:      texHeight,
+		borders:        borders,
+		transformation: InitTransformation(),
+	}
+
+	gl.GenVertexArrays(1, &n.vao)
+	gl.BindVertexArray(n.vao)
+
+	gl.GenBuffers(1, &n.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, n.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, 16*5*4, nil, gl.DYNAMIC_DRAW)
+
+	//two triangles for every cell of the 4 by 4 grid of vertices
+	ind := make([]uint32, 0, 54)
+	for row := uint32(0); row < 3; row++ {
+		for col := uint32(0); col < 3; col++ {
+			i := row*4 + col
+			ind = append(ind, i, i+1, i+4, i+1, i+4, i+5)
+		}
+	}
+	gl.GenBuffers(1, &n.ebo)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, n.ebo)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(ind)*4, gl.Ptr(ind), gl.STATIC_DRAW)
+
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 5*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 5*4, gl.PtrOffset(3*4))
+	gl.EnableVertexAttribArray(1)
+
+	n.updateVertices()
+	n.updateOrigin()
+	return n
+}
+
+//Rewrites the vertices for the size of the nine-slice
+func (n *NineSlice) updateVertices() {
+	if n.vao == 0 {
+		return
+	}
+
+	left, top, right, bottom := n.borders[0], n.borders[1], n.borders[2], n.borders[3]
+
+	//borders wider than the nine-slice shrink together, so they still meet in the middle
+	if left+right > n.width {
+		k := n.width / (left + right)
+		left *= k
+		right *= k
+	}
+	if top+bottom > n.height {
+		k := n.height / (top + bottom)
+		top *= k
+		bottom *= k
+	}
+
+	w := n.width / 2
+	h := n.height / 2
+	xs := [4]float32{-w, -w + left, w - right, w}
+	ys := [4]float32{h, h - top, -h + bottom, -h}
+	us := [4]float32{0, n.borders[0] / n.texWidth, 1 - n.borders[2]/n.texWidth, 1}
+	vs := [4]float32{0, n.borders[1] / n.texHeight, 1 - n.borders[3]/n.texHeight, 1}
+
+	vec := make([]float32, 0, 16*5)
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 4; col++ {
+			vec = append(vec, xs[col], ys[row], 0, us[col], vs[row])
+		}
+	}
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, n.vbo)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(vec)*4, gl.Ptr(vec))
+}
+
+func (n *NineSlice) updateOrigin() {
+	n.ox, n.oy = pivotCenter(n.x, n.y, n.width*n.scalex, n.height*n.scaley, n.px, n.py, n.angle)
+}
+
+//Returns a nine-slice of the same image with vertices of its own, so it can be given a size of its own
+func (n *NineSlice) Copy() *NineSlice {
+	ns := newNineSlice(n.texture, n.texWidth, n.texHeight, n.borders)
+	vao, vbo, ebo := ns.vao, ns.vbo, ns.ebo
+
+	*ns = *n
+	ns.vao = vao
+	ns.vbo = vbo
+	ns.ebo = ebo
+	if ns.image != nil {
+		ns.image.refs++
+	}
+	ns.updateVertices()
+	return ns
+}
+
+//Frees the vertices of the nine-slice, and its image once every copy made with Copy is deleted as well.  The nine-slice
+//draws nothing afterwards.  Copies of the struct share the vertices, so only one of them is deleted.
+func (n *NineSlice) Delete() {
+	if n.vao == 0 {
+		return
+	}
+
+	gl.DeleteBuffers(1, &n.vbo)
+	gl.DeleteBuffers(1, &n.ebo)
+	gl.DeleteVertexArrays(1, &n.vao)
+	n.vao, n.vbo, n.ebo = 0, 0, 0
+
+	if n.image != nil {
+		n.image.refs--
+		if n.image.refs == 0 {
+			gl.DeleteTextures(1, &n.image.texture)
+		}
+		n.image = nil
+	}
+	n.texture = 0
+}
+
+//Sets the size the image is stretched to.  Copies of the struct share its vertices, so they are resized as well, unlike the
+//nine-slices made with Copy.
+func (n *NineSlice) SetSize(width, height float64) {
+	if width < 0 || height < 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a size of 0 or more, found %vx%v", width, height))
+	}
+
+	n.width = float32(width)
+	n.height = float32(height)
+	n.updateVertices()
+}
+
+func (n *NineSlice) GetSize() (float64, float64) {
+	return float64(n.width), float64(n.height)
+}
+
+//Returns the widths of the left, top, right and bottom borders, in pixels of the image
+func (n *NineSlice) GetBorders() (float64, float64, float64, float64) {
+	return float64(n.borders[0]), float64(n.borders[1]), float64(n.borders[2]), float64(n.borders[3])
+}
+
+//Sets the point of the nine-slice that is placed at its position, where (0, 0) is the top left corner and (1, 1) is the
+//bottom right corner.  It is defaulted to the center.
+func (n *NineSlice) SetPivot(x, y float64) {
+	n.px = float32(x)
+	n.py = float32(y)
+}
+
+func (n *NineSlice) GetPivot() (float64, float64) {
+	return float64(n.px), float64(n.py)
+}
+
+//Sets the pivot to one of the nine origins, such as TopLeft
+func (n *NineSlice) SetOrigin(or origin) {
+	n.px, n.py = or.pivot()
+}
+
+//Sets the point of the rectangle of the object holding the nine-slice that its position is measured from.  It is defaulted
+//to the center.
+func (n *NineSlice) SetAnchor(x, y float64) {
+	n.ax = float32(x)
+	n.ay = float32(y)
+}
+
+func (n *NineSlice) GetAnchor() (float64, float64) {
+	return float64(n.ax), float64(n.ay)
+}
+
+//Returns the vao and texture held by the nine-slice to draw
+func (n *NineSlice) GetDrawInfo() (uint32, uint32) {
+	return n.vao, n.texture
+}
+
+func (n *NineSlice) getMeshes() []mesh {
+	if n.vao == 0 {
+		return nil
+	}
+	return []mesh{{n.vao, n.texture, 54, 1, n.premultiplied}}
+}
+
+func (n *NineSlice) GetTransformation() mgl32.Mat4 {
+	n.updateOrigin()
+
+	n.transformation.translation = mgl32.Translate3D(n.ox, n.oy, 0)
+	return n.transformation.translation.Mul4(n.transformation.rotation.Mul4(n.transformation.scale))
+}
+
+func (n *NineSlice) GetPosition() (float64, float64) {
+	return float64(n.x), float64(n.y)
+}
+
+func (n *NineSlice) GetScale() (float64, float64) {
+	return float64(n.scalex), float64(n.scaley)
+}
+
+func (n *NineSlice) GetAngle() float64 {
+	return float64(n.angle)
+}
+
+func (n *NineSlice) Move(x, y float64) {
+	n.x = float32(x)
+	n.y = float32(y)
+}
+
+//Scales the whole nine-slice, borders included.  Use SetSize to stretch it without distorting the borders.
+func (n *NineSlice) Scale(v ...float64) {
+	switch len(v) {
+	case 1:
+		n.scalex = float32(v[0])
+		n.scaley = float32(v[0])
+	case 2:
+		n.scalex = float32(v[0])
+		n.scaley = float32(v[1])
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected either 1 or 2 float64, found %v", len(v)))
+	}
+
+	n.transformation.scale = mgl32.Diag4(mgl32.Vec4{n.scalex, n.scaley, 1, 1})
+}
+
+func (n *NineSlice) RadianRotate(angle float64) {
+	n.angle = float32(angle)
+
+	n.transformation.rotation = mgl32.HomogRotate3DZ(n.angle)
+}
+
+func (n *NineSlice) AngleRotate(angle float64) {
+	n.RadianRotate(angle * (math.Pi / 180))
+}
+
+func (n *NineSlice) GetTint() mgl32.Vec4 {
+	return mgl32.Vec4{n.r, n.g, n.b, n.alpha}
+}
+
+func (n *NineSlice) SetColor(r, g, b float64) {
+	n.r = float32(r)
+	n.g = float32(g)
+	n.b = float32(b)
+}
+
+func (n *NineSlice) GetColor() (float64, float64, float64) {
+	return float64(n.r), float64(n.g), float64(n.b)
+}
+
+func (n *NineSlice) SetAlpha(a float64) {
+	n.alpha = float32(a)
+}
+
+func (n *NineSlice) GetAlpha() float64 {
+	return float64(n.alpha)
+}
+
+func (n *NineSlice) SetBlendMode(b blendMode) {
+	n.blend = b
+}
+
+func (n *NineSlice) GetBlendMode() blendMode {
+	return n.blend
+}
+
+func (n *NineSlice) SetMaterial(m *Material) {
+	n.material = m
+}
+
+func (n *NineSlice) GetMaterial() *Material {
+	return n.material
+}
+
+func (n *NineSlice) applyTransformations(x, y, scalex, scaley, angle float32, tint mgl32.Vec4) Artist {
+	ns := *n
+
+	ns.r *= tint[0]
+	ns.g *= tint[1]
+	ns.b *= tint[2]
+	ns.alpha *= tint[3]
+
+	ns.Scale(float64(ns.scalex*scalex), float64(ns.scaley*scaley))
+	ns.RadianRotate(float64(ns.angle + angle))
+	ns.Move(float64(ns.x+x), float64(ns.y+y))
+
+	return &ns
+}
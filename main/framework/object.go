@@ -3,22 +3,36 @@ package framework
 import (
 	"fmt"
 	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
 )
 
 type Object struct {
-	artists []Artist
-	names   []string
-	x       float32
-	y       float32
-	width   float32
-	height  float32
-	ox      float32
-	oy      float32
-	scalex  float32
-	scaley  float32
-	angle   float32
-	bounds  Bound
-	origin  origin
+	artists   []Artist
+	names     []string
+	x         float32
+	y         float32
+	width     float32
+	height    float32
+	ox        float32
+	oy        float32
+	scalex    float32
+	scaley    float32
+	angle     float32
+	r         float32
+	g         float32
+	b         float32
+	alpha     float32
+	bounds    Bound
+	px        float32
+	py        float32
+	listeners []MoveListener
+}
+
+//Anything that needs to know when an object moves, such as a spatial index or a sound emitter.  Scaling, rotating and
+//resizing the object count as moving it, as they change the area it covers.
+type MoveListener interface {
+	ObjectMoved(o *Object)
 }
 
 //Initializes the object object that stores artist objects.
@@ -84,10 +98,18 @@ func InitObject(i ...interface{}) Object {
 		1,
 		1,
 		0,
+		1,
+		1,
+		1,
+		1,
 		Bound{0, 0, 0, 0},
-		or,
+		0,
+		0,
+		make([]MoveListener, 0),
 	}
 
+	obj.px, obj.py = or.pivot()
+
 	obj.updateOrigin()
 
 	obj.setBounds()
@@ -95,38 +117,23 @@ func InitObject(i ...interface{}) Object {
 	return obj
 }
 
+//Places the center of the rectangle of the object so its pivot is at its position
 func (o *Object) updateOrigin() {
-	sx := o.width / 2
-	sy := o.height / 2
-
-	//based on the origin, get the constant to place origin (negative, 0, or positive)
-	detx := math.Mod(float64(o.origin), 3.0) - 1
-	dety := math.Floor(float64(o.origin)/3.0) - 1
-
-	//origin at angle 0
-	ox := o.x - sx*float32(detx)
-	oy := o.y - sy*float32(dety)
-
-	//difference between (x,y) and (ox,oy) and distance based on the numbers calculated
-	dx := o.x - ox
-	dy := o.y - oy
-	d := float32(math.Sqrt(float64(dx*dx + dy*dy)))
-
-	//The real origin using the angle provided
-	if o.angle != 0 {
-		ox = d*float32(math.Cos(float64(o.angle))) + o.x
-		oy = d*float32(math.Sin(float64(o.angle))) + o.y
-	}
-
-	o.ox = ox
-	o.oy = oy
+	o.ox, o.oy = pivotCenter(o.x, o.y, o.width, o.height, o.px, o.py, o.angle)
 }
 
+//Finds the box around the corners of the rectangle, scaled and rotated around the pivot
 func (o *Object) setBounds() {
-	left := o.ox - (o.width / 2)
-	right := o.ox + (o.width / 2)
-	up := o.oy + (o.height / 2)
-	down := o.oy + (o.height / 2)
+	left, up := o.anchorPoint(0, 0)
+	right, down := left, up
+
+	for _, corner := range [][2]float32{{1, 0}, {0, 1}, {1, 1}} {
+		x, y := o.anchorPoint(corner[0], corner[1])
+		left = float32(math.Min(float64(left), float64(x)))
+		right = float32(math.Max(float64(right), float64(x)))
+		up = float32(math.Max(float64(up), float64(y)))
+		down = float32(math.Min(float64(down), float64(y)))
+	}
 
 	o.bounds = Bound{left, right, up, down}
 }
@@ -138,10 +145,19 @@ func (o *Object) setBounds() {
 //	name is the name of the sprite that can be referred to
 //	directory is the location of the image to create sprites with
 //	origin is the origin the sprite will be based on.
+//Either of them can end with TextureOptions, such as to sample pixel art with FilterNearest.
 func (o *Object) CreateSprite(i ...interface{}) *Sprite {
 	var name string
 	var dir string
 	var or origin = 4
+	options := GetDefaultTextureOptions()
+
+	if len(i) > 2 {
+		if test, succ := i[len(i)-1].(TextureOptions); succ {
+			options = test
+			i = i[:len(i)-1]
+		}
+	}
 
 	if len(i) > 0 {
 		test, succ := i[0].(string)
@@ -180,24 +196,61 @@ func (o *Object) CreateSprite(i ...interface{}) *Sprite {
 		panic(fmt.Sprintf("Invalid number of arguments.  The method only accepts one to three arguments, found %v instead.", len(i)))
 	}
 
-	sprite := InitSprite(dir, InitCanvas(o.width, o.height, o.ox, o.oy), or)
+	sprite := InitSprite(dir, o.GetCanvas(), or, options)
 
-	o.artists = append(o.artists, &sprite)
-	o.names = append(o.names, name)
+	o.AddArtist(name, &sprite)
 
 	return &sprite
 }
 
+//Adds an artist that has already been created, such as a sprite of a render target, to the object
+func (o *Object) AddArtist(name string, a Artist) {
+	for _, n := range o.names {
+		if name == n {
+			panic(fmt.Sprintf("Invalid argument.  The name \"%v\" already exists", name))
+		}
+	}
+
+	o.artists = append(o.artists, a)
+	o.names = append(o.names, name)
+}
+
+//Returns the canvas covering the object, for creating artists sized to fit in it
+func (o *Object) GetCanvas() Canvas {
+	return InitCanvas(o.width, o.height, o.ox, o.oy)
+}
+
+//Artists positioned relative to a point of the rectangle of the object holding them, such as sprites
+type anchored interface {
+	GetAnchor() (float64, float64)
+}
+
 func (o *Object) GetArtists() []Artist {
 	artists := make([]Artist, len(o.artists))
 
 	for i, artist := range o.artists {
-		artists[i] = artist.applyTransformations(o.ox, o.oy, o.scalex, o.scaley, o.angle)
+		x, y := o.ox, o.oy
+		if a, succ := artist.(anchored); succ {
+			ax, ay := a.GetAnchor()
+			x, y = o.anchorPoint(float32(ax), float32(ay))
+		}
+
+		artists[i] = artist.applyTransformations(x, y, o.scalex, o.scaley, o.angle, mgl32.Vec4{o.r, o.g, o.b, o.alpha})
 	}
 
 	return artists
 }
 
+//Returns where the point of the rectangle at the anchor is in the window, scaled and rotated with the object around its
+//pivot
+func (o *Object) anchorPoint(ax, ay float32) (float32, float32) {
+	dx := (ax - o.px) * o.width * o.scalex
+	dy := (o.py - ay) * o.height * o.scaley
+
+	sin, cos := math.Sincos(float64(o.angle))
+	return o.x + dx*float32(cos) - dy*float32(sin), o.y + dx*float32(sin) + dy*float32(cos)
+}
+
 func (o *Object) GetArtist(name string) Artist {
 	for i, n := range o.names {
 		if n == name {
@@ -207,11 +260,89 @@ func (o *Object) GetArtist(name string) Artist {
 	panic(fmt.Sprintf("Invalid name.  Could not find any artist with the name \"%v\".", name))
 }
 
+//Returns the position of the object
+func (o *Object) GetPosition() (float64, float64) {
+	return float64(o.x), float64(o.y)
+}
+
+//Returns the horizontal and vertical scale of the object
+func (o *Object) GetScale() (float64, float64) {
+	return float64(o.scalex), float64(o.scaley)
+}
+
+//Returns the rotation of the object in radians
+func (o *Object) GetAngle() float64 {
+	return float64(o.angle)
+}
+
 func (o *Object) Move(x, y float64) {
 	o.x = float32(x)
 	o.y = float32(y)
 
 	o.updateOrigin()
+	o.setBounds()
+	o.moved()
+}
+
+func (o *Object) moved() {
+	for _, l := range o.listeners {
+		l.ObjectMoved(o)
+	}
+}
+
+//Adds a listener that is told every time the object is moved, scaled, rotated or resized
+func (o *Object) AddMoveListener(l MoveListener) {
+	o.listeners = append(o.listeners, l)
+}
+
+func (o *Object) RemoveMoveListener(l MoveListener) {
+	for i, n := range o.listeners {
+		if n == l {
+			o.listeners = append(o.listeners[:i], o.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+//Changes the size of the rectangle of the object, keeping its pivot in place.  Artists anchored to the rectangle follow it,
+//while the size of the artists stays the same.
+func (o *Object) Resize(width, height float64) {
+	o.width = float32(width)
+	o.height = float32(height)
+
+	o.updateOrigin()
+	o.setBounds()
+	o.moved()
+}
+
+func (o *Object) GetSize() (float64, float64) {
+	return float64(o.width), float64(o.height)
+}
+
+//Sets the point of the rectangle of the object that is placed at its position, where (0, 0) is the top left corner and
+//(1, 1) is the bottom right corner
+func (o *Object) SetPivot(x, y float64) {
+	o.px = float32(x)
+	o.py = float32(y)
+
+	o.updateOrigin()
+	o.setBounds()
+	o.moved()
+}
+
+func (o *Object) GetPivot() (float64, float64) {
+	return float64(o.px), float64(o.py)
+}
+
+//Sets the pivot to one of the nine origins, such as TopLeft
+func (o *Object) SetOrigin(or origin) {
+	px, py := or.pivot()
+	o.SetPivot(float64(px), float64(py))
+}
+
+//Returns the smallest rectangle holding the object in the window, scaled and rotated around its pivot
+func (o *Object) GetBounds() Bound {
+	return o.bounds
 }
 
 func (o *Object) Scale(i ...float64) {
@@ -236,13 +367,39 @@ func (o *Object) Scale(i ...float64) {
 
 	o.scalex = x
 	o.scaley = y
+	o.setBounds()
+	o.moved()
+}
+
+//Sets the color every artist in the object is multiplied by, on top of their own colors
+func (o *Object) SetColor(r, g, b float64) {
+	o.r = float32(r)
+	o.g = float32(g)
+	o.b = float32(b)
+}
+
+func (o *Object) GetColor() (float64, float64, float64) {
+	return float64(o.r), float64(o.g), float64(o.b)
+}
+
+//Sets the opacity every artist in the object is multiplied by, on top of their own opacity
+func (o *Object) SetAlpha(a float64) {
+	o.alpha = float32(a)
+}
+
+func (o *Object) GetAlpha() float64 {
+	return float64(o.alpha)
 }
 
 func (o *Object) AngleRotate(angle float64) {
-	a := angle * (math.Pi / 180)
-	o.angle = float32(a)
+	o.RadianRotate(angle * (math.Pi / 180))
 }
 
+//Rotates the object around its pivot
 func (o *Object) RadianRotate(angle float64) {
 	o.angle = float32(angle)
+
+	o.updateOrigin()
+	o.setBounds()
+	o.moved()
 }
@@ -1,5 +1,11 @@
 package framework
 
+import (
+	"fmt"
+	"math"
+)
+
+//One of the nine points of a rectangle, used as a pivot.  Pivots between them are set with SetPivot.
 type origin uint32
 
 const (
@@ -13,3 +19,22 @@ const (
 	BottomCenter
 	BottomRight
 )
+
+//Returns the pivot at the origin, where (0, 0) is the top left corner and (1, 1) is the bottom right corner
+func (or origin) pivot() (float32, float32) {
+	if or > BottomRight {
+		panic(fmt.Sprintf("Invalid argument.  Expected one of the nine origins, found %v", uint32(or)))
+	}
+	return float32(or%3) / 2, float32(or/3) / 2
+}
+
+//Returns where the center of a rectangle is when its pivot is placed at (x, y).  The pivot is normalized, where (0, 0) is
+//the top left corner and (1, 1) is the bottom right corner, and the rectangle is rotated by angle around it.
+func pivotCenter(x, y, width, height, px, py, angle float32) (float32, float32) {
+	//from the pivot to the center, before rotating, with y going up in the window
+	dx := (.5 - px) * width
+	dy := (py - .5) * height
+
+	sin, cos := math.Sincos(float64(angle))
+	return x + dx*float32(cos) - dy*float32(sin), y + dx*float32(sin) + dy*float32(cos)
+}
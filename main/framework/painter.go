@@ -33,26 +33,45 @@ const (
 		out vec4 frag_colour;
 		
 		uniform sampler2D ourTexture;
+		uniform vec4 tint;
+		uniform bool premultiplied;
 
         void main() {
-            frag_colour = texture(ourTexture, TexCoord);
+            vec4 colour = texture(ourTexture, TexCoord) * tint;
+            if (premultiplied) {
+                colour.rgb *= tint.a;
+            } else {
+                colour.rgb *= colour.a;
+            }
+            frag_colour = colour;
         }
     ` + "\x00"
 )
 
+//Projection shared by every shader program, set when the window is initialized
+var projection mgl32.Mat4
+
 func Init(width int, height int) (*glfw.Window, uint32) {
 	window := initGlfw(width, height)
 	prog := initOpenGL()
 
-	gl.UseProgram(prog)
+	gl.Enable(gl.BLEND)
+	setBlendMode(BlendNormal)
 
-	ortho := mgl32.Ortho2D(-800, 800, -450, 450)
-	orthoUniform := gl.GetUniformLocation(prog, gl.Str("projection\x00"))
-	gl.UniformMatrix4fv(orthoUniform, 1, false, &ortho[0])
+	projection = mgl32.Ortho2D(-800, 800, -450, 450)
+	useProgram(prog)
 
 	return window, prog
 }
 
+//Switches to the program and gives it the projection
+func useProgram(prog uint32) {
+	gl.UseProgram(prog)
+
+	orthoUniform := gl.GetUniformLocation(prog, gl.Str("projection\x00"))
+	gl.UniformMatrix4fv(orthoUniform, 1, false, &projection[0])
+}
+
 func initGlfw(width int, height int) *glfw.Window {
 	if err := glfw.Init(); err != nil {
 		panic(err)
@@ -83,21 +102,54 @@ func initOpenGL() uint32 {
 	version := gl.GoStr(gl.GetString(gl.VERSION))
 	log.Println("OpenGL version", version)
 
-	vertexShader, err := compileShader(vertexShaderSource, gl.VERTEX_SHADER)
+	prog, err := createProgram(vertexShaderSource, fragmentShaderSource)
 	if err != nil {
 		panic(err)
 	}
+	return prog
+}
+
+//Compiles both shaders and links them into a program, returning the link log as the error if linking fails
+func createProgram(vertexSource, fragmentSource string) (uint32, error) {
+	vertexShader, err := compileShader(vertexSource, gl.VERTEX_SHADER)
+	if err != nil {
+		return 0, err
+	}
 
-	fragmentShader, err := compileShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
+	fragmentShader, err := compileShader(fragmentSource, gl.FRAGMENT_SHADER)
 	if err != nil {
-		panic(err)
+		gl.DeleteShader(vertexShader)
+		return 0, err
 	}
 
 	prog := gl.CreateProgram()
 	gl.AttachShader(prog, vertexShader)
 	gl.AttachShader(prog, fragmentShader)
+
+	//every vao stores the position at 0 and the texture coordinate at 1, so make every program read them from there
+	gl.BindAttribLocation(prog, 0, gl.Str("vp\x00"))
+	gl.BindAttribLocation(prog, 1, gl.Str("tx\x00"))
+
 	gl.LinkProgram(prog)
-	return prog
+
+	//the shaders are kept by the program once linked
+	gl.DeleteShader(vertexShader)
+	gl.DeleteShader(fragmentShader)
+
+	var status int32
+	gl.GetProgramiv(prog, gl.LINK_STATUS, &status)
+	if status == gl.FALSE {
+		var logLength int32
+		gl.GetProgramiv(prog, gl.INFO_LOG_LENGTH, &logLength)
+
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetProgramInfoLog(prog, logLength, nil, gl.Str(log))
+
+		gl.DeleteProgram(prog)
+		return 0, fmt.Errorf("failed to link program: %v", log)
+	}
+
+	return prog, nil
 }
 
 func InitFrame() {
@@ -109,19 +161,77 @@ func SwapWindowAndPollEvents(window *glfw.Window) {
 	window.SwapBuffers()
 }
 
+//Returns the seconds passed since the window was initialized
+func GetTime() float64 {
+	return glfw.GetTime()
+}
+
 func Draw(objects []Artist, prog uint32) {
-	gl.UseProgram(prog)
+	current := prog
+	useProgram(current)
+
+	blend := BlendNormal
+	setBlendMode(blend)
+
+	clipped := false
+	defer gl.Disable(gl.SCISSOR_TEST)
 
 	for _, obj := range objects {
-		vao, texture := obj.GetDrawInfo()
-		transformation := obj.GetTransformation()
+		if c, succ := obj.(*clippedArtist); succ {
+			scissor(c.clip)
+			clipped = true
+		} else if clipped {
+			gl.Disable(gl.SCISSOR_TEST)
+			clipped = false
+		}
 
-		tUniform := gl.GetUniformLocation(prog, gl.Str("transformation\x00"))
+		transformation := obj.GetTransformation()
+		tint := obj.GetTint()
+		material := obj.GetMaterial()
+
+		//artists with a material are drawn with the material's shader instead
+		p := prog
+		if material != nil {
+			p = material.shader.program
+		}
+		if p != current {
+			current = p
+			useProgram(current)
+		}
+
+		tUniform := gl.GetUniformLocation(current, gl.Str("transformation\x00"))
 		gl.UniformMatrix4fv(tUniform, 1, false, &transformation[0])
 
-		gl.BindTexture(gl.TEXTURE_2D, texture)
-		gl.BindVertexArray(vao)
-		gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
+		tintUniform := gl.GetUniformLocation(current, gl.Str("tint\x00"))
+
+		pUniform := gl.GetUniformLocation(current, gl.Str("premultiplied\x00"))
+
+		if material != nil {
+			material.apply()
+		}
+
+		if obj.GetBlendMode() != blend {
+			blend = obj.GetBlendMode()
+			setBlendMode(blend)
+		}
+
+		for _, m := range obj.getMeshes() {
+			t := tint
+			t[3] *= m.opacity
+			gl.Uniform4fv(tintUniform, 1, &t[0])
+
+			//the texture is premultiplied if it was loaded or drawn that way, or if the artist is given BlendPremultiplied for a
+			//texture it did not load itself
+			var premultiplied int32
+			if m.premultiplied || blend == BlendPremultiplied {
+				premultiplied = 1
+			}
+			gl.Uniform1i(pUniform, premultiplied)
+
+			gl.BindTexture(gl.TEXTURE_2D, m.texture)
+			gl.BindVertexArray(m.vao)
+			gl.DrawElements(gl.TRIANGLES, m.count, gl.UNSIGNED_INT, gl.PtrOffset(0))
+		}
 	}
 }
 
@@ -0,0 +1,350 @@
+package framework
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/koinuri/game-project/main/asset"
+	"github.com/koinuri/game-project/main/global"
+)
+
+const (
+	//Draws a quad given in screen coordinates, used by every post effect
+	postVertexShaderSource = `
+        #version 400
+        in vec3 vp;
+		in vec2 tx;
+
+		out vec2 TexCoord;
+
+        void main() {
+            gl_Position = vec4(vp, 1.0);
+			TexCoord = tx;
+        }
+    ` + "\x00"
+	blurShaderSource = `
+        #version 400
+		in vec2 TexCoord;
+
+		out vec4 frag_colour;
+
+		uniform sampler2D ourTexture;
+		uniform vec2 resolution;
+		uniform float radius;
+
+        void main() {
+            float weights[5] = float[](0.0545, 0.2442, 0.4026, 0.2442, 0.0545);
+            vec2 texel = radius / resolution;
+            vec4 sum = vec4(0.0);
+            for (int x = -2; x <= 2; x++) {
+                for (int y = -2; y <= 2; y++) {
+                    sum += texture(ourTexture, TexCoord + vec2(x, y) * texel) * weights[x + 2] * weights[y + 2];
+                }
+            }
+            frag_colour = sum;
+        }
+    ` + "\x00"
+	bloomShaderSource = `
+        #version 400
+		in vec2 TexCoord;
+
+		out vec4 frag_colour;
+
+		uniform sampler2D ourTexture;
+		uniform vec2 resolution;
+		uniform float threshold;
+		uniform float intensity;
+		uniform float radius;
+
+        void main() {
+            float weights[5] = float[](0.0545, 0.2442, 0.4026, 0.2442, 0.0545);
+            vec2 texel = radius / resolution;
+            vec3 glow = vec3(0.0);
+            for (int x = -2; x <= 2; x++) {
+                for (int y = -2; y <= 2; y++) {
+                    vec3 c = texture(ourTexture, TexCoord + vec2(x, y) * texel).rgb;
+                    glow += max(c - vec3(threshold), 0.0) * weights[x + 2] * weights[y + 2];
+                }
+            }
+            vec4 base = texture(ourTexture, TexCoord);
+            frag_colour = vec4(base.rgb + glow * intensity, base.a);
+        }
+    ` + "\x00"
+	vignetteShaderSource = `
+        #version 400
+		in vec2 TexCoord;
+
+		out vec4 frag_colour;
+
+		uniform sampler2D ourTexture;
+		uniform float radius;
+		uniform float softness;
+		uniform float strength;
+
+        void main() {
+            vec4 base = texture(ourTexture, TexCoord);
+            float vignette = smoothstep(radius, radius - softness, length(TexCoord - 0.5));
+            frag_colour = vec4(base.rgb * mix(1.0, vignette, strength), base.a);
+        }
+    ` + "\x00"
+	colorGradingShaderSource = `
+        #version 400
+		in vec2 TexCoord;
+
+		out vec4 frag_colour;
+
+		uniform sampler2D ourTexture;
+		uniform sampler2D lut;
+		uniform float intensity;
+
+        void main() {
+            vec4 base = texture(ourTexture, TexCoord);
+
+            //the lut is a strip of 16 slices of 16 by 16, with blue choosing the slice
+            float blue = clamp(base.b, 0.0, 1.0) * 15.0;
+            float slice0 = floor(blue);
+            float slice1 = min(slice0 + 1.0, 15.0);
+            vec2 uv = vec2((clamp(base.r, 0.0, 1.0) * 15.0 + 0.5) / 256.0, (clamp(base.g, 0.0, 1.0) * 15.0 + 0.5) / 16.0);
+
+            vec3 graded0 = texture(lut, uv + vec2(slice0 / 16.0, 0.0)).rgb;
+            vec3 graded1 = texture(lut, uv + vec2(slice1 / 16.0, 0.0)).rgb;
+            vec3 graded = mix(graded0, graded1, blue - slice0);
+
+            frag_colour = vec4(mix(base.rgb, graded, intensity), base.a);
+        }
+    ` + "\x00"
+	scanlineShaderSource = `
+        #version 400
+		in vec2 TexCoord;
+
+		out vec4 frag_colour;
+
+		uniform sampler2D ourTexture;
+		uniform float count;
+		uniform float intensity;
+		uniform float curvature;
+
+        void main() {
+            //bend the screen like a tube
+            vec2 uv = TexCoord - 0.5;
+            uv *= 1.0 + curvature * dot(uv, uv);
+            uv += 0.5;
+
+            if (uv.x < 0.0 || uv.x > 1.0 || uv.y < 0.0 || uv.y > 1.0) {
+                frag_colour = vec4(0.0, 0.0, 0.0, 1.0);
+                return;
+            }
+
+            vec4 base = texture(ourTexture, uv);
+            float line = sin(uv.y * count * 3.14159265) * 0.5 + 0.5;
+            frag_colour = vec4(base.rgb * (1.0 - intensity * (1.0 - line)), base.a);
+        }
+    ` + "\x00"
+)
+
+//Programs of the built in effects, compiled the first time they are used
+var postShaders = make(map[string]*Shader)
+
+func getPostShader(name, source string) *Shader {
+	if shader, succ := postShaders[name]; succ {
+		return shader
+	}
+
+	prog, err := createProgram(postVertexShaderSource, source)
+	if err != nil {
+		panic(err)
+	}
+
+	postShaders[name] = &Shader{prog}
+	return postShaders[name]
+}
+
+//Loads a fragment shader from a file to be used as a post effect.  The shader receives the drawn scene in ourTexture, the
+//size of the window in pixels in resolution, and the seconds since the window was initialized in time.
+func InitPostShader(fragment string) *Shader {
+	if fragment == "" {
+		panic("Invalid argument.  A post effect needs a fragment shader")
+	}
+
+	fs, err := loadShaderSource(fragment, fragmentShaderSource)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the fragment shader %v.\n%v", asset.Describe(fragment), err))
+	}
+
+	prog, err := createProgram(postVertexShaderSource, fs)
+	if err != nil {
+		panic(err)
+	}
+
+	s := &Shader{prog}
+	s.watch("", fragment, postVertexShaderSource)
+	return s
+}
+
+//Blurs the scene.  radius is how far apart the samples are, in pixels.
+func InitBlurEffect(radius float32) *Material {
+	m := InitMaterial(getPostShader("blur", blurShaderSource))
+	m.SetFloat("radius", radius)
+	return m
+}
+
+//Makes the parts of the scene brighter than threshold glow.  threshold is within [0, 1], intensity scales the glow, and radius
+//is how far the glow spreads, in pixels.
+func InitBloomEffect(threshold, intensity, radius float32) *Material {
+	m := InitMaterial(getPostShader("bloom", bloomShaderSource))
+	m.SetFloat("threshold", threshold)
+	m.SetFloat("intensity", intensity)
+	m.SetFloat("radius", radius)
+	return m
+}
+
+//Darkens the edges of the screen.  radius is where the darkening starts measured from the center, with 0.5 reaching the
+//sides, softness is how wide the fade is, and strength is how dark the edges get within [0, 1].
+func InitVignetteEffect(radius, softness, strength float32) *Material {
+	m := InitMaterial(getPostShader("vignette", vignetteShaderSource))
+	m.SetFloat("radius", radius)
+	m.SetFloat("softness", softness)
+	m.SetFloat("strength", strength)
+	return m
+}
+
+//Recolors the scene with a color lookup table.  The lut is a 256 by 16 image made of 16 slices of 16 by 16, where red goes
+//right, green goes down and blue picks the slice.  intensity blends between the original and the graded colors.
+func InitColorGradingEffect(lut string, intensity float32) *Material {
+	m := InitMaterial(getPostShader("colorGrading", colorGradingShaderSource))
+	m.SetTexture("lut", LoadTexture(lut))
+	m.SetFloat("intensity", intensity)
+	return m
+}
+
+//Draws the scene like an old CRT screen.  count is the number of scanlines, intensity is how dark the lines between them
+//are, and curvature is how much the screen bulges.
+func InitScanlineEffect(count, intensity, curvature float32) *Material {
+	m := InitMaterial(getPostShader("scanline", scanlineShaderSource))
+	m.SetFloat("count", count)
+	m.SetFloat("intensity", intensity)
+	m.SetFloat("curvature", curvature)
+	return m
+}
+
+//Draws the scene into an offscreen texture, then runs it through a chain of effects before it reaches the window.
+type PostProcessor struct {
+	targets [2]*RenderTarget
+	effects []*Material
+	quad    uint32
+}
+
+//Creates a post processor the size of the window.  It has to be created after the window is initialized.
+func InitPostProcessor() *PostProcessor {
+	width := int32(global.Width)
+	height := int32(global.Height)
+
+	//the quad covers the whole screen, reading the framebuffer textures the right way up
+	vec := []float32{
+		-1, 1, 0, 0.0, 1.0, //top left
+		1, 1, 0, 1.0, 1.0, //top right
+		-1, -1, 0, 0.0, 0.0, //bottom left
+		1, -1, 0, 1.0, 0.0, //bottom right
+	}
+	ind := []uint32{
+		0, 1, 2,
+		1, 2, 3,
+	}
+
+	var quad uint32
+	gl.GenVertexArrays(1, &quad)
+	gl.BindVertexArray(quad)
+
+	var vbo uint32
+	gl.GenBuffers(1, &vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vec)*4, gl.Ptr(vec), gl.STATIC_DRAW)
+
+	var ebo uint32
+	gl.GenBuffers(1, &ebo)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(ind)*4, gl.Ptr(ind), gl.STATIC_DRAW)
+
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 5*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 5*4, gl.PtrOffset(3*4))
+	gl.EnableVertexAttribArray(1)
+
+	return &PostProcessor{
+		targets: [2]*RenderTarget{
+			InitRenderTarget(width, height, 1600, 900),
+			InitRenderTarget(width, height, 1600, 900),
+		},
+		effects: make([]*Material, 0),
+		quad:    quad,
+	}
+}
+
+//Adds an effect at the end of the chain.
+func (p *PostProcessor) AddEffect(effect *Material) {
+	p.effects = append(p.effects, effect)
+}
+
+func (p *PostProcessor) RemoveEffect(effect *Material) {
+	for i, e := range p.effects {
+		if e == effect {
+			p.effects = append(p.effects[:i], p.effects[i+1:]...)
+			return
+		}
+	}
+}
+
+//Redirects drawing into the post processor.  Call it after InitFrame and before drawing the scene.
+func (p *PostProcessor) Begin() {
+	if len(p.effects) == 0 {
+		return
+	}
+
+	p.targets[0].bind()
+	clear()
+}
+
+//Runs the scene through every effect and draws the result into the window.  Call it after drawing the scene and before
+//SwapWindowAndPollEvents.
+func (p *PostProcessor) End() {
+	if len(p.effects) == 0 {
+		return
+	}
+	p.targets[0].End()
+
+	//effects replace the pixels instead of blending them
+	gl.Disable(gl.BLEND)
+	gl.BindVertexArray(p.quad)
+
+	source := 0
+	for i, effect := range p.effects {
+		last := i == len(p.effects)-1
+
+		//every effect but the last draws into the other target, and the last one draws into the window
+		if !last {
+			p.targets[1-source].bind()
+		}
+
+		prog := effect.shader.program
+		gl.UseProgram(prog)
+
+		resUniform := gl.GetUniformLocation(prog, gl.Str("resolution\x00"))
+		gl.Uniform2f(resUniform, float32(global.Width), float32(global.Height))
+
+		timeUniform := gl.GetUniformLocation(prog, gl.Str("time\x00"))
+		gl.Uniform1f(timeUniform, float32(GetTime()))
+
+		effect.apply()
+
+		gl.BindTexture(gl.TEXTURE_2D, p.targets[source].texture)
+		gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, gl.PtrOffset(0))
+
+		if !last {
+			p.targets[1-source].End()
+			source = 1 - source
+		}
+	}
+
+	gl.Enable(gl.BLEND)
+}
@@ -1,8 +1,30 @@
 package framework
 
+import "math"
+
 type Bound struct {
 	Left   float32
 	Right  float32
 	Up     float32
 	Bottom float32
 }
+
+//Returns true if the two bounds share any area
+func (b Bound) Overlaps(o Bound) bool {
+	return b.Left < o.Right && o.Left < b.Right && b.Bottom < o.Up && o.Bottom < b.Up
+}
+
+//Returns true if the point is inside the bound
+func (b Bound) Contains(x, y float32) bool {
+	return x >= b.Left && x <= b.Right && y >= b.Bottom && y <= b.Up
+}
+
+//Returns the smallest bound holding both bounds
+func (b Bound) Union(o Bound) Bound {
+	return Bound{
+		float32(math.Min(float64(b.Left), float64(o.Left))),
+		float32(math.Max(float64(b.Right), float64(o.Right))),
+		float32(math.Max(float64(b.Up), float64(o.Up))),
+		float32(math.Min(float64(b.Bottom), float64(o.Bottom))),
+	}
+}
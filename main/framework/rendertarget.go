@@ -0,0 +1,162 @@
+package framework
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Offscreen framebuffer that artists can be drawn into instead of the window.  The texture it draws into can be shown with a
+//sprite or read by post effects.
+type RenderTarget struct {
+	fbo         uint32
+	texture     uint32
+	width       int32
+	height      int32
+	areaWidth   float32
+	areaHeight  float32
+	previous    mgl32.Mat4
+	prevFbo     int32
+	prevView    [4]int32
+	isRendering bool
+}
+
+//Creates a render target.
+//	*InitRenderTarget(width, height)
+//	*InitRenderTarget(width, height, areaWidth, areaHeight)
+//Where:
+//	width and height are the size of the texture in pixels.
+//	areaWidth and areaHeight are the size of the area around (0, 0) that is drawn into the texture, in the same units as the
+//	window's 1600 by 900 coordinate system.  They are defaulted to width and height.
+func InitRenderTarget(width, height int32, area ...float32) *RenderTarget {
+	if width <= 0 || height <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive size, found %v by %v", width, height))
+	}
+
+	areaWidth := float32(width)
+	areaHeight := float32(height)
+	switch len(area) {
+	case 0:
+	case 2:
+		areaWidth = area[0]
+		areaHeight = area[1]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected 0 or 2 float32 for the area, found %v.", len(area)))
+	}
+
+	//create the texture the framebuffer draws into
+	var texture uint32
+	gl.GenTextures(1, &texture)
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+
+	var fbo uint32
+	gl.GenFramebuffers(1, &fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, texture, 0)
+
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		panic(fmt.Sprintf("Could not create the render target.  Framebuffer status was 0x%x", status))
+	}
+
+	return &RenderTarget{
+		fbo:        fbo,
+		texture:    texture,
+		width:      width,
+		height:     height,
+		areaWidth:  areaWidth,
+		areaHeight: areaHeight,
+	}
+}
+
+//Redirects drawing into the render target and clears it to transparent.
+func (r *RenderTarget) Begin() {
+	r.bind()
+
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+}
+
+//Redirects drawing back to where it was going before Begin, which is usually the window.
+func (r *RenderTarget) End() {
+	if !r.isRendering {
+		panic("Invalid call.  End was called without calling Begin first")
+	}
+
+	projection = r.previous
+	r.isRendering = false
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(r.prevFbo))
+	gl.Viewport(r.prevView[0], r.prevView[1], r.prevView[2], r.prevView[3])
+}
+
+//Binds the framebuffer and switches the projection to the area of the render target
+func (r *RenderTarget) bind() {
+	if r.isRendering {
+		panic("Invalid call.  Begin was called twice without calling End")
+	}
+
+	//remember where drawing was going, so render targets can be drawn while drawing into another one
+	r.previous = projection
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &r.prevFbo)
+	gl.GetIntegerv(gl.VIEWPORT, &r.prevView[0])
+	r.isRendering = true
+	projection = mgl32.Ortho2D(-r.areaWidth/2, r.areaWidth/2, -r.areaHeight/2, r.areaHeight/2)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, r.fbo)
+	gl.Viewport(0, 0, r.width, r.height)
+}
+
+//Draws the artists into the render target, replacing what it held before.
+func (r *RenderTarget) Draw(artists []Artist, prog uint32) {
+	r.Begin()
+	Draw(artists, prog)
+	r.End()
+}
+
+func (r *RenderTarget) GetTexture() uint32 {
+	return r.texture
+}
+
+func (r *RenderTarget) GetSize() (int32, int32) {
+	return r.width, r.height
+}
+
+//Creates a sprite showing what was drawn into the render target.  The sprite keeps showing the latest drawing.
+//	*CreateSprite(canvas)
+//	*CreateSprite(canvas, origin)
+//Where:
+//	canvas is the container the sprite is fitted in, such as the one returned by Object.GetCanvas.
+//	origin is where the coordinate system of the sprite is based on.  It is defaulted to the center.
+func (r *RenderTarget) CreateSprite(canvas Canvas, or ...origin) Sprite {
+	o := Center
+	switch len(or) {
+	case 0:
+	case 1:
+		o = or[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one origin, found %v.", len(or)))
+	}
+
+	//OpenGL stores the first row of a framebuffer at the bottom, so the texture is read upside down compared to images
+	spr := createSprite(r.texture, r.areaWidth, r.areaHeight, canvas, o, mgl32.Vec4{0, 1, 1, 0})
+
+	//everything is drawn premultiplied, so the texture already is
+	spr.blend = BlendPremultiplied
+	spr.premultiplied = true
+
+	return spr
+}
+
+//Frees the framebuffer and its texture.  Sprites created from the render target must not be drawn afterwards.
+func (r *RenderTarget) Delete() {
+	gl.DeleteFramebuffers(1, &r.fbo)
+	gl.DeleteTextures(1, &r.texture)
+}
@@ -0,0 +1,109 @@
+package framework
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+//Reads what has been drawn into the window this frame, shrunk to the size given, such as for the thumbnail of a save.
+//Call it after drawing and before SwapWindowAndPollEvents.
+//	*Screenshot()
+//	*Screenshot(width, height)
+//Where:
+//	width and height are the size of the image in pixels.  They are defaulted to the size of the viewport.
+func Screenshot(size ...int) *image.NRGBA {
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	vw, vh := int(viewport[2]), int(viewport[3])
+
+	width, height := vw, vh
+	switch len(size) {
+	case 0:
+	case 2:
+		width, height = size[0], size[1]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected a width and a height, found %v.", len(size)))
+	}
+	if width <= 0 || height <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a size above 0, found %vx%v", width, height))
+	}
+
+	pix := make([]uint8, vw*vh*4)
+	if len(pix) > 0 {
+		gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+		gl.ReadPixels(viewport[0], viewport[1], viewport[2], viewport[3], gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pix))
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		//OpenGL reads the bottom row first, so rows are taken from the end
+		y0, y1 := span(y, height, vh)
+		for x := 0; x < width; x++ {
+			x0, x1 := span(x, width, vw)
+
+			//every pixel is the average of the pixels of the window it covers
+			var sum [3]int
+			count := 0
+			for sy := y0; sy < y1; sy++ {
+				row := (vh - 1 - sy) * vw * 4
+				for sx := x0; sx < x1; sx++ {
+					i := row + sx*4
+					sum[0] += int(pix[i])
+					sum[1] += int(pix[i+1])
+					sum[2] += int(pix[i+2])
+					count++
+				}
+			}
+
+			i := img.PixOffset(x, y)
+			if count > 0 {
+				img.Pix[i] = uint8(sum[0] / count)
+				img.Pix[i+1] = uint8(sum[1] / count)
+				img.Pix[i+2] = uint8(sum[2] / count)
+			}
+			//the window itself is opaque, whatever alpha was left in it
+			img.Pix[i+3] = 255
+		}
+	}
+	return img
+}
+
+//Returns the first and last plus one of the pixels of the source length covered by the pixel of the target length
+func span(i, target, source int) (int, int) {
+	start := i * source / target
+	end := (i + 1) * source / target
+	if end <= start {
+		end = start + 1
+	}
+	if end > source {
+		end = source
+	}
+	return start, end
+}
+
+//Creates a sprite showing an image made while the game runs, such as a screenshot or the thumbnail of a save.
+//	*InitImageSprite(img, canvas)
+//	*InitImageSprite(img, canvas, origin)
+//Where:
+//	canvas is the container the sprite is fitted in, such as the one returned by Object.GetCanvas.
+//	origin is where the coordinate system of the sprite is based on.  It is defaulted to the center.
+func InitImageSprite(img image.Image, canvas Canvas, or ...origin) Sprite {
+	if img == nil {
+		panic("Invalid argument.  The image cannot be nil")
+	}
+
+	o := Center
+	switch len(or) {
+	case 0:
+	case 1:
+		o = or[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one origin, found %v.", len(or)))
+	}
+
+	options := GetDefaultTextureOptions()
+	pix, width, height := rasterize(img, options.Premultiply)
+	return createTextureSprite(pix, width, height, canvas, o, options)
+}
@@ -0,0 +1,522 @@
+package framework
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/koinuri/game-project/main/asset"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+//Typeface at one size, used to draw Text.
+type Font struct {
+	face    font.Face
+	size    float64
+	version int
+}
+
+var defaultFont *Font
+
+//Loads a TrueType or OpenType font.
+//	*LoadFont(dir, size)
+//Where:
+//	dir is the location of the .ttf or .otf file, relative to the executable file.
+//	size is the height of the letters in units of the window's 1600 by 900 coordinate system.
+func LoadFont(dir string, size float64) *Font {
+	if size <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive size, found %v", size))
+	}
+
+	face, err := loadFace(dir, size)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the font %v.\n%v", asset.Describe(dir), err))
+	}
+
+	f := &Font{face: face, size: size}
+	WatchAsset(dir, func() error {
+		face, err := loadFace(dir, size)
+		if err != nil {
+			return err
+		}
+		f.face = face
+		//texts drawn with the font see the new version and draw themselves again
+		f.version++
+		return nil
+	})
+	return f
+}
+
+func loadFace(dir string, size float64) (font.Face, error) {
+	data, err := asset.ReadFile(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	//72 dots per inch makes a point one unit
+	return opentype.NewFace(parsed, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+//Returns the 7 by 13 pixel font built into the game, for when no font file is at hand
+func DefaultFont() *Font {
+	if defaultFont == nil {
+		defaultFont = &Font{face: basicfont.Face7x13, size: 13}
+	}
+	return defaultFont
+}
+
+func (f *Font) GetSize() float64 {
+	return f.size
+}
+
+//Returns the distance between the tops of two lines
+func (f *Font) GetLineHeight() float64 {
+	return float64(f.face.Metrics().Height.Ceil())
+}
+
+//Returns the width of the widest line of the text and the height of all its lines
+func (f *Font) Measure(text string) (float64, float64) {
+	lines := strings.Split(text, "\n")
+
+	var width fixed.Int26_6
+	for _, l := range lines {
+		if w := font.MeasureString(f.face, l); w > width {
+			width = w
+		}
+	}
+	return float64(width.Ceil()), f.GetLineHeight() * float64(len(lines))
+}
+
+type textAlignment uint32
+
+const (
+	AlignLeft textAlignment = iota
+	AlignCenter
+	AlignRight
+)
+
+//Artist that draws a string with a font.  The string is drawn into a texture of its own whenever it changes, so changing it
+//every frame is slower than moving it.  Lines are broken at "\n", and at spaces as well once a wrap width is set, or
+//between letters when a word does not fit on a line of its own.
+type Text struct {
+	font           *Font
+	text           string
+	lines          []string
+	wrap           float32
+	align          textAlignment
+	version        int
+	x              float32
+	y              float32
+	width          float32
+	height         float32
+	ox             float32
+	oy             float32
+	px             float32
+	py             float32
+	ax             float32
+	ay             float32
+	scalex         float32
+	scaley         float32
+	angle          float32
+	r              float32
+	g              float32
+	b              float32
+	alpha          float32
+	blend          blendMode
+	material       *Material
+	vao            uint32
+	vbo            uint32
+	ebo            uint32
+	texture        uint32
+	transformation transformation
+}
+
+//Creates a text.
+//	*InitText(text)
+//	*InitText(text, font)
+//Where:
+//	font is the font the text is drawn with.  It is defaulted to DefaultFont.
+//The text is white until SetColor is called, and placed by its top left corner.
+func InitText(text string, f ...*Font) *Text {
+	t := &Text{
+		font:           DefaultFont(),
+		text:           text,
+		px:             0,
+		py:             0,
+		ax:             .5,
+		ay:             .5,
+		scalex:         1,
+		scaley:         1,
+		r:              1,
+		g:              1,
+		b:              1,
+		alpha:          1,
+		blend:          BlendNormal,
+		transformation: InitTransformation(),
+	}
+
+	switch len(f) {
+	case 0:
+	case 1:
+		if f[0] == nil {
+			panic("Invalid argument.  The font cannot be nil")
+		}
+		t.font = f[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one font, found %v.", len(f)))
+	}
+
+	gl.GenTextures(1, &t.texture)
+	t.vao, t.vbo, t.ebo = createQuad(0, 0, mgl32.Vec4{0, 0, 1, 1})
+	t.render()
+	return t
+}
+
+func (t *Text) SetText(text string) {
+	if text == t.text {
+		return
+	}
+	t.text = text
+	t.render()
+}
+
+func (t *Text) GetText() string {
+	return t.text
+}
+
+func (t *Text) SetFont(f *Font) {
+	if f == nil {
+		panic("Invalid argument.  The font cannot be nil")
+	}
+	t.font = f
+	t.render()
+}
+
+func (t *Text) GetFont() *Font {
+	return t.font
+}
+
+//Sets the width lines are broken at, at the last space before it, or at the last letter before it for words wider than
+//it and text without spaces such as Japanese.  0 only breaks lines at "\n".
+func (t *Text) SetWrapWidth(width float64) {
+	t.wrap = float32(width)
+	t.render()
+}
+
+func (t *Text) GetWrapWidth() float64 {
+	return float64(t.wrap)
+}
+
+//Sets how the lines are lined up with each other, AlignLeft, AlignCenter or AlignRight
+func (t *Text) SetAlignment(align textAlignment) {
+	t.align = align
+	t.render()
+}
+
+func (t *Text) GetAlignment() textAlignment {
+	return t.align
+}
+
+//Returns the size of the text in units of the window's 1600 by 900 coordinate system, before it is scaled
+func (t *Text) GetSize() (float64, float64) {
+	t.refresh()
+	return float64(t.width), float64(t.height)
+}
+
+//Returns the lines the text is drawn in, after wrapping
+func (t *Text) GetLines() []string {
+	t.refresh()
+	return t.lines
+}
+
+//Breaks the text into lines
+func (t *Text) layout() []string {
+	lines := make([]string, 0)
+	for _, paragraph := range strings.Split(t.text, "\n") {
+		if t.wrap <= 0 {
+			lines = append(lines, paragraph)
+			continue
+		}
+
+		line := ""
+		for _, word := range strings.Split(paragraph, " ") {
+			next := word
+			if line != "" {
+				next = line + " " + word
+			}
+			if t.fits(next) {
+				line = next
+				continue
+			}
+
+			if line != "" {
+				lines = append(lines, line)
+				next = word
+			}
+			//words wider than the wrap width, and text written without spaces such as Japanese, are broken between runes
+			for !t.fits(next) {
+				cut := t.fitting(next)
+				lines = append(lines, next[:cut])
+				next = next[cut:]
+			}
+			line = next
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+//Returns true if the string is no wider than the wrap width
+func (t *Text) fits(s string) bool {
+	return float32(font.MeasureString(t.font.face, s).Ceil()) <= t.wrap
+}
+
+//Returns the length in bytes of the most runes at the start of the string that fit in the wrap width, which is at least
+//one rune so every line holds something
+func (t *Text) fitting(s string) int {
+	cut := 0
+	for i, r := range s {
+		end := i + utf8.RuneLen(r)
+		if cut > 0 && !t.fits(s[:end]) {
+			break
+		}
+		cut = end
+	}
+	return cut
+}
+
+//Draws the text again if its font changed since it was last drawn, such as when the font file is reloaded
+func (t *Text) refresh() {
+	if t.version != t.font.version {
+		t.render()
+	}
+}
+
+//Draws the text into its texture
+func (t *Text) render() {
+	t.version = t.font.version
+	t.lines = t.layout()
+
+	face := t.font.face
+	lineHeight := face.Metrics().Height.Ceil()
+	ascent := face.Metrics().Ascent.Ceil()
+
+	width := 0
+	for _, l := range t.lines {
+		if w := font.MeasureString(face, l).Ceil(); w > width {
+			width = w
+		}
+	}
+	height := lineHeight * len(t.lines)
+
+	t.width = float32(width)
+	t.height = float32(height)
+	if width == 0 || height == 0 || t.vao == 0 {
+		return
+	}
+
+	//white letters over nothing, colored by the tint
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	d := font.Drawer{Dst: img, Src: image.White, Face: face}
+	for i, l := range t.lines {
+		x := 0
+		switch t.align {
+		case AlignCenter:
+			x = (width - font.MeasureString(face, l).Ceil()) / 2
+		case AlignRight:
+			x = width - font.MeasureString(face, l).Ceil()
+		}
+		d.Dot = fixed.P(x, i*lineHeight+ascent)
+		d.DrawString(l)
+	}
+
+	pix, w, h := rasterize(img, false)
+	uploadTexture(t.texture, pix, w, h, TextureOptions{Filter: FilterLinear, Wrap: WrapClamp, Mipmaps: MipmapsOff, Anisotropy: 1})
+
+	vec := quadVertices(t.width, t.height, mgl32.Vec4{0, 0, 1, 1})
+	gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vec)*4, gl.Ptr(vec), gl.DYNAMIC_DRAW)
+}
+
+func (t *Text) updateOrigin() {
+	//whole units keep the letters from blurring between pixels
+	ox, oy := pivotCenter(t.x, t.y, t.width*t.scalex, t.height*t.scaley, t.px, t.py, t.angle)
+	if t.angle == 0 && t.scalex == 1 && t.scaley == 1 {
+		ox = float32(math.Round(float64(ox-t.width/2))) + t.width/2
+		oy = float32(math.Round(float64(oy-t.height/2))) + t.height/2
+	}
+	t.ox, t.oy = ox, oy
+}
+
+//Sets the point of the text that is placed at its position, where (0, 0) is the top left corner and (1, 1) is the bottom
+//right corner.  It is defaulted to the top left corner.
+func (t *Text) SetPivot(x, y float64) {
+	t.px = float32(x)
+	t.py = float32(y)
+}
+
+func (t *Text) GetPivot() (float64, float64) {
+	return float64(t.px), float64(t.py)
+}
+
+//Sets the pivot to one of the nine origins, such as Center
+func (t *Text) SetOrigin(or origin) {
+	t.px, t.py = or.pivot()
+}
+
+//Sets the point of the rectangle of the object holding the text that its position is measured from.  It is defaulted to
+//the center.
+func (t *Text) SetAnchor(x, y float64) {
+	t.ax = float32(x)
+	t.ay = float32(y)
+}
+
+func (t *Text) GetAnchor() (float64, float64) {
+	return float64(t.ax), float64(t.ay)
+}
+
+//Frees the texture and the buffers of the text, once it is not needed anymore.  The text draws nothing afterwards.
+func (t *Text) Delete() {
+	if t.vao == 0 {
+		return
+	}
+
+	gl.DeleteTextures(1, &t.texture)
+	gl.DeleteBuffers(1, &t.vbo)
+	gl.DeleteBuffers(1, &t.ebo)
+	gl.DeleteVertexArrays(1, &t.vao)
+	t.texture, t.vao, t.vbo, t.ebo = 0, 0, 0, 0
+}
+
+//Returns the vao and texture held by the text to draw
+func (t *Text) GetDrawInfo() (uint32, uint32) {
+	return t.vao, t.texture
+}
+
+func (t *Text) getMeshes() []mesh {
+	if t.vao == 0 {
+		return nil
+	}
+	t.refresh()
+	if t.width == 0 || t.height == 0 {
+		return nil
+	}
+	return []mesh{{t.vao, t.texture, 6, 1, false}}
+}
+
+func (t *Text) GetTransformation() mgl32.Mat4 {
+	t.updateOrigin()
+
+	t.transformation.translation = mgl32.Translate3D(t.ox, t.oy, 0)
+	return t.transformation.translation.Mul4(t.transformation.rotation.Mul4(t.transformation.scale))
+}
+
+func (t *Text) GetPosition() (float64, float64) {
+	return float64(t.x), float64(t.y)
+}
+
+func (t *Text) GetScale() (float64, float64) {
+	return float64(t.scalex), float64(t.scaley)
+}
+
+func (t *Text) GetAngle() float64 {
+	return float64(t.angle)
+}
+
+func (t *Text) Move(x, y float64) {
+	t.x = float32(x)
+	t.y = float32(y)
+}
+
+func (t *Text) Scale(v ...float64) {
+	switch len(v) {
+	case 1:
+		t.scalex = float32(v[0])
+		t.scaley = float32(v[0])
+	case 2:
+		t.scalex = float32(v[0])
+		t.scaley = float32(v[1])
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected either 1 or 2 float64, found %v", len(v)))
+	}
+
+	t.transformation.scale = mgl32.Diag4(mgl32.Vec4{t.scalex, t.scaley, 1, 1})
+}
+
+func (t *Text) RadianRotate(angle float64) {
+	t.angle = float32(angle)
+
+	t.transformation.rotation = mgl32.HomogRotate3DZ(t.angle)
+}
+
+func (t *Text) AngleRotate(angle float64) {
+	t.RadianRotate(angle * (math.Pi / 180))
+}
+
+func (t *Text) GetTint() mgl32.Vec4 {
+	return mgl32.Vec4{t.r, t.g, t.b, t.alpha}
+}
+
+func (t *Text) SetColor(r, g, b float64) {
+	t.r = float32(r)
+	t.g = float32(g)
+	t.b = float32(b)
+}
+
+func (t *Text) GetColor() (float64, float64, float64) {
+	return float64(t.r), float64(t.g), float64(t.b)
+}
+
+func (t *Text) SetAlpha(a float64) {
+	t.alpha = float32(a)
+}
+
+func (t *Text) GetAlpha() float64 {
+	return float64(t.alpha)
+}
+
+func (t *Text) SetBlendMode(b blendMode) {
+	t.blend = b
+}
+
+func (t *Text) GetBlendMode() blendMode {
+	return t.blend
+}
+
+func (t *Text) SetMaterial(m *Material) {
+	t.material = m
+}
+
+func (t *Text) GetMaterial() *Material {
+	return t.material
+}
+
+func (t *Text) applyTransformations(x, y, scalex, scaley, angle float32, tint mgl32.Vec4) Artist {
+	//drawn again before it is copied, so the text itself knows its new lines and size rather than only the copy
+	t.refresh()
+	tx := *t
+
+	tx.r *= tint[0]
+	tx.g *= tint[1]
+	tx.b *= tint[2]
+	tx.alpha *= tint[3]
+
+	tx.Scale(float64(tx.scalex*scalex), float64(tx.scaley*scaley))
+	tx.RadianRotate(float64(tx.angle + angle))
+	tx.Move(float64(tx.x+x), float64(tx.y+y))
+
+	return &tx
+}
@@ -0,0 +1,155 @@
+package framework
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+type textureFilter uint32
+
+const (
+	//Uses the filter of the default texture options
+	FilterDefault textureFilter = iota
+	//Takes the color of the closest pixel, which keeps pixel art sharp
+	FilterNearest
+	//Blends the four closest pixels
+	FilterLinear
+	//Blends the four closest pixels of the two closest mipmaps, which keeps scaled down textures smooth.  It always builds
+	//mipmaps.
+	FilterTrilinear
+)
+
+type textureWrap uint32
+
+const (
+	//Uses the wrap mode of the default texture options
+	WrapDefault textureWrap = iota
+	//Stretches the edge pixels past the edges, so the edges of sprites do not bleed into the opposite side
+	WrapClamp
+	//Tiles the texture
+	WrapRepeat
+	//Tiles the texture, flipping every other tile
+	WrapMirror
+)
+
+type textureMipmaps uint32
+
+const (
+	//Uses the mipmaps setting of the default texture options
+	MipmapsDefault textureMipmaps = iota
+	//Builds smaller copies of the texture used when it is drawn scaled down
+	MipmapsOn
+	MipmapsOff
+)
+
+var defaultTextureOptions = struct {
+	mutex   sync.Mutex
+	options TextureOptions
+}{options: TextureOptions{
+	Filter:     FilterLinear,
+	Wrap:       WrapRepeat,
+	Mipmaps:    MipmapsOff,
+	Anisotropy: 1,
+}}
+
+//Sets the options of the textures loaded afterwards.  Textures loaded without TextureOptions use them as they are, and the
+//sampling fields left at their defaults in the TextureOptions of other textures are taken from them.  They start as
+//FilterLinear, WrapRepeat, MipmapsOff and an anisotropy of 1.  A game made of pixel art would set FilterNearest here.
+func SetDefaultTextureOptions(options TextureOptions) {
+	if options.Filter == FilterDefault || options.Wrap == WrapDefault || options.Mipmaps == MipmapsDefault {
+		panic("Invalid argument.  The default texture options cannot refer to the defaults")
+	}
+	if options.Anisotropy < 1 {
+		panic(fmt.Sprintf("Invalid argument.  Expected an anisotropy of 1 or more, found %v", options.Anisotropy))
+	}
+
+	defaultTextureOptions.mutex.Lock()
+	defer defaultTextureOptions.mutex.Unlock()
+	defaultTextureOptions.options = options
+}
+
+func GetDefaultTextureOptions() TextureOptions {
+	defaultTextureOptions.mutex.Lock()
+	defer defaultTextureOptions.mutex.Unlock()
+	return defaultTextureOptions.options
+}
+
+//Returns the options of a texture loaded with the options given, which can be none or one
+func textureOptions(options []TextureOptions) TextureOptions {
+	switch len(options) {
+	case 0:
+		return GetDefaultTextureOptions()
+	case 1:
+		return withDefaults(options[0])
+	}
+	panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one TextureOptions, found %v.", len(options)))
+}
+
+//Fills the sampling fields left at their defaults
+func withDefaults(options TextureOptions) TextureOptions {
+	def := GetDefaultTextureOptions()
+	if options.Filter == FilterDefault {
+		options.Filter = def.Filter
+	}
+	if options.Wrap == WrapDefault {
+		options.Wrap = def.Wrap
+	}
+	if options.Mipmaps == MipmapsDefault {
+		options.Mipmaps = def.Mipmaps
+	}
+	if options.Anisotropy == 0 {
+		options.Anisotropy = def.Anisotropy
+	}
+	return options
+}
+
+//Sets how the bound texture is sampled, and builds its mipmaps if it uses them.  The options must have been filled by
+//withDefaults.
+func applySampling(options TextureOptions) {
+	var wrap int32 = gl.REPEAT
+	switch options.Wrap {
+	case WrapClamp:
+		wrap = gl.CLAMP_TO_EDGE
+	case WrapMirror:
+		wrap = gl.MIRRORED_REPEAT
+	}
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, wrap)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, wrap)
+
+	mipmaps := options.Mipmaps == MipmapsOn || options.Filter == FilterTrilinear
+
+	var min, mag int32 = gl.LINEAR, gl.LINEAR
+	switch options.Filter {
+	case FilterNearest:
+		min, mag = gl.NEAREST, gl.NEAREST
+		if mipmaps {
+			min = gl.NEAREST_MIPMAP_NEAREST
+		}
+	case FilterTrilinear:
+		min = gl.LINEAR_MIPMAP_LINEAR
+	default:
+		if mipmaps {
+			min = gl.LINEAR_MIPMAP_NEAREST
+		}
+	}
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, min)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, mag)
+
+	//anisotropy is an extension before OpenGL 4.6, and the maximum is left at 0 by drivers without it
+	if options.Anisotropy > 1 {
+		var max float32
+		gl.GetFloatv(gl.MAX_TEXTURE_MAX_ANISOTROPY, &max)
+		if max >= 1 {
+			if options.Anisotropy > max {
+				options.Anisotropy = max
+			}
+			gl.TexParameterf(gl.TEXTURE_2D, gl.TEXTURE_MAX_ANISOTROPY, options.Anisotropy)
+		}
+	}
+
+	if mipmaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+	}
+}
@@ -0,0 +1,706 @@
+package framework
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/koinuri/game-project/main/asset"
+)
+
+//Layout of the .tmx and .tsx files
+type tmxMap struct {
+	Orientation string        `xml:"orientation,attr"`
+	Width       int           `xml:"width,attr"`
+	Height      int           `xml:"height,attr"`
+	TileWidth   int           `xml:"tilewidth,attr"`
+	TileHeight  int           `xml:"tileheight,attr"`
+	Infinite    int           `xml:"infinite,attr"`
+	Properties  []tmxProperty `xml:"properties>property"`
+	Tilesets    []tmxTileset  `xml:"tileset"`
+	Layers      []tmxLayer    `xml:",any"`
+}
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"`
+}
+
+type tmxTileset struct {
+	FirstGid   uint32        `xml:"firstgid,attr"`
+	Source     string        `xml:"source,attr"`
+	Name       string        `xml:"name,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	Spacing    int           `xml:"spacing,attr"`
+	Margin     int           `xml:"margin,attr"`
+	TileCount  int           `xml:"tilecount,attr"`
+	Columns    int           `xml:"columns,attr"`
+	Image      tmxImage      `xml:"image"`
+	Tiles      []tmxTile     `xml:"tile"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+type tmxTile struct {
+	ID         uint32        `xml:"id,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Animation  []tmxFrame    `xml:"animation>frame"`
+	Image      *tmxImage     `xml:"image"`
+}
+
+type tmxFrame struct {
+	TileID   uint32 `xml:"tileid,attr"`
+	Duration int    `xml:"duration,attr"`
+}
+
+//Tile layers, object groups and groups all share this layout, told apart by the name of the element
+type tmxLayer struct {
+	XMLName    xml.Name
+	Name       string        `xml:"name,attr"`
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Opacity    *float64      `xml:"opacity,attr"`
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Data       tmxData       `xml:"data"`
+	Objects    []tmxObject   `xml:"object"`
+	Layers     []tmxLayer    `xml:",any"`
+}
+
+type tmxData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Text        string `xml:",chardata"`
+	Tiles       []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+}
+
+type tmxObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	Rotation   float64       `xml:"rotation,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Ellipse    *struct{}     `xml:"ellipse"`
+	Point      *struct{}     `xml:"point"`
+	Polygon    *tmxPoints    `xml:"polygon"`
+	Polyline   *tmxPoints    `xml:"polyline"`
+}
+
+type tmxPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+//Layout of the .tmj and .tsj files
+type tmjMap struct {
+	Orientation string        `json:"orientation"`
+	Width       int           `json:"width"`
+	Height      int           `json:"height"`
+	TileWidth   int           `json:"tilewidth"`
+	TileHeight  int           `json:"tileheight"`
+	Infinite    bool          `json:"infinite"`
+	Properties  []tmjProperty `json:"properties"`
+	Tilesets    []tmjTileset  `json:"tilesets"`
+	Layers      []tmjLayer    `json:"layers"`
+}
+
+type tmjProperty struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+type tmjTileset struct {
+	FirstGid    uint32        `json:"firstgid"`
+	Source      string        `json:"source"`
+	Name        string        `json:"name"`
+	TileWidth   int           `json:"tilewidth"`
+	TileHeight  int           `json:"tileheight"`
+	Spacing     int           `json:"spacing"`
+	Margin      int           `json:"margin"`
+	TileCount   int           `json:"tilecount"`
+	Columns     int           `json:"columns"`
+	Image       string        `json:"image"`
+	ImageWidth  int           `json:"imagewidth"`
+	ImageHeight int           `json:"imageheight"`
+	Tiles       []tmjTile     `json:"tiles"`
+	Properties  []tmjProperty `json:"properties"`
+}
+
+type tmjTile struct {
+	ID         uint32        `json:"id"`
+	Properties []tmjProperty `json:"properties"`
+	Animation  []struct {
+		TileID   uint32 `json:"tileid"`
+		Duration int    `json:"duration"`
+	} `json:"animation"`
+	Image string `json:"image"`
+}
+
+type tmjLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Visible     *bool           `json:"visible"`
+	Opacity     *float64        `json:"opacity"`
+	OffsetX     float64         `json:"offsetx"`
+	OffsetY     float64         `json:"offsety"`
+	Properties  []tmjProperty   `json:"properties"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Data        json.RawMessage `json:"data"`
+	Objects     []tmjObject     `json:"objects"`
+	Layers      []tmjLayer      `json:"layers"`
+}
+
+type tmjObject struct {
+	ID         int           `json:"id"`
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	Class      string        `json:"class"`
+	X          float64       `json:"x"`
+	Y          float64       `json:"y"`
+	Width      float64       `json:"width"`
+	Height     float64       `json:"height"`
+	Rotation   float64       `json:"rotation"`
+	GID        uint32        `json:"gid"`
+	Visible    *bool         `json:"visible"`
+	Ellipse    bool          `json:"ellipse"`
+	Point      bool          `json:"point"`
+	Polygon    []MapPoint    `json:"polygon"`
+	Polyline   []MapPoint    `json:"polyline"`
+	Properties []tmjProperty `json:"properties"`
+}
+
+//Visibility and opacity handed down from group layers
+type layerParent struct {
+	visible bool
+	opacity float64
+	offsetx float64
+	offsety float64
+}
+
+//Loads a .tmx or .tmj map, without creating any textures
+func loadTiledMap(dir string) (*tilemapData, error) {
+	data, err := asset.ReadFile(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(path.Ext(dir)) == ".tmx" {
+		var m tmxMap
+		if err := xml.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		return convertTmx(m, path.Dir(dir))
+	}
+
+	var m tmjMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return convertTmj(m, path.Dir(dir))
+}
+
+func newTilemapData(orientation string, infinite bool, width, height, tileWidth, tileHeight int) (*tilemapData, error) {
+	if orientation != "" && orientation != "orthogonal" {
+		return nil, fmt.Errorf("only orthogonal maps are supported, found %v", orientation)
+	}
+	if infinite {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+
+	return &tilemapData{
+		width:        width,
+		height:       height,
+		tileWidth:    tileWidth,
+		tileHeight:   tileHeight,
+		tilesets:     make([]*tileset, 0),
+		layers:       make([]*TileLayer, 0),
+		objectLayers: make([]*ObjectLayer, 0),
+	}, nil
+}
+
+func convertTmx(m tmxMap, base string) (*tilemapData, error) {
+	t, err := newTilemapData(m.Orientation, m.Infinite != 0, m.Width, m.Height, m.TileWidth, m.TileHeight)
+	if err != nil {
+		return nil, err
+	}
+	t.properties = convertTmxProperties(m.Properties)
+
+	for _, ts := range m.Tilesets {
+		dir := base
+		firstGid := ts.FirstGid
+
+		//external tilesets keep the first gid in the map and everything else in their own file
+		if ts.Source != "" {
+			source := path.Join(base, ts.Source)
+			data, err := asset.ReadFile(source)
+			if err != nil {
+				return nil, err
+			}
+			if err := xml.Unmarshal(data, &ts); err != nil {
+				return nil, err
+			}
+			dir = path.Dir(source)
+		}
+
+		set, err := convertTmxTileset(ts, firstGid, dir)
+		if err != nil {
+			return nil, err
+		}
+		t.tilesets = append(t.tilesets, set)
+	}
+
+	if err := t.addTmxLayers(m.Layers, layerParent{true, 1, 0, 0}); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func convertTmxTileset(ts tmxTileset, firstGid uint32, dir string) (*tileset, error) {
+	if ts.Image.Source == "" {
+		return nil, fmt.Errorf("the tileset \"%v\" is a collection of images, which is not supported", ts.Name)
+	}
+
+	set := &tileset{
+		name:       ts.Name,
+		firstGid:   firstGid,
+		tileWidth:  ts.TileWidth,
+		tileHeight: ts.TileHeight,
+		spacing:    ts.Spacing,
+		margin:     ts.Margin,
+		columns:    ts.Columns,
+		tileCount:  ts.TileCount,
+		imgWidth:   float32(ts.Image.Width),
+		imgHeight:  float32(ts.Image.Height),
+		image:      path.Join(dir, ts.Image.Source),
+		animations: make(map[uint32][]tileFrame),
+		properties: make(map[uint32]Properties),
+	}
+
+	for _, tile := range ts.Tiles {
+		if len(tile.Properties) > 0 {
+			set.properties[tile.ID] = convertTmxProperties(tile.Properties)
+		}
+		if len(tile.Animation) > 0 {
+			frames := make([]tileFrame, len(tile.Animation))
+			for i, f := range tile.Animation {
+				frames[i] = tileFrame{f.TileID, float64(f.Duration) / 1000}
+			}
+			set.animations[tile.ID] = frames
+		}
+	}
+
+	return set, validateTileset(set)
+}
+
+func validateTileset(set *tileset) error {
+	if set.columns <= 0 || set.imgWidth <= 0 || set.imgHeight <= 0 {
+		return fmt.Errorf("the tileset \"%v\" is missing its columns or image size", set.name)
+	}
+	return nil
+}
+
+func (t *tilemapData) addTmxLayers(layers []tmxLayer, parent layerParent) error {
+	for _, l := range layers {
+		visible := parent.visible && (l.Visible == nil || *l.Visible != 0)
+		opacity := parent.opacity
+		if l.Opacity != nil {
+			opacity *= *l.Opacity
+		}
+		offsetx := parent.offsetx + l.OffsetX
+		offsety := parent.offsety + l.OffsetY
+
+		switch l.XMLName.Local {
+		case "layer":
+			data, err := decodeTmxData(l.Data, l.Width*l.Height)
+			if err != nil {
+				return fmt.Errorf("could not read the layer \"%v\": %v", l.Name, err)
+			}
+			t.layers = append(t.layers, &TileLayer{
+				Name:       l.Name,
+				Width:      l.Width,
+				Height:     l.Height,
+				Visible:    visible,
+				Opacity:    opacity,
+				OffsetX:    offsetx,
+				OffsetY:    offsety,
+				Properties: convertTmxProperties(l.Properties),
+				data:       data,
+			})
+		case "objectgroup":
+			objects := make([]MapObject, len(l.Objects))
+			for i, o := range l.Objects {
+				objects[i] = convertTmxObject(o, offsetx, offsety)
+			}
+			t.objectLayers = append(t.objectLayers, &ObjectLayer{
+				Name:       l.Name,
+				Visible:    visible,
+				Objects:    objects,
+				Properties: convertTmxProperties(l.Properties),
+			})
+		case "group":
+			if err := t.addTmxLayers(l.Layers, layerParent{visible, opacity, offsetx, offsety}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func decodeTmxData(d tmxData, count int) ([]uint32, error) {
+	switch d.Encoding {
+	case "":
+		data := make([]uint32, count)
+		for i, tile := range d.Tiles {
+			if i < count {
+				data[i] = tile.GID
+			}
+		}
+		return data, nil
+	case "csv":
+		return decodeCsv(d.Text, count)
+	case "base64":
+		return decodeBase64(d.Text, d.Compression, count)
+	}
+	return nil, fmt.Errorf("unknown encoding \"%v\"", d.Encoding)
+}
+
+func decodeCsv(text string, count int) ([]uint32, error) {
+	data := make([]uint32, 0, count)
+	for _, field := range strings.Split(text, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		gid, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, uint32(gid))
+	}
+
+	if len(data) != count {
+		return nil, fmt.Errorf("expected %v tiles, found %v", count, len(data))
+	}
+	return data, nil
+}
+
+func decodeBase64(text, compression string, count int) ([]uint32, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, err
+	}
+
+	switch compression {
+	case "":
+	case "zlib":
+		r, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		if raw, err = io.ReadAll(r); err != nil {
+			return nil, err
+		}
+	case "gzip":
+		r, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		if raw, err = io.ReadAll(r); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression \"%v\"", compression)
+	}
+
+	if len(raw) != count*4 {
+		return nil, fmt.Errorf("expected %v tiles, found %v", count, len(raw)/4)
+	}
+
+	data := make([]uint32, count)
+	for i := range data {
+		data[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+	return data, nil
+}
+
+func convertTmxObject(o tmxObject, offsetx, offsety float64) MapObject {
+	kind := o.Type
+	if kind == "" {
+		kind = o.Class
+	}
+
+	obj := MapObject{
+		ID:         o.ID,
+		Name:       o.Name,
+		Type:       kind,
+		X:          o.X + offsetx,
+		Y:          o.Y + offsety,
+		Width:      o.Width,
+		Height:     o.Height,
+		Rotation:   o.Rotation,
+		GID:        o.GID,
+		Visible:    o.Visible == nil || *o.Visible != 0,
+		Ellipse:    o.Ellipse != nil,
+		Point:      o.Point != nil,
+		Properties: convertTmxProperties(o.Properties),
+	}
+
+	if o.Polygon != nil {
+		obj.Polygon = parsePoints(o.Polygon.Points)
+	}
+	if o.Polyline != nil {
+		obj.Polyline = parsePoints(o.Polyline.Points)
+	}
+
+	return obj
+}
+
+//Parses points written as "x1,y1 x2,y2 ..."
+func parsePoints(text string) []MapPoint {
+	points := make([]MapPoint, 0)
+	for _, pair := range strings.Fields(text) {
+		xy := strings.Split(pair, ",")
+		if len(xy) != 2 {
+			continue
+		}
+		x, _ := strconv.ParseFloat(xy[0], 64)
+		y, _ := strconv.ParseFloat(xy[1], 64)
+		points = append(points, MapPoint{x, y})
+	}
+	return points
+}
+
+func convertTmxProperties(props []tmxProperty) Properties {
+	p := make(Properties)
+	for _, prop := range props {
+		value := prop.Value
+		//multiline strings are stored as the text of the element
+		if value == "" {
+			value = prop.Text
+		}
+
+		switch prop.Type {
+		case "bool":
+			p[prop.Name] = value == "true"
+		case "int", "object":
+			i, _ := strconv.Atoi(value)
+			p[prop.Name] = i
+		case "float":
+			f, _ := strconv.ParseFloat(value, 64)
+			p[prop.Name] = f
+		default:
+			p[prop.Name] = value
+		}
+	}
+	return p
+}
+
+func convertTmj(m tmjMap, base string) (*tilemapData, error) {
+	t, err := newTilemapData(m.Orientation, m.Infinite, m.Width, m.Height, m.TileWidth, m.TileHeight)
+	if err != nil {
+		return nil, err
+	}
+	t.properties = convertTmjProperties(m.Properties)
+
+	for _, ts := range m.Tilesets {
+		dir := base
+		firstGid := ts.FirstGid
+
+		if ts.Source != "" {
+			source := path.Join(base, ts.Source)
+			data, err := asset.ReadFile(source)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, &ts); err != nil {
+				return nil, err
+			}
+			dir = path.Dir(source)
+		}
+
+		set, err := convertTmjTileset(ts, firstGid, dir)
+		if err != nil {
+			return nil, err
+		}
+		t.tilesets = append(t.tilesets, set)
+	}
+
+	if err := t.addTmjLayers(m.Layers, layerParent{true, 1, 0, 0}); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func convertTmjTileset(ts tmjTileset, firstGid uint32, dir string) (*tileset, error) {
+	if ts.Image == "" {
+		return nil, fmt.Errorf("the tileset \"%v\" is a collection of images, which is not supported", ts.Name)
+	}
+
+	set := &tileset{
+		name:       ts.Name,
+		firstGid:   firstGid,
+		tileWidth:  ts.TileWidth,
+		tileHeight: ts.TileHeight,
+		spacing:    ts.Spacing,
+		margin:     ts.Margin,
+		columns:    ts.Columns,
+		tileCount:  ts.TileCount,
+		imgWidth:   float32(ts.ImageWidth),
+		imgHeight:  float32(ts.ImageHeight),
+		image:      path.Join(dir, ts.Image),
+		animations: make(map[uint32][]tileFrame),
+		properties: make(map[uint32]Properties),
+	}
+
+	for _, tile := range ts.Tiles {
+		if len(tile.Properties) > 0 {
+			set.properties[tile.ID] = convertTmjProperties(tile.Properties)
+		}
+		if len(tile.Animation) > 0 {
+			frames := make([]tileFrame, len(tile.Animation))
+			for i, f := range tile.Animation {
+				frames[i] = tileFrame{f.TileID, float64(f.Duration) / 1000}
+			}
+			set.animations[tile.ID] = frames
+		}
+	}
+
+	return set, validateTileset(set)
+}
+
+func (t *tilemapData) addTmjLayers(layers []tmjLayer, parent layerParent) error {
+	for _, l := range layers {
+		visible := parent.visible && (l.Visible == nil || *l.Visible)
+		opacity := parent.opacity
+		if l.Opacity != nil {
+			opacity *= *l.Opacity
+		}
+		offsetx := parent.offsetx + l.OffsetX
+		offsety := parent.offsety + l.OffsetY
+
+		switch l.Type {
+		case "tilelayer":
+			data, err := decodeTmjData(l, l.Width*l.Height)
+			if err != nil {
+				return fmt.Errorf("could not read the layer \"%v\": %v", l.Name, err)
+			}
+			t.layers = append(t.layers, &TileLayer{
+				Name:       l.Name,
+				Width:      l.Width,
+				Height:     l.Height,
+				Visible:    visible,
+				Opacity:    opacity,
+				OffsetX:    offsetx,
+				OffsetY:    offsety,
+				Properties: convertTmjProperties(l.Properties),
+				data:       data,
+			})
+		case "objectgroup":
+			objects := make([]MapObject, len(l.Objects))
+			for i, o := range l.Objects {
+				kind := o.Type
+				if kind == "" {
+					kind = o.Class
+				}
+				objects[i] = MapObject{
+					ID:         o.ID,
+					Name:       o.Name,
+					Type:       kind,
+					X:          o.X + offsetx,
+					Y:          o.Y + offsety,
+					Width:      o.Width,
+					Height:     o.Height,
+					Rotation:   o.Rotation,
+					GID:        o.GID,
+					Visible:    o.Visible == nil || *o.Visible,
+					Ellipse:    o.Ellipse,
+					Point:      o.Point,
+					Polygon:    o.Polygon,
+					Polyline:   o.Polyline,
+					Properties: convertTmjProperties(o.Properties),
+				}
+			}
+			t.objectLayers = append(t.objectLayers, &ObjectLayer{
+				Name:       l.Name,
+				Visible:    visible,
+				Objects:    objects,
+				Properties: convertTmjProperties(l.Properties),
+			})
+		case "group":
+			if err := t.addTmjLayers(l.Layers, layerParent{visible, opacity, offsetx, offsety}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func decodeTmjData(l tmjLayer, count int) ([]uint32, error) {
+	if l.Encoding == "base64" {
+		var text string
+		if err := json.Unmarshal(l.Data, &text); err != nil {
+			return nil, err
+		}
+		return decodeBase64(text, l.Compression, count)
+	}
+
+	data := make([]uint32, 0, count)
+	if err := json.Unmarshal(l.Data, &data); err != nil {
+		return nil, err
+	}
+	if len(data) != count {
+		return nil, fmt.Errorf("expected %v tiles, found %v", count, len(data))
+	}
+	return data, nil
+}
+
+func convertTmjProperties(props []tmjProperty) Properties {
+	p := make(Properties)
+	for _, prop := range props {
+		//json numbers are read as float64, so turn the whole ones back into int
+		switch prop.Type {
+		case "int", "object":
+			if f, succ := prop.Value.(float64); succ {
+				p[prop.Name] = int(f)
+				continue
+			}
+		}
+		p[prop.Name] = prop.Value
+	}
+	return p
+}
@@ -0,0 +1,587 @@
+package framework
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/koinuri/game-project/main/asset"
+)
+
+const (
+	//Bits Tiled stores in the tile ids to flip tiles
+	FlipHorizontal uint32 = 0x80000000
+	FlipVertical   uint32 = 0x40000000
+	FlipDiagonal   uint32 = 0x20000000
+	flipMask              = FlipHorizontal | FlipVertical | FlipDiagonal
+
+	//Number of tiles along each side of a chunk.  Every chunk is drawn with one mesh per tileset it uses.
+	chunkSize = 16
+)
+
+//Custom properties set in Tiled.  Values are bool, int, float64 or string depending on their type in Tiled.
+type Properties map[string]interface{}
+
+type tileFrame struct {
+	id       uint32
+	duration float64
+}
+
+type tileset struct {
+	name          string
+	firstGid      uint32
+	tileWidth     int
+	tileHeight    int
+	spacing       int
+	margin        int
+	columns       int
+	tileCount     int
+	imgWidth      float32
+	imgHeight     float32
+	image         string
+	texture       uint32
+	premultiplied bool
+	animations    map[uint32][]tileFrame
+	properties    map[uint32]Properties
+}
+
+//Returns the texture coordinates of the tile as (left, top, right, bottom)
+func (t *tileset) texRect(id uint32) mgl32.Vec4 {
+	col := int(id) % t.columns
+	row := int(id) / t.columns
+
+	px := float32(t.margin + col*(t.tileWidth+t.spacing))
+	py := float32(t.margin + row*(t.tileHeight+t.spacing))
+
+	return mgl32.Vec4{
+		px / t.imgWidth,
+		py / t.imgHeight,
+		(px + float32(t.tileWidth)) / t.imgWidth,
+		(py + float32(t.tileHeight)) / t.imgHeight,
+	}
+}
+
+//Returns the tile shown at the time given, following the tile's animation if it has one
+func (t *tileset) frameAt(id uint32, time float64) uint32 {
+	frames, succ := t.animations[id]
+	if !succ {
+		return id
+	}
+
+	total := 0.0
+	for _, f := range frames {
+		total += f.duration
+	}
+	if total <= 0 {
+		return frames[0].id
+	}
+
+	//find the frame the time falls into within the current loop
+	time -= float64(int(time/total)) * total
+	for _, f := range frames {
+		if time < f.duration {
+			return f.id
+		}
+		time -= f.duration
+	}
+	return frames[len(frames)-1].id
+}
+
+type chunkKey struct {
+	x int
+	y int
+}
+
+//Meshes of one chunk of a layer, rebuilt when one of its tiles changes
+type tileChunk struct {
+	meshes   []mesh
+	buffers  []uint32
+	dirty    bool
+	animated bool
+}
+
+//Grid of tiles drawn as one layer of a tilemap.
+type TileLayer struct {
+	Name       string
+	Width      int
+	Height     int
+	Visible    bool
+	Opacity    float64
+	OffsetX    float64
+	OffsetY    float64
+	Properties Properties
+	data       []uint32
+	chunks     map[chunkKey]*tileChunk
+}
+
+//Object placed in an object layer in Tiled.  Positions and sizes are in Tiled's pixels, with y going down from the top of the
+//map.  Use Tilemap.ToWorld to find where they are in the window.
+type MapObject struct {
+	ID         int
+	Name       string
+	Type       string
+	X          float64
+	Y          float64
+	Width      float64
+	Height     float64
+	Rotation   float64
+	GID        uint32
+	Visible    bool
+	Ellipse    bool
+	Point      bool
+	Polygon    []MapPoint
+	Polyline   []MapPoint
+	Properties Properties
+}
+
+//Point of a polygon or polyline, relative to the position of its object
+type MapPoint struct {
+	X float64
+	Y float64
+}
+
+type ObjectLayer struct {
+	Name       string
+	Visible    bool
+	Objects    []MapObject
+	Properties Properties
+}
+
+//Artist that draws the tile layers of a map made in Tiled.
+type Tilemap struct {
+	*tilemapData
+	x              float32
+	y              float32
+	scalex         float32
+	scaley         float32
+	angle          float32
+	r              float32
+	g              float32
+	b              float32
+	alpha          float32
+	blend          blendMode
+	material       *Material
+	transformation transformation
+}
+
+//Everything shared between a tilemap and the copies made of it when it is drawn
+type tilemapData struct {
+	width        int
+	height       int
+	tileWidth    int
+	tileHeight   int
+	tilesets     []*tileset
+	layers       []*TileLayer
+	objectLayers []*ObjectLayer
+	properties   Properties
+	time         float64
+	frames       map[*tileset]map[uint32]uint32
+}
+
+//Loads a map made in Tiled.
+//	*InitTilemap(directory)
+//Where:
+//	directory is the location of the .tmx or .tmj file, relative to the executable file.  Tilesets and their images are
+//	looked up relative to the map file.
+//The top left corner of the map is placed at the position of the tilemap, and every pixel of the map is one unit of the
+//window's 1600 by 900 coordinate system.
+func InitTilemap(dir string) *Tilemap {
+	data, err := loadTiledMap(dir)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the map %v.\n%v", asset.Describe(dir), err))
+	}
+
+	options := GetDefaultTextureOptions()
+	for _, ts := range data.tilesets {
+		ts.texture = LoadTexture(ts.image, options)
+		ts.premultiplied = options.Premultiply
+	}
+
+	t := &Tilemap{
+		tilemapData:    data,
+		scalex:         1,
+		scaley:         1,
+		r:              1,
+		g:              1,
+		b:              1,
+		alpha:          1,
+		blend:          BlendNormal,
+		transformation: InitTransformation(),
+	}
+	t.frames = t.currentFrames()
+
+	return t
+}
+
+//Returns the tileset holding the tile id, with the flip flags removed
+func (t *tilemapData) tilesetOf(gid uint32) (*tileset, uint32) {
+	gid &^= flipMask
+	for i := len(t.tilesets) - 1; i >= 0; i-- {
+		if gid >= t.tilesets[i].firstGid {
+			return t.tilesets[i], gid - t.tilesets[i].firstGid
+		}
+	}
+	return nil, 0
+}
+
+//Returns the frame every animated tile is showing
+func (t *tilemapData) currentFrames() map[*tileset]map[uint32]uint32 {
+	frames := make(map[*tileset]map[uint32]uint32)
+	for _, ts := range t.tilesets {
+		frames[ts] = make(map[uint32]uint32)
+		for id := range ts.animations {
+			frames[ts][id] = ts.frameAt(id, t.time)
+		}
+	}
+	return frames
+}
+
+//Advances the animated tiles by dt seconds.
+func (t *Tilemap) Update(dt float64) {
+	t.time += dt
+
+	frames := t.currentFrames()
+	changed := false
+	for ts, ids := range frames {
+		for id, frame := range ids {
+			if t.frames[ts][id] != frame {
+				changed = true
+			}
+		}
+	}
+	t.frames = frames
+
+	if !changed {
+		return
+	}
+
+	for _, l := range t.layers {
+		for _, c := range l.chunks {
+			if c.animated {
+				c.dirty = true
+			}
+		}
+	}
+}
+
+func (t *Tilemap) GetLayer(name string) *TileLayer {
+	for _, l := range t.layers {
+		if l.Name == name {
+			return l
+		}
+	}
+	panic(fmt.Sprintf("Invalid name.  Could not find any tile layer with the name \"%v\".", name))
+}
+
+func (t *Tilemap) GetLayers() []*TileLayer {
+	return t.layers
+}
+
+func (t *Tilemap) GetObjectLayer(name string) *ObjectLayer {
+	for _, l := range t.objectLayers {
+		if l.Name == name {
+			return l
+		}
+	}
+	panic(fmt.Sprintf("Invalid name.  Could not find any object layer with the name \"%v\".", name))
+}
+
+func (t *Tilemap) GetObjectLayers() []*ObjectLayer {
+	return t.objectLayers
+}
+
+func (t *Tilemap) GetProperties() Properties {
+	return t.properties
+}
+
+//Returns the custom properties of the tile id, or nil if it has none
+func (t *Tilemap) GetTileProperties(gid uint32) Properties {
+	ts, id := t.tilesetOf(gid)
+	if ts == nil {
+		return nil
+	}
+	return ts.properties[id]
+}
+
+//Returns the width and height of the map in tiles
+func (t *Tilemap) GetSize() (int, int) {
+	return t.width, t.height
+}
+
+//Returns the width and height of a tile in pixels
+func (t *Tilemap) GetTileSize() (int, int) {
+	return t.tileWidth, t.tileHeight
+}
+
+//Converts a position in Tiled's pixels into a position in the window, without the rotation and scale of the tilemap
+func (t *Tilemap) ToWorld(x, y float64) (float64, float64) {
+	return float64(t.x) + x*float64(t.scalex), float64(t.y) - y*float64(t.scaley)
+}
+
+//Returns the tile id at the column and row, including its flip flags.  0 means there is no tile.
+func (l *TileLayer) GetTile(col, row int) uint32 {
+	if col < 0 || row < 0 || col >= l.Width || row >= l.Height {
+		return 0
+	}
+	return l.data[row*l.Width+col]
+}
+
+//Replaces the tile at the column and row.  gid may include the flip flags, and 0 removes the tile.
+func (l *TileLayer) SetTile(col, row int, gid uint32) {
+	if col < 0 || row < 0 || col >= l.Width || row >= l.Height {
+		panic(fmt.Sprintf("Invalid argument.  (%v, %v) is outside of the layer", col, row))
+	}
+
+	l.data[row*l.Width+col] = gid
+	if c, succ := l.chunks[chunkKey{col / chunkSize, row / chunkSize}]; succ {
+		c.dirty = true
+	}
+}
+
+//Builds the meshes of the chunk, one for every tileset it uses
+func (t *tilemapData) buildChunk(l *TileLayer, key chunkKey, c *tileChunk) {
+	if len(c.buffers) > 0 {
+		gl.DeleteBuffers(int32(len(c.buffers)), &c.buffers[0])
+	}
+	for _, m := range c.meshes {
+		gl.DeleteVertexArrays(1, &m.vao)
+	}
+
+	vertices := make(map[*tileset][]float32)
+	order := make([]*tileset, 0)
+	c.animated = false
+
+	for row := key.y * chunkSize; row < (key.y+1)*chunkSize && row < l.Height; row++ {
+		for col := key.x * chunkSize; col < (key.x+1)*chunkSize && col < l.Width; col++ {
+			gid := l.data[row*l.Width+col]
+			ts, id := t.tilesetOf(gid)
+			if ts == nil || gid&^flipMask == 0 {
+				continue
+			}
+
+			if _, succ := ts.animations[id]; succ {
+				c.animated = true
+				id = t.frames[ts][id]
+			}
+
+			if _, succ := vertices[ts]; !succ {
+				order = append(order, ts)
+			}
+			vertices[ts] = append(vertices[ts], tileQuad(t, l, ts, id, gid, col, row)...)
+		}
+	}
+
+	c.meshes = make([]mesh, 0, len(order))
+	c.buffers = make([]uint32, 0, len(order)*2)
+	for _, ts := range order {
+		vec := vertices[ts]
+		quads := len(vec) / 20
+
+		ind := make([]uint32, 0, quads*6)
+		for q := uint32(0); q < uint32(quads); q++ {
+			ind = append(ind, q*4, q*4+1, q*4+2, q*4+1, q*4+2, q*4+3)
+		}
+
+		var vao uint32
+		gl.GenVertexArrays(1, &vao)
+		gl.BindVertexArray(vao)
+
+		var vbo uint32
+		gl.GenBuffers(1, &vbo)
+		gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+		gl.BufferData(gl.ARRAY_BUFFER, len(vec)*4, gl.Ptr(vec), gl.STATIC_DRAW)
+
+		var ebo uint32
+		gl.GenBuffers(1, &ebo)
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(ind)*4, gl.Ptr(ind), gl.STATIC_DRAW)
+
+		gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 5*4, gl.PtrOffset(0))
+		gl.EnableVertexAttribArray(0)
+
+		gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 5*4, gl.PtrOffset(3*4))
+		gl.EnableVertexAttribArray(1)
+
+		c.meshes = append(c.meshes, mesh{vao, ts.texture, int32(len(ind)), 1, ts.premultiplied})
+		c.buffers = append(c.buffers, vbo, ebo)
+	}
+
+	c.dirty = false
+}
+
+//Returns the four vertices of a tile in the same layout as a sprite's quad: top left, top right, bottom left, bottom right
+func tileQuad(t *tilemapData, l *TileLayer, ts *tileset, id, gid uint32, col, row int) []float32 {
+	//tiles larger than the grid stick out of the top right of their cell, like in Tiled
+	left := float32(col*t.tileWidth) + float32(l.OffsetX)
+	bottom := -float32((row+1)*t.tileHeight) - float32(l.OffsetY)
+	right := left + float32(ts.tileWidth)
+	top := bottom + float32(ts.tileHeight)
+
+	r := ts.texRect(id)
+	tl := [2]float32{r[0], r[1]}
+	tr := [2]float32{r[2], r[1]}
+	bl := [2]float32{r[0], r[3]}
+	br := [2]float32{r[2], r[3]}
+
+	//the diagonal flip comes first, then the horizontal and vertical flips
+	if gid&FlipDiagonal != 0 {
+		tr, bl = bl, tr
+	}
+	if gid&FlipHorizontal != 0 {
+		tl, tr = tr, tl
+		bl, br = br, bl
+	}
+	if gid&FlipVertical != 0 {
+		tl, bl = bl, tl
+		tr, br = br, tr
+	}
+
+	return []float32{
+		left, top, 0, tl[0], tl[1],
+		right, top, 0, tr[0], tr[1],
+		left, bottom, 0, bl[0], bl[1],
+		right, bottom, 0, br[0], br[1],
+	}
+}
+
+func (t *Tilemap) getMeshes() []mesh {
+	meshes := make([]mesh, 0)
+
+	for _, l := range t.layers {
+		if !l.Visible {
+			continue
+		}
+
+		if l.chunks == nil {
+			l.chunks = make(map[chunkKey]*tileChunk)
+			for y := 0; y*chunkSize < l.Height; y++ {
+				for x := 0; x*chunkSize < l.Width; x++ {
+					l.chunks[chunkKey{x, y}] = &tileChunk{dirty: true}
+				}
+			}
+		}
+
+		//draw the chunks row by row so overlapping tiles are drawn in the same order every frame
+		for y := 0; y*chunkSize < l.Height; y++ {
+			for x := 0; x*chunkSize < l.Width; x++ {
+				key := chunkKey{x, y}
+				c := l.chunks[key]
+				if c.dirty {
+					t.buildChunk(l, key, c)
+				}
+				for _, m := range c.meshes {
+					m.opacity = float32(l.Opacity)
+					meshes = append(meshes, m)
+				}
+			}
+		}
+	}
+
+	return meshes
+}
+
+//Returns the texture of the first tileset
+func (t *Tilemap) GetDrawInfo() (uint32, uint32) {
+	if len(t.tilesets) == 0 {
+		return 0, 0
+	}
+	return 0, t.tilesets[0].texture
+}
+
+func (t *Tilemap) GetTransformation() mgl32.Mat4 {
+	t.transformation.translation = mgl32.Translate3D(t.x, t.y, 0)
+	return t.transformation.translation.Mul4(t.transformation.rotation.Mul4(t.transformation.scale))
+}
+
+//Returns the position of the top left corner of the map
+func (t *Tilemap) GetPosition() (float64, float64) {
+	return float64(t.x), float64(t.y)
+}
+
+func (t *Tilemap) GetScale() (float64, float64) {
+	return float64(t.scalex), float64(t.scaley)
+}
+
+func (t *Tilemap) GetAngle() float64 {
+	return float64(t.angle)
+}
+
+//Moves the top left corner of the map
+func (t *Tilemap) Move(x, y float64) {
+	t.x = float32(x)
+	t.y = float32(y)
+}
+
+func (t *Tilemap) Scale(v ...float64) {
+	switch len(v) {
+	case 1:
+		t.scalex = float32(v[0])
+		t.scaley = float32(v[0])
+	case 2:
+		t.scalex = float32(v[0])
+		t.scaley = float32(v[1])
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected either 1 or 2 float64, found %v", len(v)))
+	}
+
+	t.transformation.scale = mgl32.Diag4(mgl32.Vec4{t.scalex, t.scaley, 1, 1})
+}
+
+func (t *Tilemap) RadianRotate(angle float64) {
+	t.angle = float32(angle)
+
+	t.transformation.rotation = mgl32.HomogRotate3DZ(t.angle)
+}
+
+func (t *Tilemap) GetTint() mgl32.Vec4 {
+	return mgl32.Vec4{t.r, t.g, t.b, t.alpha}
+}
+
+func (t *Tilemap) SetColor(r, g, b float64) {
+	t.r = float32(r)
+	t.g = float32(g)
+	t.b = float32(b)
+}
+
+func (t *Tilemap) GetColor() (float64, float64, float64) {
+	return float64(t.r), float64(t.g), float64(t.b)
+}
+
+func (t *Tilemap) SetAlpha(a float64) {
+	t.alpha = float32(a)
+}
+
+func (t *Tilemap) GetAlpha() float64 {
+	return float64(t.alpha)
+}
+
+func (t *Tilemap) SetBlendMode(b blendMode) {
+	t.blend = b
+}
+
+func (t *Tilemap) GetBlendMode() blendMode {
+	return t.blend
+}
+
+func (t *Tilemap) SetMaterial(m *Material) {
+	t.material = m
+}
+
+func (t *Tilemap) GetMaterial() *Material {
+	return t.material
+}
+
+func (t *Tilemap) applyTransformations(x, y, scalex, scaley, angle float32, tint mgl32.Vec4) Artist {
+	tm := *t
+
+	tm.r *= tint[0]
+	tm.g *= tint[1]
+	tm.b *= tint[2]
+	tm.alpha *= tint[3]
+
+	tm.Scale(float64(tm.scalex*scalex), float64(tm.scaley*scaley))
+	tm.RadianRotate(float64(tm.angle + angle))
+	tm.Move(float64(tm.x+x), float64(tm.y+y))
+
+	return &tm
+}
@@ -0,0 +1,382 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/koinuri/game-project/main/asset"
+	"gopkg.in/yaml.v2"
+)
+
+//Names of the interpolation modes that can be used in timeline files.  "step" holds the value until the next keyframe.
+var interpolations = map[string]Easing{
+	"step":         nil,
+	"linear":       Linear,
+	"quadIn":       QuadIn,
+	"quadOut":      QuadOut,
+	"quadInOut":    QuadInOut,
+	"cubicIn":      CubicIn,
+	"cubicOut":     CubicOut,
+	"cubicInOut":   CubicInOut,
+	"elasticIn":    ElasticIn,
+	"elasticOut":   ElasticOut,
+	"elasticInOut": ElasticInOut,
+	"bounceIn":     BounceIn,
+	"bounceOut":    BounceOut,
+	"bounceInOut":  BounceInOut,
+	"backIn":       BackIn,
+	"backOut":      BackOut,
+	"backInOut":    BackInOut,
+}
+
+var timelineProperties = map[string]tweenProperty{
+	"position": TweenPosition,
+	"scale":    TweenScale,
+	"rotation": TweenRotation,
+	"alpha":    TweenAlpha,
+	"color":    TweenColor,
+}
+
+//Layout of the timeline files, shared by json and yaml
+type timelineFile struct {
+	Duration float64             `json:"duration" yaml:"duration"`
+	Loop     bool                `json:"loop" yaml:"loop"`
+	Tracks   []timelineFileTrack `json:"tracks" yaml:"tracks"`
+	Events   []timelineFileEvent `json:"events" yaml:"events"`
+}
+
+type timelineFileTrack struct {
+	Artist    string                 `json:"artist" yaml:"artist"`
+	Property  string                 `json:"property" yaml:"property"`
+	Keyframes []timelineFileKeyframe `json:"keyframes" yaml:"keyframes"`
+}
+
+type timelineFileKeyframe struct {
+	Time          float64   `json:"time" yaml:"time"`
+	Value         []float64 `json:"value" yaml:"value"`
+	Interpolation string    `json:"interpolation" yaml:"interpolation"`
+	Event         string    `json:"event" yaml:"event"`
+}
+
+type timelineFileEvent struct {
+	Time float64 `json:"time" yaml:"time"`
+	Name string  `json:"name" yaml:"name"`
+}
+
+type keyframe struct {
+	time   float64
+	value  []float64
+	easing Easing
+}
+
+type track struct {
+	tween     *Tween
+	keyframes []keyframe
+}
+
+type timelineEvent struct {
+	time float64
+	name string
+}
+
+//Keyframed animation of the artists inside an object, loaded from a file.
+type Timeline struct {
+	tracks   []track
+	events   []timelineEvent
+	duration float64
+	loop     bool
+	time     float64
+	speed    float64
+	playing  bool
+	done     bool
+	//whether the events at the very beginning are still to fire, which is only so before the first update from the time 0
+	rewound    bool
+	onEvent    func(name string)
+	onComplete func()
+}
+
+//Loads a timeline from a json or yaml file and binds its tracks to the artists of the object.
+//	*InitTimeline(directory, object)
+//Where:
+//	directory is the location of the timeline file, relative to the executable file.  Files ending in .yaml or .yml are read
+//	as yaml, everything else as json.
+//	object is the object holding the artists.  Tracks refer to artists by the name given to CreateSprite, and an empty name
+//	refers to the object itself.
+//Rotation values in the file are in degrees.  Every keyframe's interpolation describes how the value moves towards the next
+//keyframe, and defaults to linear.
+func InitTimeline(dir string, obj *Object) *Timeline {
+	file, err := loadTimelineFile(dir)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the timeline %v.\n%v", asset.Describe(dir), err))
+	}
+
+	t := &Timeline{
+		speed:   1,
+		playing: true,
+		rewound: true,
+	}
+	t.load(file, obj)
+
+	//an edited timeline keeps playing from the same time
+	WatchAsset(dir, func() error {
+		file, err := loadTimelineFile(dir)
+		if err != nil {
+			return err
+		}
+		t.load(file, obj)
+		t.time = math.Min(t.time, t.duration)
+		t.apply()
+		return nil
+	})
+
+	return t
+}
+
+//Replaces the tracks, events, duration and looping of the timeline with the ones of the file.  Nothing is replaced if the
+//file is invalid.
+func (t *Timeline) load(file timelineFile, obj *Object) {
+	loaded := &Timeline{
+		tracks:   make([]track, 0, len(file.Tracks)),
+		events:   make([]timelineEvent, 0),
+		duration: file.Duration,
+	}
+
+	for _, ft := range file.Tracks {
+		loaded.tracks = append(loaded.tracks, loaded.createTrack(ft, obj))
+	}
+
+	for _, e := range file.Events {
+		loaded.events = append(loaded.events, timelineEvent{e.Time, e.Name})
+	}
+	sort.SliceStable(loaded.events, func(a, b int) bool {
+		return loaded.events[a].time < loaded.events[b].time
+	})
+
+	t.tracks = loaded.tracks
+	t.events = loaded.events
+	t.duration = loaded.duration
+	t.loop = file.Loop
+}
+
+func loadTimelineFile(dir string) (timelineFile, error) {
+	var file timelineFile
+
+	data, err := asset.ReadFile(dir)
+	if err != nil {
+		return file, err
+	}
+
+	ext := strings.ToLower(path.Ext(dir))
+	if ext == ".yaml" || ext == ".yml" {
+		err = yaml.Unmarshal(data, &file)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+
+	return file, err
+}
+
+func (t *Timeline) createTrack(ft timelineFileTrack, obj *Object) track {
+	property, succ := timelineProperties[ft.Property]
+	if !succ {
+		panic(fmt.Sprintf("Invalid timeline.  Unknown property \"%v\"", ft.Property))
+	}
+	if len(ft.Keyframes) == 0 {
+		panic(fmt.Sprintf("Invalid timeline.  The %v track of \"%v\" has no keyframes", ft.Property, ft.Artist))
+	}
+
+	var target interface{} = obj
+	if ft.Artist != "" {
+		target = obj.GetArtist(ft.Artist)
+	}
+
+	//the tween is never played, it only checks the target and sets the values
+	tw := InitTween(target, property, 0, make([]float64, len(ft.Keyframes[0].Value))...)
+
+	keys := make([]keyframe, len(ft.Keyframes))
+	for i, fk := range ft.Keyframes {
+		easing, succ := interpolations[fk.Interpolation]
+		if fk.Interpolation == "" {
+			easing = Linear
+		} else if !succ {
+			panic(fmt.Sprintf("Invalid timeline.  Unknown interpolation \"%v\"", fk.Interpolation))
+		}
+
+		value := tw.expand(fk.Value)
+		if property == TweenRotation {
+			value[0] = value[0] * (math.Pi / 180)
+		}
+
+		keys[i] = keyframe{fk.Time, value, easing}
+
+		if fk.Event != "" {
+			t.events = append(t.events, timelineEvent{fk.Time, fk.Event})
+		}
+		if fk.Time > t.duration {
+			t.duration = fk.Time
+		}
+	}
+
+	sort.SliceStable(keys, func(a, b int) bool {
+		return keys[a].time < keys[b].time
+	})
+
+	return track{tw, keys}
+}
+
+//Sets the function called with the name of every event the playback passes.
+func (t *Timeline) OnEvent(f func(name string)) {
+	t.onEvent = f
+}
+
+//Sets the function called when a timeline that does not loop reaches its end.
+func (t *Timeline) OnComplete(f func()) {
+	t.onComplete = f
+}
+
+func (t *Timeline) Play() {
+	t.playing = true
+}
+
+func (t *Timeline) Pause() {
+	t.playing = false
+}
+
+func (t *Timeline) IsPlaying() bool {
+	return t.playing && !t.done
+}
+
+//Sets how fast the timeline plays.  1 is the normal speed.
+func (t *Timeline) SetSpeed(speed float64) {
+	if speed < 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a speed of 0 or more, found %v", speed))
+	}
+	t.speed = speed
+}
+
+//Jumps to the time given in seconds without firing the events in between.
+func (t *Timeline) Seek(time float64) {
+	t.time = math.Max(0, math.Min(time, t.duration))
+	t.done = false
+	t.rewound = t.time == 0
+	t.apply()
+}
+
+func (t *Timeline) GetTime() float64 {
+	return t.time
+}
+
+func (t *Timeline) GetDuration() float64 {
+	return t.duration
+}
+
+func (t *Timeline) IsDone() bool {
+	return t.done
+}
+
+func (t *Timeline) Reset() {
+	t.Seek(0)
+}
+
+func (t *Timeline) Update(dt float64) float64 {
+	if t.done {
+		return dt
+	}
+	if !t.playing {
+		return 0
+	}
+
+	if dt*t.speed <= 0 {
+		return 0
+	}
+
+	//events placed at the very beginning fire when the timeline starts from there.  A lap ending exactly at the end has
+	//already fired them, so they are not fired again when the next update starts from 0.
+	prev := t.time
+	if t.rewound {
+		prev = -1
+		t.rewound = false
+	}
+	t.time += dt * t.speed
+
+	if t.time < t.duration {
+		t.fireEvents(prev, t.time)
+		t.apply()
+		return 0
+	}
+
+	if !t.loop || t.duration == 0 {
+		leftover := (t.time - t.duration) / t.speed
+		t.fireEvents(prev, t.duration)
+		t.time = t.duration
+		t.apply()
+
+		t.done = true
+		if t.onComplete != nil {
+			t.onComplete()
+		}
+		return leftover
+	}
+
+	//fire the events up to the end, then the events of every lap made during this update
+	t.fireEvents(prev, t.duration)
+	laps := math.Floor(t.time / t.duration)
+	t.time -= laps * t.duration
+	for i := 1.0; i < laps; i++ {
+		t.fireEvents(-1, t.duration)
+	}
+	t.fireEvents(-1, t.time)
+	t.apply()
+
+	return 0
+}
+
+//Fires the events within (from, to]
+func (t *Timeline) fireEvents(from, to float64) {
+	if t.onEvent == nil {
+		return
+	}
+	for _, e := range t.events {
+		if e.time > from && e.time <= to {
+			t.onEvent(e.name)
+		}
+	}
+}
+
+func (t *Timeline) apply() {
+	for _, tr := range t.tracks {
+		tr.tween.set(tr.valueAt(t.time))
+	}
+}
+
+func (tr *track) valueAt(time float64) []float64 {
+	keys := tr.keyframes
+	if time <= keys[0].time {
+		return keys[0].value
+	}
+
+	for i := 0; i < len(keys)-1; i++ {
+		from := keys[i]
+		to := keys[i+1]
+		if time >= to.time {
+			continue
+		}
+
+		if from.easing == nil || to.time == from.time {
+			return from.value
+		}
+
+		e := from.easing((time - from.time) / (to.time - from.time))
+		v := make([]float64, len(from.value))
+		for j := range v {
+			v[j] = from.value[j] + (to.value[j]-from.value[j])*e
+		}
+		return v
+	}
+
+	return keys[len(keys)-1].value
+}
@@ -0,0 +1,493 @@
+package framework
+
+import "fmt"
+
+type tweenProperty uint32
+
+const (
+	TweenPosition tweenProperty = iota
+	TweenScale
+	TweenRotation
+	TweenAlpha
+	TweenColor
+	TweenVolume
+)
+
+//Anything that can be moved, scaled and rotated, such as Sprite and Object.
+type Transformable interface {
+	Move(x, y float64)
+	Scale(v ...float64)
+	RadianRotate(angle float64)
+	GetPosition() (float64, float64)
+	GetScale() (float64, float64)
+	GetAngle() float64
+}
+
+//Anything that can be colored and faded.
+type Tintable interface {
+	SetColor(r, g, b float64)
+	GetColor() (float64, float64, float64)
+	SetAlpha(a float64)
+	GetAlpha() float64
+}
+
+//Artists every tween but the volume can animate, checked when building so none of them loses a setter the tweens need
+var (
+	_ Transformable = (*Object)(nil)
+	_ Tintable      = (*Object)(nil)
+	_ Transformable = (*Sprite)(nil)
+	_ Tintable      = (*Sprite)(nil)
+	_ Transformable = (*AnimatedSprite)(nil)
+	_ Tintable      = (*AnimatedSprite)(nil)
+	_ Transformable = (*Text)(nil)
+	_ Tintable      = (*Text)(nil)
+	_ Transformable = (*NineSlice)(nil)
+	_ Tintable      = (*NineSlice)(nil)
+	_ Transformable = (*Tilemap)(nil)
+	_ Tintable      = (*Tilemap)(nil)
+)
+
+//Anything that can be made louder and quieter, such as the voices of the audio mixer.
+type Audible interface {
+	SetVolume(volume float64)
+	GetVolume() float64
+}
+
+//Interface that every tween, sequence, group and delay follows.
+//Update advances the animation by dt seconds and returns the part of dt that was left over once the animation finished, so
+//animations played one after another do not lose time between them.
+type Animation interface {
+	Update(dt float64) float64
+	IsDone() bool
+	Reset()
+}
+
+//Repeat count and completion state shared by every animation.
+type playback struct {
+	repeat     int
+	played     int
+	done       bool
+	onComplete func()
+}
+
+//Sets how many more times the animation plays after the first time.  -1 repeats it forever.
+func (p *playback) SetRepeat(n int) {
+	if n < -1 {
+		panic(fmt.Sprintf("Invalid argument.  Expected -1 or more, found %v", n))
+	}
+	p.repeat = n
+}
+
+//Sets the function called once the animation has finished all of its repeats.
+func (p *playback) OnComplete(f func()) {
+	p.onComplete = f
+}
+
+func (p *playback) IsDone() bool {
+	return p.done
+}
+
+//Returns true if there is another repeat left, counting it as played
+func (p *playback) nextRepeat() bool {
+	if p.repeat >= 0 && p.played >= p.repeat {
+		return false
+	}
+	p.played++
+	return true
+}
+
+func (p *playback) finish() {
+	p.done = true
+	if p.onComplete != nil {
+		p.onComplete()
+	}
+}
+
+type Tween struct {
+	playback
+	target   interface{}
+	property tweenProperty
+	from     []float64
+	to       []float64
+	duration float64
+	elapsed  float64
+	delay    float64
+	waited   float64
+	easing   Easing
+	yoyo     bool
+	reversed bool
+	started  bool
+}
+
+//Creates a tween that animates the property of the target to the values given over duration seconds.
+//	*InitTween(target, TweenPosition, duration, x, y)
+//	*InitTween(target, TweenScale, duration, scale)
+//	*InitTween(target, TweenScale, duration, scalex, scaley)
+//	*InitTween(target, TweenRotation, duration, radian)
+//	*InitTween(target, TweenAlpha, duration, alpha)
+//	*InitTween(target, TweenColor, duration, r, g, b)
+//	*InitTween(target, TweenVolume, duration, volume)
+//Where:
+//	target is a pointer to the Object, Sprite, Text, NineSlice or Tilemap to animate.  It must be Transformable for
+//	position, scale and rotation, Tintable for alpha and color, and Audible for volume.
+//The values the tween starts from are read from the target when the tween starts playing, unless they are set with SetFrom.
+func InitTween(target interface{}, property tweenProperty, duration float64, to ...float64) *Tween {
+	if duration < 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a duration of 0 or more, found %v", duration))
+	}
+
+	switch property {
+	case TweenPosition, TweenScale, TweenRotation:
+		if _, succ := target.(Transformable); !succ {
+			panic(fmt.Sprintf("Invalid argument.  Expected Transformable, got %T", target))
+		}
+	case TweenAlpha, TweenColor:
+		if _, succ := target.(Tintable); !succ {
+			panic(fmt.Sprintf("Invalid argument.  Expected Tintable, got %T", target))
+		}
+	case TweenVolume:
+		if _, succ := target.(Audible); !succ {
+			panic(fmt.Sprintf("Invalid argument.  Expected Audible, got %T", target))
+		}
+	default:
+		panic(fmt.Sprintf("Invalid argument.  Unknown tween property %v", property))
+	}
+
+	t := &Tween{
+		target:   target,
+		property: property,
+		duration: duration,
+		easing:   Linear,
+	}
+	t.to = t.expand(to)
+
+	return t
+}
+
+//Checks that the number of values matches the property, then expands a single scale value into both axes
+func (t *Tween) expand(v []float64) []float64 {
+	count := 1
+	switch t.property {
+	case TweenPosition:
+		count = 2
+	case TweenScale:
+		if len(v) == 1 {
+			v = []float64{v[0], v[0]}
+		}
+		count = 2
+	case TweenColor:
+		count = 3
+	}
+
+	if len(v) != count {
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected %v values for the property, found %v", count, len(v)))
+	}
+
+	values := make([]float64, count)
+	copy(values, v)
+	return values
+}
+
+//Sets the values to start from instead of reading them from the target when the tween starts.
+func (t *Tween) SetFrom(from ...float64) {
+	t.from = t.expand(from)
+	t.started = true
+}
+
+func (t *Tween) SetEasing(e Easing) {
+	if e == nil {
+		panic("Invalid argument.  The easing function cannot be nil")
+	}
+	t.easing = e
+}
+
+//Sets the seconds to wait before the tween starts.
+func (t *Tween) SetDelay(seconds float64) {
+	t.delay = seconds
+}
+
+//Sets whether every repeat plays backwards from the one before it.
+func (t *Tween) SetYoyo(yoyo bool) {
+	t.yoyo = yoyo
+}
+
+func (t *Tween) Update(dt float64) float64 {
+	if t.done {
+		return dt
+	}
+
+	//wait for the delay before touching the target
+	if t.waited < t.delay {
+		t.waited += dt
+		if t.waited < t.delay {
+			return 0
+		}
+		dt = t.waited - t.delay
+	}
+
+	if !t.started {
+		t.from = t.get()
+		t.started = true
+	}
+
+	t.elapsed += dt
+	for t.elapsed >= t.duration {
+		leftover := t.elapsed - t.duration
+		t.apply(1)
+
+		if t.duration == 0 || !t.nextRepeat() {
+			t.finish()
+			return leftover
+		}
+
+		if t.yoyo {
+			t.reversed = !t.reversed
+		}
+		t.elapsed = leftover
+	}
+
+	t.apply(t.elapsed / t.duration)
+	return 0
+}
+
+//Rewinds the tween to the beginning.  The values it started from are kept, so the tween plays the same way again.
+func (t *Tween) Reset() {
+	t.elapsed = 0
+	t.waited = 0
+	t.played = 0
+	t.reversed = false
+	t.done = false
+}
+
+//Sets the target to the value at progress p, where p is within [0, 1]
+func (t *Tween) apply(p float64) {
+	if t.reversed {
+		p = 1 - p
+	}
+	e := t.easing(p)
+
+	v := make([]float64, len(t.to))
+	for i := range v {
+		v[i] = t.from[i] + (t.to[i]-t.from[i])*e
+	}
+	t.set(v)
+}
+
+func (t *Tween) get() []float64 {
+	switch t.property {
+	case TweenPosition:
+		x, y := t.target.(Transformable).GetPosition()
+		return []float64{x, y}
+	case TweenScale:
+		x, y := t.target.(Transformable).GetScale()
+		return []float64{x, y}
+	case TweenRotation:
+		return []float64{t.target.(Transformable).GetAngle()}
+	case TweenAlpha:
+		return []float64{t.target.(Tintable).GetAlpha()}
+	case TweenVolume:
+		return []float64{t.target.(Audible).GetVolume()}
+	default:
+		r, g, b := t.target.(Tintable).GetColor()
+		return []float64{r, g, b}
+	}
+}
+
+func (t *Tween) set(v []float64) {
+	switch t.property {
+	case TweenPosition:
+		t.target.(Transformable).Move(v[0], v[1])
+	case TweenScale:
+		t.target.(Transformable).Scale(v[0], v[1])
+	case TweenRotation:
+		t.target.(Transformable).RadianRotate(v[0])
+	case TweenAlpha:
+		t.target.(Tintable).SetAlpha(v[0])
+	case TweenVolume:
+		t.target.(Audible).SetVolume(v[0])
+	default:
+		t.target.(Tintable).SetColor(v[0], v[1], v[2])
+	}
+}
+
+//Plays animations one after another.
+type Sequence struct {
+	playback
+	animations []Animation
+	current    int
+}
+
+func InitSequence(animations ...Animation) *Sequence {
+	return &Sequence{animations: animations}
+}
+
+func (s *Sequence) Update(dt float64) float64 {
+	if s.done {
+		return dt
+	}
+
+	//time left at the last restart, to stop looping forever when nothing in the sequence takes any time
+	restartedWith := -1.0
+	for {
+		if s.current >= len(s.animations) {
+			if dt == restartedWith || !s.nextRepeat() {
+				s.finish()
+				return dt
+			}
+			restartedWith = dt
+
+			s.current = 0
+			for _, a := range s.animations {
+				a.Reset()
+			}
+			continue
+		}
+
+		dt = s.animations[s.current].Update(dt)
+		if !s.animations[s.current].IsDone() {
+			return 0
+		}
+		s.current++
+	}
+}
+
+func (s *Sequence) Reset() {
+	s.current = 0
+	s.played = 0
+	s.done = false
+	for _, a := range s.animations {
+		a.Reset()
+	}
+}
+
+//Plays animations at the same time.  The group finishes once the longest animation in it finishes.
+type Group struct {
+	playback
+	animations []Animation
+}
+
+func InitGroup(animations ...Animation) *Group {
+	return &Group{animations: animations}
+}
+
+func (g *Group) Update(dt float64) float64 {
+	if g.done {
+		return dt
+	}
+
+	for {
+		leftover := dt
+		finished := true
+		for _, a := range g.animations {
+			l := a.Update(dt)
+			if !a.IsDone() {
+				finished = false
+			} else if l < leftover {
+				leftover = l
+			}
+		}
+
+		if !finished {
+			return 0
+		}
+
+		if leftover == dt || !g.nextRepeat() {
+			g.finish()
+			return leftover
+		}
+
+		for _, a := range g.animations {
+			a.Reset()
+		}
+		dt = leftover
+	}
+}
+
+func (g *Group) Reset() {
+	g.played = 0
+	g.done = false
+	for _, a := range g.animations {
+		a.Reset()
+	}
+}
+
+//Waits for the seconds given.  Used inside sequences to leave a gap between animations, or on its own to call a function
+//after some time.
+type Delay struct {
+	playback
+	duration float64
+	elapsed  float64
+}
+
+func InitDelay(seconds float64) *Delay {
+	if seconds < 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a duration of 0 or more, found %v", seconds))
+	}
+	return &Delay{duration: seconds}
+}
+
+func (d *Delay) Update(dt float64) float64 {
+	if d.done {
+		return dt
+	}
+
+	d.elapsed += dt
+	for d.elapsed >= d.duration {
+		leftover := d.elapsed - d.duration
+		if d.duration == 0 || !d.nextRepeat() {
+			d.finish()
+			return leftover
+		}
+		d.elapsed = leftover
+	}
+	return 0
+}
+
+func (d *Delay) Reset() {
+	d.elapsed = 0
+	d.played = 0
+	d.done = false
+}
+
+//Plays animations every frame and drops them once they are done.
+type Animator struct {
+	animations []Animation
+}
+
+func InitAnimator() Animator {
+	return Animator{make([]Animation, 0)}
+}
+
+//Starts playing the animation on the next Update.
+func (a *Animator) Play(anim Animation) {
+	a.animations = append(a.animations, anim)
+}
+
+//Stops the animation where it currently is.
+func (a *Animator) Stop(anim Animation) {
+	for i, n := range a.animations {
+		if n == anim {
+			a.animations = append(a.animations[:i], a.animations[i+1:]...)
+			return
+		}
+	}
+}
+
+//Returns true while there is any animation that has not finished.
+func (a *Animator) IsPlaying() bool {
+	return len(a.animations) > 0
+}
+
+//Advances every animation by dt seconds.
+func (a *Animator) Update(dt float64) {
+	//callbacks may start new animations, so only the animations playing before this update are advanced
+	playing := a.animations
+	a.animations = make([]Animation, 0, len(playing))
+
+	for _, anim := range playing {
+		anim.Update(dt)
+		if !anim.IsDone() {
+			a.animations = append(a.animations, anim)
+		}
+	}
+}
@@ -1,5 +1,28 @@
 package global
 
+import (
+	"os"
+	"path/filepath"
+)
+
 var Directory string
+
+//Directory the game writes to, such as for saves and settings, since the directory of the executable may not be writable
+var UserDirectory string
+
 var Width uint32
 var Height uint32
+
+//Writes the data to a temporary file next to the file, then moves it over the file so a crash cannot leave half of it.
+//The directory of the file is created if it does not exist.
+func WriteFile(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	temp := file + ".tmp"
+	if err := os.WriteFile(temp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(temp, file)
+}
@@ -0,0 +1,318 @@
+//Package input keeps track of the keyboard, the mouse and a gamepad.  The events of the window are gathered as they arrive
+//and handed out once per frame by Update, so every part of the game sees the same presses during a frame.
+package input
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+//Buttons of a gamepad as numbered by XInput, which most gamepads follow
+const (
+	GamepadA = iota
+	GamepadB
+	GamepadX
+	GamepadY
+	GamepadLeftBumper
+	GamepadRightBumper
+	GamepadBack
+	GamepadStart
+	GamepadGuide
+	GamepadLeftThumb
+	GamepadRightThumb
+	GamepadUp
+	GamepadRight
+	GamepadDown
+	GamepadLeft
+)
+
+//Axes of a gamepad as numbered by XInput
+const (
+	GamepadLeftX = iota
+	GamepadLeftY
+	GamepadRightX
+	GamepadRightY
+	GamepadLeftTrigger
+	GamepadRightTrigger
+)
+
+//Events gathered since the last Update
+type events struct {
+	pressed       []glfw.Key
+	released      []glfw.Key
+	chars         []rune
+	mousePressed  []glfw.MouseButton
+	mouseReleased []glfw.MouseButton
+	scrollX       float64
+	scrollY       float64
+}
+
+type Input struct {
+	window   *glfw.Window
+	pending  events
+	current  events
+	keys     map[glfw.Key]bool
+	mods     glfw.ModifierKey
+	buttons  map[glfw.MouseButton]bool
+	mouseX   float64
+	mouseY   float64
+	joystick glfw.Joystick
+	axes     []float32
+	pad      []byte
+	previous []byte
+	deadZone float64
+	//text being composed with an input method and the position of its caret, in characters
+	composition string
+	compCaret   int
+	composer    Composer
+}
+
+//Platform layer reading the text being composed with an input method, such as kana not yet converted to kanji, which glfw
+//3.2 does not report.  Without one the system shows the composition in a window of its own and only the committed text
+//arrives through GetTyped.
+type Composer interface {
+	//Returns the text being composed and the position of its caret in characters, or an empty text when nothing is being
+	//composed.  It is called once per frame by Update.
+	GetComposition() (string, int)
+	//Places the window of the input method listing the candidates below (x, y), in the pixels of the window
+	SetCandidatePosition(x, y float64)
+}
+
+//Starts listening to the window.  Call Update once per frame, after the events are polled.
+func InitInput(window *glfw.Window) *Input {
+	in := &Input{
+		window:   window,
+		keys:     make(map[glfw.Key]bool),
+		buttons:  make(map[glfw.MouseButton]bool),
+		joystick: glfw.Joystick1,
+		deadZone: .25,
+	}
+
+	window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		in.mods = mods
+		switch action {
+		case glfw.Press:
+			in.keys[key] = true
+			in.pending.pressed = append(in.pending.pressed, key)
+		case glfw.Repeat:
+			in.pending.pressed = append(in.pending.pressed, key)
+		case glfw.Release:
+			delete(in.keys, key)
+			in.pending.released = append(in.pending.released, key)
+		}
+	})
+
+	window.SetCharCallback(func(w *glfw.Window, char rune) {
+		in.pending.chars = append(in.pending.chars, char)
+	})
+
+	window.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		in.mods = mods
+		if action == glfw.Press {
+			in.buttons[button] = true
+			in.pending.mousePressed = append(in.pending.mousePressed, button)
+		} else {
+			delete(in.buttons, button)
+			in.pending.mouseReleased = append(in.pending.mouseReleased, button)
+		}
+	})
+
+	window.SetCursorPosCallback(func(w *glfw.Window, x, y float64) {
+		in.mouseX, in.mouseY = in.toWorld(x, y)
+	})
+
+	window.SetScrollCallback(func(w *glfw.Window, x, y float64) {
+		in.pending.scrollX += x
+		in.pending.scrollY += y
+	})
+
+	return in
+}
+
+//Turns a position in the pixels of the window into the 1600 by 900 coordinate system of the painter, where y goes up
+func (in *Input) toWorld(x, y float64) (float64, float64) {
+	width, height := in.window.GetSize()
+	if width == 0 || height == 0 {
+		return 0, 0
+	}
+	return x/float64(width)*1600 - 800, 450 - y/float64(height)*900
+}
+
+//Turns a position in the 1600 by 900 coordinate system of the painter into the pixels of the window
+func (in *Input) toWindow(x, y float64) (float64, float64) {
+	width, height := in.window.GetSize()
+	return (x + 800) / 1600 * float64(width), (450 - y) / 900 * float64(height)
+}
+
+//Hands out the events gathered since the last call and reads the gamepad.  Call it once per frame.
+func (in *Input) Update() {
+	in.current = in.pending
+	in.pending = events{}
+
+	in.previous = append(in.previous[:0], in.pad...)
+	if glfw.JoystickPresent(in.joystick) {
+		in.axes = glfw.GetJoystickAxes(in.joystick)
+		in.pad = append(in.pad[:0], glfw.GetJoystickButtons(in.joystick)...)
+	} else {
+		in.axes = nil
+		in.pad = in.pad[:0]
+	}
+
+	if in.composer != nil {
+		in.composition, in.compCaret = in.composer.GetComposition()
+	}
+}
+
+func (in *Input) IsKeyDown(key glfw.Key) bool {
+	return in.keys[key]
+}
+
+//Returns true if the key was pressed this frame, including the repeats sent while it is held down
+func (in *Input) WasKeyPressed(key glfw.Key) bool {
+	for _, k := range in.current.pressed {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (in *Input) WasKeyReleased(key glfw.Key) bool {
+	for _, k := range in.current.released {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+//Returns the keys pressed this frame in the order they were pressed, including repeats
+func (in *Input) GetPressedKeys() []glfw.Key {
+	return in.current.pressed
+}
+
+//Returns the modifier keys held during the last key or mouse button event
+func (in *Input) GetModifiers() glfw.ModifierKey {
+	return in.mods
+}
+
+//Returns the characters typed this frame, as the keyboard layout of the player produces them
+func (in *Input) GetTyped() []rune {
+	return in.current.chars
+}
+
+//Sets the platform layer the composition is read from every frame, or nil to stop reading it
+func (in *Input) SetComposer(c Composer) {
+	in.composer = c
+	if c == nil {
+		in.SetComposition("", 0)
+	}
+}
+
+func (in *Input) GetComposer() Composer {
+	return in.composer
+}
+
+//Sets the text being composed with an input method and the position of its caret in characters, for platform layers that
+//are told of the composition as it changes rather than asked for it like a Composer.  An empty text ends the composition.
+func (in *Input) SetComposition(text string, caret int) {
+	in.composition = text
+	in.compCaret = caret
+}
+
+//Returns the text being composed with an input method and the position of its caret, or an empty text when nothing is
+//being composed
+func (in *Input) GetComposition() (string, int) {
+	return in.composition, in.compCaret
+}
+
+//Places the window of the input method listing the candidates below (x, y), in the 1600 by 900 coordinate system of the
+//painter, such as under the caret of a text field.  It does nothing without a Composer.
+func (in *Input) SetCandidatePosition(x, y float64) {
+	if in.composer != nil {
+		in.composer.SetCandidatePosition(in.toWindow(x, y))
+	}
+}
+
+//Returns the text held by the clipboard of the system, or an empty text if it does not hold any
+func (in *Input) GetClipboard() string {
+	text, err := in.window.GetClipboardString()
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+func (in *Input) SetClipboard(text string) {
+	in.window.SetClipboardString(text)
+}
+
+//Returns the position of the cursor in the 1600 by 900 coordinate system of the painter
+func (in *Input) GetMousePosition() (float64, float64) {
+	return in.mouseX, in.mouseY
+}
+
+func (in *Input) IsMouseDown(button glfw.MouseButton) bool {
+	return in.buttons[button]
+}
+
+func (in *Input) WasMousePressed(button glfw.MouseButton) bool {
+	for _, b := range in.current.mousePressed {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
+
+func (in *Input) WasMouseReleased(button glfw.MouseButton) bool {
+	for _, b := range in.current.mouseReleased {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
+
+//Returns how far the wheel was scrolled this frame, where positive y scrolls up
+func (in *Input) GetScroll() (float64, float64) {
+	return in.current.scrollX, in.current.scrollY
+}
+
+//Sets the joystick read as the gamepad.  It is defaulted to glfw.Joystick1.
+func (in *Input) SetGamepad(joystick glfw.Joystick) {
+	in.joystick = joystick
+}
+
+func (in *Input) IsGamepadConnected() bool {
+	return glfw.JoystickPresent(in.joystick)
+}
+
+//Sets how far a stick has to be pushed before it counts, within [0, 1].  It is defaulted to 0.25.
+func (in *Input) SetDeadZone(deadZone float64) {
+	in.deadZone = deadZone
+}
+
+//Returns the axis of the gamepad within [-1, 1], or 0 if the gamepad does not have it or the stick is within the dead zone
+func (in *Input) GetGamepadAxis(axis int) float64 {
+	if axis < 0 || axis >= len(in.axes) {
+		return 0
+	}
+
+	v := float64(in.axes[axis])
+	if v > -in.deadZone && v < in.deadZone {
+		return 0
+	}
+	return v
+}
+
+func (in *Input) IsGamepadButtonDown(button int) bool {
+	return button >= 0 && button < len(in.pad) && in.pad[button] == byte(glfw.Press)
+}
+
+//Returns true if the button of the gamepad went down since the last frame
+func (in *Input) WasGamepadButtonPressed(button int) bool {
+	if !in.IsGamepadButtonDown(button) {
+		return false
+	}
+	return button >= len(in.previous) || in.previous[button] != byte(glfw.Press)
+}
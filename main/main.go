@@ -4,7 +4,7 @@ import (
 	//	"fmt"
 	"math"
 	"os"
-	"path"
+	"path/filepath"
 	"runtime"
 
 	"github.com/koinuri/game-project/main/framework"
@@ -24,7 +24,15 @@ func Init() {
 		panic(err)
 	}
 
-	global.Directory = path.Dir(ex)
+	global.Directory = filepath.Dir(ex)
+
+	//without a config directory, such as when $HOME is not set, the game writes next to the executable instead
+	if config, err := os.UserConfigDir(); err == nil {
+		global.UserDirectory = filepath.Join(config, "game-project")
+	} else {
+		global.UserDirectory = filepath.Join(global.Directory, "user")
+	}
+
 	global.Width = width
 	global.Height = height
 }
@@ -47,22 +55,34 @@ func main() {
 	spr2.AngleRotate(210)
 	spr3.AngleRotate(330)
 
-	angle := float64(0.0)
+	//Animations played on the object
+	animator := framework.InitAnimator()
+
+	spin := framework.InitTween(&obj, framework.TweenRotation, 2, 2*math.Pi)
+	spin.SetRepeat(-1)
+	animator.Play(spin)
+
+	pulse := framework.InitTween(&obj, framework.TweenScale, 1, .35)
+	pulse.SetFrom(.25)
+	pulse.SetEasing(framework.QuadInOut)
+	pulse.SetYoyo(true)
+	pulse.SetRepeat(-1)
+	animator.Play(pulse)
+
+	last := framework.GetTime()
 	//Main loop to draw the drawing logic created
 	for !window.ShouldClose() {
 		framework.InitFrame()
 
-		rad := angle * (math.Pi / 180)
-
-		obj.AngleRotate(angle * 3)
+		now := framework.GetTime()
+		animator.Update(now - last)
+		last = now
 
+		rad := now * (math.Pi / 3)
 		obj.Move(450*math.Cos(rad)*.5, 450*math.Sin(rad)*.5)
 
-		obj.Scale(.3)
-
 		framework.Draw(obj.GetArtists(), program)
 
 		framework.SwapWindowAndPollEvents(window)
-		angle += 1
 	}
 }
@@ -0,0 +1,97 @@
+package pathfinding
+
+import "container/heap"
+
+type node struct {
+	cell     Cell
+	priority float64
+	index    int
+}
+
+//Min-heap of cells ordered by priority, used by both A* and the flow fields
+type queue []*node
+
+func (q queue) Len() int { return len(q) }
+
+func (q queue) Less(i, j int) bool { return q[i].priority < q[j].priority }
+
+func (q queue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *queue) Push(x interface{}) {
+	n := x.(*node)
+	n.index = len(*q)
+	*q = append(*q, n)
+}
+
+func (q *queue) Pop() interface{} {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+//Returns the cheapest path from start to goal with A*, including both of them, and its cost.  Returns nil if the goal
+//cannot be reached.
+func (g *Grid) FindPath(start, goal Cell) ([]Cell, float64) {
+	if !g.IsWalkable(start) || !g.IsWalkable(goal) {
+		return nil, 0
+	}
+	if start == goal {
+		return []Cell{start}, 0
+	}
+
+	min := g.minCost()
+	costs := map[Cell]float64{start: 0}
+	from := make(map[Cell]Cell)
+	closed := make(map[Cell]bool)
+
+	open := &queue{}
+	heap.Push(open, &node{cell: start, priority: g.estimate(start, goal, min)})
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node).cell
+		if current == goal {
+			return trace(from, start, goal), costs[goal]
+		}
+		if closed[current] {
+			continue
+		}
+		closed[current] = true
+
+		for _, s := range g.neighbors(current) {
+			if closed[s.cell] {
+				continue
+			}
+
+			cost := costs[current] + s.cost
+			if known, succ := costs[s.cell]; succ && known <= cost {
+				continue
+			}
+
+			//pushing again instead of updating the old entry is simpler, and the stale entry is skipped once closed
+			costs[s.cell] = cost
+			from[s.cell] = current
+			heap.Push(open, &node{cell: s.cell, priority: cost + g.estimate(s.cell, goal, min)})
+		}
+	}
+
+	return nil, 0
+}
+
+//Walks back from the goal to the start
+func trace(from map[Cell]Cell, start, goal Cell) []Cell {
+	path := []Cell{goal}
+	for c := goal; c != start; {
+		c = from[c]
+		path = append(path, c)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
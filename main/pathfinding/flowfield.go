@@ -0,0 +1,119 @@
+package pathfinding
+
+import (
+	"container/heap"
+	"math"
+)
+
+//Cost of reaching the nearest goal from every cell of a grid, found with Dijkstra's algorithm.  Handy when many units head
+//to the same place, since every one of them can read its next step from the same field.
+type FlowField struct {
+	grid     *Grid
+	goals    []Cell
+	distance []float64
+	next     []int
+}
+
+//Creates a flow field leading to the goals given.  Call Refresh after changing the grid.
+func InitFlowField(grid *Grid, goals ...Cell) *FlowField {
+	if len(goals) == 0 {
+		panic("Invalid number of arguments.  Expected at least one goal, found 0.")
+	}
+
+	f := &FlowField{grid: grid, goals: goals}
+	f.Refresh()
+	return f
+}
+
+//Computes the field again from the current costs of the grid
+func (f *FlowField) Refresh() {
+	g := f.grid
+	f.distance = make([]float64, g.width*g.height)
+	f.next = make([]int, g.width*g.height)
+	for i := range f.distance {
+		f.distance[i] = math.Inf(1)
+		f.next[i] = -1
+	}
+
+	open := &queue{}
+	for _, goal := range f.goals {
+		if g.IsWalkable(goal) {
+			f.distance[goal.Y*g.width+goal.X] = 0
+			heap.Push(open, &node{cell: goal})
+		}
+	}
+
+	for open.Len() > 0 {
+		n := heap.Pop(open).(*node)
+		current := n.cell
+		i := current.Y*g.width + current.X
+		if n.priority > f.distance[i] {
+			continue
+		}
+
+		//the moves are searched backwards, so moving from the neighbor into current costs as much as entering current
+		cost := g.GetCost(current)
+		for _, s := range g.neighbors(current) {
+			d := f.distance[i] + cost
+			if s.cell.X != current.X && s.cell.Y != current.Y {
+				d = f.distance[i] + cost*math.Sqrt2
+			}
+
+			j := s.cell.Y*g.width + s.cell.X
+			if d < f.distance[j] {
+				f.distance[j] = d
+				f.next[j] = i
+				heap.Push(open, &node{cell: s.cell, priority: d})
+			}
+		}
+	}
+}
+
+//Returns the cost of reaching the nearest goal from the cell, and false if no goal can be reached
+func (f *FlowField) GetDistance(c Cell) (float64, bool) {
+	if !f.grid.Contains(c) {
+		return 0, false
+	}
+
+	d := f.distance[c.Y*f.grid.width+c.X]
+	if math.IsInf(d, 1) {
+		return 0, false
+	}
+	return d, true
+}
+
+//Returns the cell to move into from c, and false if c is a goal or no goal can be reached
+func (f *FlowField) GetNext(c Cell) (Cell, bool) {
+	if !f.grid.Contains(c) {
+		return Cell{}, false
+	}
+
+	i := f.next[c.Y*f.grid.width+c.X]
+	if i < 0 {
+		return Cell{}, false
+	}
+	return Cell{i % f.grid.width, i / f.grid.width}, true
+}
+
+//Returns the direction to move in from c, such as (1, -1) for up and right, or (0, 0) if there is nowhere to go.
+//Like the rows of the grid, y grows downwards.
+func (f *FlowField) GetDirection(c Cell) (int, int) {
+	n, succ := f.GetNext(c)
+	if !succ {
+		return 0, 0
+	}
+	return n.X - c.X, n.Y - c.Y
+}
+
+//Returns the path from the cell to the nearest goal, including both of them, or nil if no goal can be reached
+func (f *FlowField) GetPath(from Cell) []Cell {
+	if _, succ := f.GetDistance(from); !succ {
+		return nil
+	}
+
+	path := []Cell{from}
+	for c, succ := f.GetNext(from); succ; c, succ = f.GetNext(c) {
+		path = append(path, c)
+	}
+	return path
+}
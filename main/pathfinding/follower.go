@@ -0,0 +1,123 @@
+package pathfinding
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Moves a target along a path at a constant speed.  It is an Animation, so it can be played by an Animator or put in a
+//Sequence.
+type Follower struct {
+	target     framework.Transformable
+	speed      float64
+	points     [][2]float64
+	index      int
+	done       bool
+	onArrive   func(index int)
+	onComplete func()
+}
+
+//Creates a follower that moves the target through the points in the world.
+//	*InitFollower(target, speed, x1, y1, x2, y2, ...)
+//Where:
+//	speed is how far the target moves in a second.
+//	x and y are the positions to move through in order.  The target starts moving from where it is.
+func InitFollower(target framework.Transformable, speed float64, points ...float64) *Follower {
+	if len(points)%2 != 0 {
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected pairs of x and y, found %v values.", len(points)))
+	}
+	if speed <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive speed, found %v", speed))
+	}
+
+	f := &Follower{target: target, speed: speed}
+	for i := 0; i < len(points); i += 2 {
+		f.points = append(f.points, [2]float64{points[i], points[i+1]})
+	}
+	f.done = len(f.points) == 0
+
+	return f
+}
+
+//Creates a follower that moves the target through the centers of the cells of the path.
+func (g *Grid) Follow(target framework.Transformable, speed float64, path []Cell) *Follower {
+	points := make([]float64, 0, len(path)*2)
+	for _, c := range path {
+		x, y := g.ToWorld(c)
+		points = append(points, x, y)
+	}
+	return InitFollower(target, speed, points...)
+}
+
+func (f *Follower) SetSpeed(speed float64) {
+	if speed <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive speed, found %v", speed))
+	}
+	f.speed = speed
+}
+
+func (f *Follower) GetSpeed() float64 {
+	return f.speed
+}
+
+//Sets the function called every time the target reaches a point, with the index of the point.
+func (f *Follower) OnArrive(fn func(index int)) {
+	f.onArrive = fn
+}
+
+//Sets the function called once the target reaches the last point.
+func (f *Follower) OnComplete(fn func()) {
+	f.onComplete = fn
+}
+
+//Returns the index of the point the target is moving to
+func (f *Follower) GetIndex() int {
+	return f.index
+}
+
+func (f *Follower) Update(dt float64) float64 {
+	if f.done {
+		return dt
+	}
+
+	x, y := f.target.GetPosition()
+	for f.index < len(f.points) {
+		p := f.points[f.index]
+		dx, dy := p[0]-x, p[1]-y
+		dist := math.Hypot(dx, dy)
+
+		//stop between two points when the time runs out
+		travel := f.speed * dt
+		if travel < dist {
+			f.target.Move(x+dx/dist*travel, y+dy/dist*travel)
+			return 0
+		}
+
+		x, y = p[0], p[1]
+		f.target.Move(x, y)
+		dt -= dist / f.speed
+
+		if f.onArrive != nil {
+			f.onArrive(f.index)
+		}
+		f.index++
+	}
+
+	f.done = true
+	if f.onComplete != nil {
+		f.onComplete()
+	}
+	return dt
+}
+
+func (f *Follower) IsDone() bool {
+	return f.done
+}
+
+//Sends the target back through the points from the first one, starting from wherever the target is
+func (f *Follower) Reset() {
+	f.index = 0
+	f.done = len(f.points) == 0
+}
@@ -0,0 +1,225 @@
+package pathfinding
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+type diagonalRule uint32
+
+const (
+	//Only moves up, down, left and right
+	DiagonalNever diagonalRule = iota
+	//Moves diagonally even between two blocked cells
+	DiagonalAlways
+	//Moves diagonally when at least one of the two cells beside the move is walkable
+	DiagonalOneCorner
+	//Moves diagonally only when both cells beside the move are walkable, so nothing cuts around a corner
+	DiagonalNoCorners
+)
+
+//Column and row of a cell in a grid.  Row 0 is the top of the grid.
+type Cell struct {
+	X int
+	Y int
+}
+
+//Cost of walking into each cell of a rectangular grid.  A cost of 0 or less blocks the cell.
+type Grid struct {
+	width      int
+	height     int
+	costs      []float64
+	diagonal   diagonalRule
+	x          float64
+	y          float64
+	cellWidth  float64
+	cellHeight float64
+}
+
+//Creates a grid where every cell is walkable with a cost of 1.
+//	*InitGrid(width, height)
+//	*InitGrid(width, height, diagonalRule)
+//Where:
+//	width and height are the number of columns and rows.
+//	diagonalRule is DiagonalNever, DiagonalAlways, DiagonalOneCorner or DiagonalNoCorners.  It is defaulted to
+//	DiagonalNoCorners.
+func InitGrid(width, height int, diagonal ...diagonalRule) *Grid {
+	if width <= 0 || height <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive width and height, found %v and %v", width, height))
+	}
+
+	rule := DiagonalNoCorners
+	switch len(diagonal) {
+	case 0:
+	case 1:
+		rule = diagonal[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one diagonal rule, found %v.", len(diagonal)))
+	}
+
+	costs := make([]float64, width*height)
+	for i := range costs {
+		costs[i] = 1
+	}
+
+	return &Grid{width, height, costs, rule, 0, 0, 1, 1}
+}
+
+//Creates a grid over a layer of the tilemap, placed where the layer is drawn.
+//	*InitGridFromTilemap(tilemap, layer, cost)
+//	*InitGridFromTilemap(tilemap, layer, cost, diagonalRule)
+//Where:
+//	layer is the name of the tile layer the grid is read from.
+//	cost returns the cost of walking into a tile, given its id and custom properties.  The id is 0 where there is no tile,
+//	and the properties are nil for tiles without any.  Return 0 to block the tile.
+func InitGridFromTilemap(tilemap *framework.Tilemap, layer string, cost func(gid uint32, properties framework.Properties) float64, diagonal ...diagonalRule) *Grid {
+	l := tilemap.GetLayer(layer)
+
+	g := InitGrid(l.Width, l.Height, diagonal...)
+	for row := 0; row < l.Height; row++ {
+		for col := 0; col < l.Width; col++ {
+			gid := l.GetTile(col, row)
+			g.costs[row*g.width+col] = cost(gid, tilemap.GetTileProperties(gid))
+		}
+	}
+
+	tw, th := tilemap.GetTileSize()
+	left, top := tilemap.ToWorld(l.OffsetX, l.OffsetY)
+	right, bottom := tilemap.ToWorld(l.OffsetX+float64(tw), l.OffsetY+float64(th))
+	g.SetArea(left, top, right-left, top-bottom)
+
+	return g
+}
+
+//Places the grid in the world, so that paths can be followed by objects.
+//x and y are the top left corner of the cell at column 0 and row 0.  Rows go down from there.
+func (g *Grid) SetArea(x, y, cellWidth, cellHeight float64) {
+	g.x = x
+	g.y = y
+	g.cellWidth = cellWidth
+	g.cellHeight = cellHeight
+}
+
+//Returns the position of the center of the cell in the world
+func (g *Grid) ToWorld(c Cell) (float64, float64) {
+	return g.x + (float64(c.X)+.5)*g.cellWidth, g.y - (float64(c.Y)+.5)*g.cellHeight
+}
+
+//Returns the cell at the position in the world.  The cell may be outside of the grid.
+func (g *Grid) ToCell(x, y float64) Cell {
+	return Cell{int(math.Floor((x - g.x) / g.cellWidth)), int(math.Floor((g.y - y) / g.cellHeight))}
+}
+
+func (g *Grid) GetSize() (int, int) {
+	return g.width, g.height
+}
+
+func (g *Grid) SetDiagonal(rule diagonalRule) {
+	g.diagonal = rule
+}
+
+func (g *Grid) GetDiagonal() diagonalRule {
+	return g.diagonal
+}
+
+func (g *Grid) Contains(c Cell) bool {
+	return c.X >= 0 && c.Y >= 0 && c.X < g.width && c.Y < g.height
+}
+
+//Sets the cost of walking into the cell.  0 or less blocks it.
+func (g *Grid) SetCost(c Cell, cost float64) {
+	if !g.Contains(c) {
+		panic(fmt.Sprintf("Invalid argument.  (%v, %v) is outside of the grid", c.X, c.Y))
+	}
+	g.costs[c.Y*g.width+c.X] = cost
+}
+
+//Returns the cost of walking into the cell, or 0 if it is blocked or outside of the grid
+func (g *Grid) GetCost(c Cell) float64 {
+	if !g.Contains(c) || g.costs[c.Y*g.width+c.X] <= 0 {
+		return 0
+	}
+	return g.costs[c.Y*g.width+c.X]
+}
+
+//Blocks the cell, or makes it walkable with a cost of 1
+func (g *Grid) SetWalkable(c Cell, walkable bool) {
+	if walkable {
+		g.SetCost(c, 1)
+	} else {
+		g.SetCost(c, 0)
+	}
+}
+
+func (g *Grid) IsWalkable(c Cell) bool {
+	return g.GetCost(c) > 0
+}
+
+type step struct {
+	cell Cell
+	cost float64
+}
+
+var (
+	straights = []Cell{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+	diagonals = []Cell{{1, -1}, {1, 1}, {-1, 1}, {-1, -1}}
+)
+
+//Returns the walkable cells next to c, with the cost of moving into each of them.  Moving diagonally costs √2 times the
+//cost of the cell.
+func (g *Grid) neighbors(c Cell) []step {
+	steps := make([]step, 0, 8)
+
+	for _, d := range straights {
+		n := Cell{c.X + d.X, c.Y + d.Y}
+		if cost := g.GetCost(n); cost > 0 {
+			steps = append(steps, step{n, cost})
+		}
+	}
+
+	if g.diagonal == DiagonalNever {
+		return steps
+	}
+
+	for _, d := range diagonals {
+		n := Cell{c.X + d.X, c.Y + d.Y}
+		cost := g.GetCost(n)
+		if cost <= 0 {
+			continue
+		}
+
+		a := g.IsWalkable(Cell{c.X + d.X, c.Y})
+		b := g.IsWalkable(Cell{c.X, c.Y + d.Y})
+		if (g.diagonal == DiagonalOneCorner && !a && !b) || (g.diagonal == DiagonalNoCorners && !(a && b)) {
+			continue
+		}
+
+		steps = append(steps, step{n, cost * math.Sqrt2})
+	}
+
+	return steps
+}
+
+//Returns the lowest cost of any walkable cell, which keeps the estimate of A* from overshooting on cheap cells
+func (g *Grid) minCost() float64 {
+	min := math.Inf(1)
+	for _, cost := range g.costs {
+		if cost > 0 && cost < min {
+			min = cost
+		}
+	}
+	return min
+}
+
+//Estimates the cost from a to b as if every cell on the way cost the same
+func (g *Grid) estimate(a, b Cell, cost float64) float64 {
+	dx := math.Abs(float64(a.X - b.X))
+	dy := math.Abs(float64(a.Y - b.Y))
+
+	if g.diagonal == DiagonalNever {
+		return (dx + dy) * cost
+	}
+	return (math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)) * cost
+}
@@ -0,0 +1,79 @@
+package pathfinding
+
+//Removes the cells of the path that can be skipped by walking in a straight line, leaving only the corners.  A straight
+//line is only taken when every cell it crosses is walkable and costs no more than the cells of the path it replaces, so
+//smoothing never cuts through walls or expensive ground.
+func (g *Grid) Smooth(path []Cell) []Cell {
+	if len(path) < 3 {
+		return append([]Cell(nil), path...)
+	}
+
+	smooth := []Cell{path[0]}
+	anchor := 0
+	for i := 2; i < len(path); i++ {
+		if !g.shortcut(path[anchor : i+1]) {
+			anchor = i - 1
+			smooth = append(smooth, path[anchor])
+		}
+	}
+
+	return append(smooth, path[len(path)-1])
+}
+
+//Returns true if the first and last cells of the part of the path can be joined by a straight line
+func (g *Grid) shortcut(part []Cell) bool {
+	max := 0.0
+	for _, c := range part {
+		if cost := g.GetCost(c); cost > max {
+			max = cost
+		}
+	}
+
+	return g.lineOfSight(part[0], part[len(part)-1], max)
+}
+
+//Returns true if every cell crossed by the line between the centers of a and b is walkable and costs at most max.  A line
+//passing exactly through a corner needs both cells beside the corner.
+func (g *Grid) lineOfSight(a, b Cell, max float64) bool {
+	passable := func(c Cell) bool {
+		cost := g.GetCost(c)
+		return cost > 0 && cost <= max
+	}
+
+	dx, sx := b.X-a.X, 1
+	if dx < 0 {
+		dx, sx = -dx, -1
+	}
+	dy, sy := b.Y-a.Y, 1
+	if dy < 0 {
+		dy, sy = -dy, -1
+	}
+
+	x, y := a.X, a.Y
+	for ix, iy := 0, 0; ix < dx || iy < dy; {
+		//compares where the line leaves the cell horizontally and vertically, scaled up to stay in integers
+		d := (1+2*ix)*dy - (1+2*iy)*dx
+		switch {
+		case d == 0:
+			if !passable(Cell{x + sx, y}) || !passable(Cell{x, y + sy}) {
+				return false
+			}
+			x += sx
+			y += sy
+			ix++
+			iy++
+		case d < 0:
+			x += sx
+			ix++
+		default:
+			y += sy
+			iy++
+		}
+
+		if !passable(Cell{x, y}) {
+			return false
+		}
+	}
+
+	return true
+}
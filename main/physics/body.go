@@ -0,0 +1,140 @@
+package physics
+
+import (
+	"fmt"
+
+	"github.com/koinuri/game-project/main/collision"
+	"github.com/koinuri/game-project/main/framework"
+)
+
+type bodyType uint32
+
+const (
+	//Moved by gravity, forces and collisions
+	Dynamic bodyType = iota
+	//Never moves, such as the ground and walls
+	Static
+	//Moves only by its velocity and pushes dynamic bodies without being pushed back, such as moving platforms
+	Kinematic
+)
+
+//Physical properties of a collider and the object it is attached to.
+type Body struct {
+	collider        *collision.Collider
+	target          framework.Transformable
+	kind            bodyType
+	vx              float64
+	vy              float64
+	angularVelocity float64
+	fx              float64
+	fy              float64
+	mass            float64
+	invMass         float64
+	friction        float64
+	restitution     float64
+	gravityScale    float64
+}
+
+//Creates a body that moves the target of the collider.
+//	*InitBody(collider)
+//	*InitBody(collider, bodyType)
+//Where:
+//	collider gives the shape of the body.  The body moves the collider's target with Move and RadianRotate.
+//	bodyType is Dynamic, Static or Kinematic.  It is defaulted to Dynamic.
+func InitBody(collider *collision.Collider, kind ...bodyType) *Body {
+	if collider == nil {
+		panic("Invalid argument.  The collider cannot be nil")
+	}
+
+	k := Dynamic
+	switch len(kind) {
+	case 0:
+	case 1:
+		k = kind[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one body type, found %v.", len(kind)))
+	}
+
+	b := &Body{
+		collider:     collider,
+		target:       collider.GetTarget(),
+		kind:         k,
+		friction:     0.3,
+		restitution:  0.2,
+		gravityScale: 1,
+	}
+	b.SetMass(1)
+
+	return b
+}
+
+func (b *Body) GetCollider() *collision.Collider {
+	return b.collider
+}
+
+func (b *Body) GetType() bodyType {
+	return b.kind
+}
+
+//Sets the mass of the body.  Static and kinematic bodies ignore it and act as if they were infinitely heavy.
+func (b *Body) SetMass(mass float64) {
+	if mass <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a positive mass, found %v", mass))
+	}
+
+	b.mass = mass
+	b.invMass = 0
+	if b.kind == Dynamic {
+		b.invMass = 1 / mass
+	}
+}
+
+func (b *Body) GetMass() float64 {
+	return b.mass
+}
+
+//Sets the velocity in units per second
+func (b *Body) SetVelocity(x, y float64) {
+	b.vx = x
+	b.vy = y
+}
+
+func (b *Body) GetVelocity() (float64, float64) {
+	return b.vx, b.vy
+}
+
+//Sets the angular velocity in radians per second
+func (b *Body) SetAngularVelocity(v float64) {
+	b.angularVelocity = v
+}
+
+func (b *Body) GetAngularVelocity() float64 {
+	return b.angularVelocity
+}
+
+//Sets how much the body slows down things sliding along it, usually within [0, 1]
+func (b *Body) SetFriction(f float64) {
+	b.friction = f
+}
+
+//Sets how bouncy the body is, from 0 (no bounce) to 1 (keeps all of its speed)
+func (b *Body) SetRestitution(r float64) {
+	b.restitution = r
+}
+
+//Sets how strongly the world's gravity pulls the body.  0 makes it float.
+func (b *Body) SetGravityScale(s float64) {
+	b.gravityScale = s
+}
+
+//Changes the velocity of the body at once, as if it were hit
+func (b *Body) ApplyImpulse(x, y float64) {
+	b.vx += x * b.invMass
+	b.vy += y * b.invMass
+}
+
+//Pushes the body during the next step
+func (b *Body) ApplyForce(x, y float64) {
+	b.fx += x
+	b.fy += y
+}
@@ -0,0 +1,223 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/koinuri/game-project/main/collision"
+)
+
+const (
+	//How much of the overlap is corrected every step, and how much overlap is allowed, to keep resting bodies from jittering
+	correctionPercent = 0.8
+	correctionSlop    = 0.01
+	//Slowest approach speed that still bounces, so resting bodies stay at rest
+	restitutionThreshold = 60.0
+	//Most substeps a single step is split into to keep fast bodies from passing through thin ones
+	maxSubsteps = 8
+)
+
+//Simulates the bodies added to it.
+type World struct {
+	bodies     []*Body
+	byCollider map[*collision.Collider]*Body
+	collisions *collision.World
+	gravityx   float64
+	gravityy   float64
+}
+
+//Creates a world pulling everything down with the gravity given, in units per second squared.
+func InitWorld(gravityx, gravityy float64) *World {
+	return &World{
+		make([]*Body, 0),
+		make(map[*collision.Collider]*Body),
+		collision.InitWorld(),
+		gravityx,
+		gravityy,
+	}
+}
+
+func (w *World) SetGravity(x, y float64) {
+	w.gravityx = x
+	w.gravityy = y
+}
+
+//Returns the collision world holding the colliders of the bodies, for setting an index or listening to collision events
+func (w *World) GetCollisionWorld() *collision.World {
+	return w.collisions
+}
+
+func (w *World) Add(b *Body) {
+	if _, succ := w.byCollider[b.collider]; succ {
+		return
+	}
+
+	w.bodies = append(w.bodies, b)
+	w.byCollider[b.collider] = b
+	w.collisions.Add(b.collider)
+}
+
+func (w *World) Remove(b *Body) {
+	for i, n := range w.bodies {
+		if n == b {
+			w.bodies = append(w.bodies[:i], w.bodies[i+1:]...)
+			break
+		}
+	}
+
+	delete(w.byCollider, b.collider)
+	w.collisions.Remove(b.collider)
+}
+
+//Advances the simulation by dt seconds and moves the objects of the bodies.  Run it once for every step of a
+//framework.Clock:
+//	for steps := clock.Tick(); steps > 0; steps-- {
+//		world.Step(clock.GetStep())
+//	}
+func (w *World) Step(dt float64) {
+	substeps := w.substeps(dt)
+	h := dt / float64(substeps)
+
+	for i := 0; i < substeps; i++ {
+		w.integrate(h)
+		w.resolve()
+	}
+
+	for _, b := range w.bodies {
+		b.fx = 0
+		b.fy = 0
+	}
+
+	w.collisions.Update()
+}
+
+//Returns how many parts the step has to be split into, so that no body moves more than half its size at a time
+func (w *World) substeps(dt float64) int {
+	substeps := 1
+	for _, b := range w.bodies {
+		if b.kind == Static {
+			continue
+		}
+
+		bound := b.collider.GetBounds()
+		size := math.Min(float64(bound.Right-bound.Left), float64(bound.Up-bound.Bottom))
+		distance := math.Hypot(b.vx, b.vy) * dt
+		if size <= 0 || distance == 0 {
+			continue
+		}
+
+		n := int(math.Ceil(distance / (size / 2)))
+		if n > substeps {
+			substeps = n
+		}
+	}
+
+	if substeps > maxSubsteps {
+		substeps = maxSubsteps
+	}
+	return substeps
+}
+
+//Moves every body by its velocity, after gravity and forces change the velocity of dynamic bodies
+func (w *World) integrate(dt float64) {
+	for _, b := range w.bodies {
+		switch b.kind {
+		case Static:
+			continue
+		case Dynamic:
+			b.vx += (w.gravityx*b.gravityScale + b.fx*b.invMass) * dt
+			b.vy += (w.gravityy*b.gravityScale + b.fy*b.invMass) * dt
+		}
+
+		x, y := b.target.GetPosition()
+		b.target.Move(x+b.vx*dt, y+b.vy*dt)
+
+		if b.angularVelocity != 0 {
+			b.target.RadianRotate(b.target.GetAngle() + b.angularVelocity*dt)
+		}
+	}
+}
+
+//Pushes overlapping bodies apart and changes their velocities with impulses
+func (w *World) resolve() {
+	for _, a := range w.bodies {
+		if a.kind != Dynamic {
+			continue
+		}
+
+		hits, penetrations := w.collisions.Query(a.collider)
+		for i, hit := range hits {
+			b, succ := w.byCollider[hit]
+			if !succ {
+				continue
+			}
+
+			//pairs of dynamic bodies are found from both sides, so only solve them once
+			if b.kind == Dynamic && w.indexOf(b) < w.indexOf(a) {
+				continue
+			}
+
+			w.solve(a, b, penetrations[i])
+		}
+	}
+}
+
+func (w *World) indexOf(b *Body) int {
+	for i, n := range w.bodies {
+		if n == b {
+			return i
+		}
+	}
+	return -1
+}
+
+//Separates a from b along the penetration, which moves a out of b
+func (w *World) solve(a, b *Body, penetration collision.Vector) {
+	invMass := a.invMass + b.invMass
+	depth := penetration.Len()
+	if invMass == 0 || depth == 0 {
+		return
+	}
+	n := penetration.Scale(1 / depth)
+
+	//push the bodies apart
+	correction := n.Scale(math.Max(depth-correctionSlop, 0) / invMass * correctionPercent)
+	ax, ay := a.target.GetPosition()
+	a.target.Move(ax+correction.X*a.invMass, ay+correction.Y*a.invMass)
+	if b.kind == Dynamic {
+		bx, by := b.target.GetPosition()
+		b.target.Move(bx-correction.X*b.invMass, by-correction.Y*b.invMass)
+	}
+
+	//only bodies moving towards each other bounce
+	rv := collision.Vector{X: a.vx - b.vx, Y: a.vy - b.vy}
+	vn := rv.Dot(n)
+	if vn >= 0 {
+		return
+	}
+
+	e := math.Min(a.restitution, b.restitution)
+	if -vn < restitutionThreshold {
+		e = 0
+	}
+	j := -(1 + e) * vn / invMass
+	w.impulse(a, b, n.Scale(j))
+
+	//friction works against the sliding along the contact, and cannot be stronger than the impulse pushing the bodies apart
+	rv = collision.Vector{X: a.vx - b.vx, Y: a.vy - b.vy}
+	tangent := rv.Sub(n.Scale(rv.Dot(n))).Normalize()
+	if tangent.Len() == 0 {
+		return
+	}
+
+	mu := math.Sqrt(a.friction * b.friction)
+	jt := -rv.Dot(tangent) / invMass
+	jt = math.Max(-j*mu, math.Min(jt, j*mu))
+	w.impulse(a, b, tangent.Scale(jt))
+}
+
+func (w *World) impulse(a, b *Body, i collision.Vector) {
+	a.vx += i.X * a.invMass
+	a.vy += i.Y * a.invMass
+	b.vx -= i.X * b.invMass
+	b.vy -= i.Y * b.invMass
+}
@@ -0,0 +1,76 @@
+package random
+
+import (
+	"fmt"
+	"time"
+)
+
+//Random number generator whose whole state is one number, so it can be saved and restored to get the same numbers again.
+//It is not safe for cryptography.
+type Rand struct {
+	state uint64
+}
+
+//Creates a generator.
+//	*InitRand()
+//	*InitRand(seed)
+//Where:
+//	seed decides the numbers generated.  It is defaulted to the current time.
+func InitRand(seed ...uint64) *Rand {
+	switch len(seed) {
+	case 0:
+		return &Rand{uint64(time.Now().UnixNano())}
+	case 1:
+		return &Rand{seed[0]}
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one seed, found %v.", len(seed)))
+	}
+}
+
+//Returns the state of the generator, which SetState restores
+func (r *Rand) GetState() uint64 {
+	return r.state
+}
+
+func (r *Rand) SetState(state uint64) {
+	r.state = state
+}
+
+//Returns a number from the whole range of uint64, using splitmix64
+func (r *Rand) Uint64() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+//Returns a number within [0, 1)
+func (r *Rand) Float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+//Returns a number within [0, n)
+func (r *Rand) Intn(n int) int {
+	if n <= 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a number above 0, found %v", n))
+	}
+
+	//numbers past the last whole multiple of n are drawn again, or the low results would come up more often
+	limit := ^uint64(0) - ^uint64(0)%uint64(n)
+	for {
+		if v := r.Uint64(); v < limit {
+			return int(v % uint64(n))
+		}
+	}
+}
+
+//Returns a number within [min, max)
+func (r *Rand) Range(min, max float64) float64 {
+	return min + r.Float64()*(max-min)
+}
+
+//Returns true with the chance given, from 0 for never to 1 for always
+func (r *Rand) Chance(p float64) bool {
+	return r.Float64() < p
+}
@@ -0,0 +1,266 @@
+package save
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koinuri/game-project/main/framework"
+	"github.com/koinuri/game-project/main/global"
+	"github.com/koinuri/game-project/main/story"
+)
+
+//Version of the save files written by the game.  When the layout of Save changes, the version goes up and a migration from
+//the version before is registered with RegisterMigration, so older saves can still be loaded.
+const Version = 1
+
+//Returned by Read when the file is not a save, or was changed since it was written, such as by a disk failing
+var ErrCorrupted = errors.New("the save is corrupted")
+
+//Returned by Read when the file was written by a newer version of the game
+var ErrNewer = errors.New("the save was made by a newer version of the game")
+
+//Layout of the save files.  The checksum is the sha256 of the bytes of the save exactly as they are in the file.
+type file struct {
+	Version  int             `json:"version"`
+	Checksum string          `json:"checksum"`
+	Save     json.RawMessage `json:"save"`
+}
+
+//A saved game, with what is shown in the list of slots and the state of the game
+type Save struct {
+	//name shown for the save, such as the chapter
+	Title     string    `json:"title"`
+	Timestamp time.Time `json:"timestamp"`
+	//seconds played until the save was made
+	Playtime float64 `json:"playtime"`
+	//png image of the game when it was saved
+	Thumbnail []byte `json:"thumbnail"`
+	Scene     string `json:"scene"`
+	//transforms of the objects of the scene, by a name given by the game
+	Objects map[string]ObjectState `json:"objects"`
+	//state of the runner of the script of the scene, if there is one
+	Story *story.State `json:"story,omitempty"`
+	//state of the random number generator of the game
+	Random uint64 `json:"random"`
+	//anything else the game keeps, as numbers, bools, strings, and lists and maps of them
+	Values map[string]interface{} `json:"values"`
+}
+
+//Transform and tint of an object
+type ObjectState struct {
+	X      float64    `json:"x"`
+	Y      float64    `json:"y"`
+	ScaleX float64    `json:"scaleX"`
+	ScaleY float64    `json:"scaleY"`
+	Angle  float64    `json:"angle"`
+	Color  [3]float64 `json:"color"`
+	Alpha  float64    `json:"alpha"`
+}
+
+//Creates an empty save of the scene
+func InitSave(scene string) *Save {
+	return &Save{
+		Scene:   scene,
+		Objects: make(map[string]ObjectState),
+		Values:  make(map[string]interface{}),
+	}
+}
+
+//Keeps the transform and tint of the object under the name
+func (s *Save) SaveObject(name string, o *framework.Object) {
+	var st ObjectState
+	st.X, st.Y = o.GetPosition()
+	st.ScaleX, st.ScaleY = o.GetScale()
+	st.Angle = o.GetAngle()
+	st.Color[0], st.Color[1], st.Color[2] = o.GetColor()
+	st.Alpha = o.GetAlpha()
+	s.Objects[name] = st
+}
+
+//Puts the object back as it was kept under the name, and returns false if there is nothing under the name
+func (s *Save) LoadObject(name string, o *framework.Object) bool {
+	st, succ := s.Objects[name]
+	if !succ {
+		return false
+	}
+
+	o.Move(st.X, st.Y)
+	o.Scale(st.ScaleX, st.ScaleY)
+	o.RadianRotate(st.Angle)
+	o.SetColor(st.Color[0], st.Color[1], st.Color[2])
+	o.SetAlpha(st.Alpha)
+	return true
+}
+
+//Sets the thumbnail to the image, usually one taken with framework.Screenshot
+func (s *Save) SetThumbnail(img image.Image) error {
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return err
+	}
+	s.Thumbnail = b.Bytes()
+	return nil
+}
+
+//Returns the thumbnail, or nil if the save has none.  Show it with framework.InitImageSprite.
+func (s *Save) GetThumbnail() (image.Image, error) {
+	if len(s.Thumbnail) == 0 {
+		return nil, nil
+	}
+	return png.Decode(bytes.NewReader(s.Thumbnail))
+}
+
+//Migrations from every version to the next, on the save as it was decoded from the json
+var migrations = map[int]func(map[string]interface{}) error{}
+
+//Registers the function turning a save of the version into one of the next version.  Saves older than Version go through
+//every migration from their version up when they are read.
+func RegisterMigration(from int, f func(save map[string]interface{}) error) {
+	if from < 1 || from >= Version {
+		panic(fmt.Sprintf("Invalid argument.  Expected a version from 1 to %v, found %v", Version-1, from))
+	}
+	if _, succ := migrations[from]; succ {
+		panic(fmt.Sprintf("Invalid argument.  A migration from the version %v already exists", from))
+	}
+	migrations[from] = f
+}
+
+//Returns the directory the saves are written to
+func GetDirectory() string {
+	return filepath.Join(global.UserDirectory, "saves")
+}
+
+//Returns the file of the slot
+func GetFile(slot int) string {
+	return filepath.Join(GetDirectory(), fmt.Sprintf("slot%v.sav", slot))
+}
+
+//Writes the save to the slot, replacing the save that was there only once the new one is written.  The timestamp is set
+//to the current time if it was not set.
+func Write(slot int, s *Save) error {
+	if slot < 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a slot of 0 or more, found %v", slot))
+	}
+	if s.Timestamp.IsZero() {
+		s.Timestamp = time.Now()
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+
+	out, err := json.Marshal(file{Version: Version, Checksum: hex.EncodeToString(sum[:]), Save: data})
+	if err != nil {
+		return err
+	}
+
+	return global.WriteFile(GetFile(slot), out)
+}
+
+//Reads the save in the slot, migrating it to the current version.  The error is ErrCorrupted if the file is damaged,
+//ErrNewer if it was made by a newer version of the game, or one for which os.IsNotExist is true if the slot is empty.
+func Read(slot int) (*Save, error) {
+	data, err := os.ReadFile(GetFile(slot))
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, ErrCorrupted
+	}
+	//a newer game may check its saves another way, so they are not taken for corrupted ones
+	if f.Version > Version {
+		return nil, ErrNewer
+	}
+	if f.Version < 1 || len(f.Save) == 0 {
+		return nil, ErrCorrupted
+	}
+	sum := sha256.Sum256(f.Save)
+	if hex.EncodeToString(sum[:]) != strings.ToLower(f.Checksum) {
+		return nil, ErrCorrupted
+	}
+
+	raw := []byte(f.Save)
+	if f.Version < Version {
+		if raw, err = migrate(raw, f.Version, Version); err != nil {
+			return nil, err
+		}
+	}
+
+	s := InitSave("")
+	if err := json.Unmarshal(raw, s); err != nil {
+		return nil, ErrCorrupted
+	}
+	return s, nil
+}
+
+//Runs the migrations from the version up to the target version
+func migrate(raw []byte, version int, target int) ([]byte, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, ErrCorrupted
+	}
+
+	for v := version; v < target; v++ {
+		f, succ := migrations[v]
+		if !succ {
+			return nil, fmt.Errorf("no migration from the version %v of the saves", v)
+		}
+		if err := f(m); err != nil {
+			return nil, fmt.Errorf("could not migrate the save from the version %v.\n%v", v, err)
+		}
+	}
+	return json.Marshal(m)
+}
+
+//Returns true if there is a save in the slot, even a damaged one
+func Exists(slot int) bool {
+	_, err := os.Stat(GetFile(slot))
+	return err == nil
+}
+
+//Removes the save in the slot, if there is one
+func Delete(slot int) error {
+	err := os.Remove(GetFile(slot))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+//Returns the slots that have a save, in order
+func GetSlots() []int {
+	slots := make([]int, 0)
+
+	files, err := os.ReadDir(GetDirectory())
+	if err != nil {
+		return slots
+	}
+	for _, f := range files {
+		name := f.Name()
+		if !strings.HasPrefix(name, "slot") || !strings.HasSuffix(name, ".sav") {
+			continue
+		}
+		if slot, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "slot"), ".sav")); err == nil && slot >= 0 {
+			slots = append(slots, slot)
+		}
+	}
+
+	sort.Ints(slots)
+	return slots
+}
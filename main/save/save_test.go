@@ -0,0 +1,178 @@
+package save
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/koinuri/game-project/main/global"
+)
+
+//Points the saves at a directory of the test
+func useTempDirectory(t *testing.T) {
+	dir := global.UserDirectory
+	global.UserDirectory = t.TempDir()
+	t.Cleanup(func() { global.UserDirectory = dir })
+}
+
+func TestWriteRead(t *testing.T) {
+	useTempDirectory(t)
+
+	s := InitSave("town")
+	s.Title = "Chapter 1"
+	s.Timestamp = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.Playtime = 61.5
+	s.Random = 42
+	s.Objects["player"] = ObjectState{X: 10, Y: 20, ScaleX: 1, ScaleY: 2, Angle: .5, Color: [3]float64{1, .5, 0}, Alpha: .8}
+	s.Values["gold"] = 12.0
+	s.Values["flags"] = []interface{}{"met", true}
+
+	if err := Write(3, s); err != nil {
+		t.Fatalf("writing failed: %v", err)
+	}
+	if _, err := os.Stat(GetFile(3) + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("the temporary file was left behind: %v", err)
+	}
+
+	got, err := Read(3)
+	if err != nil {
+		t.Fatalf("reading failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("read %+v, want %+v", got, s)
+	}
+
+	if !Exists(3) || Exists(4) {
+		t.Errorf("got slot 3 existing %v and slot 4 existing %v, want only slot 3", Exists(3), Exists(4))
+	}
+	if slots := GetSlots(); !reflect.DeepEqual(slots, []int{3}) {
+		t.Errorf("got the slots %v, want [3]", slots)
+	}
+	if err := Delete(3); err != nil || Exists(3) {
+		t.Errorf("deleting failed: %v", err)
+	}
+	if _, err := Read(3); !os.IsNotExist(err) {
+		t.Errorf("reading an empty slot gave %v, want one for which os.IsNotExist is true", err)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	useTempDirectory(t)
+
+	s := InitSave("town")
+	s.Title = "Before"
+	if err := Write(0, s); err != nil {
+		t.Fatalf("writing failed: %v", err)
+	}
+	valid, err := os.ReadFile(GetFile(0))
+	if err != nil {
+		t.Fatalf("reading the file failed: %v", err)
+	}
+
+	//a file as written by the version, with the checksum of another payload
+	other := func(version int) []byte {
+		out, _ := json.Marshal(file{Version: version, Checksum: "00", Save: json.RawMessage(`{"title":"After"}`)})
+		return out
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{
+			name: "tampered payload",
+			data: bytes.Replace(valid, []byte(`"Before"`), []byte(`"After"`), 1),
+			want: ErrCorrupted,
+		},
+		{
+			name: "tampered checksum",
+			data: bytes.Replace(valid, []byte(`"checksum":"`), []byte(`"checksum":"0`), 1),
+			want: ErrCorrupted,
+		},
+		{
+			name: "not json",
+			data: valid[:len(valid)/2],
+			want: ErrCorrupted,
+		},
+		{
+			name: "no version",
+			data: other(0),
+			want: ErrCorrupted,
+		},
+		{
+			name: "current version with a bad checksum",
+			data: other(Version),
+			want: ErrCorrupted,
+		},
+		{
+			name: "newer version with a bad checksum",
+			data: other(Version + 1),
+			want: ErrNewer,
+		},
+	}
+
+	for _, c := range cases {
+		if err := os.WriteFile(GetFile(0), c.data, 0644); err != nil {
+			t.Fatalf("%v: writing the file failed: %v", c.name, err)
+		}
+		if _, err := Read(0); err != c.want {
+			t.Errorf("%v: got %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	registered := migrations
+	t.Cleanup(func() { migrations = registered })
+
+	migrations = map[int]func(map[string]interface{}) error{
+		1: func(save map[string]interface{}) error {
+			save["title"] = save["name"]
+			delete(save, "name")
+			return nil
+		},
+		2: func(save map[string]interface{}) error {
+			save["chapter"] = 1.0
+			return nil
+		},
+		3: func(save map[string]interface{}) error {
+			return fmt.Errorf("broken")
+		},
+	}
+
+	cases := []struct {
+		name    string
+		version int
+		target  int
+		want    string
+		wantErr bool
+	}{
+		{name: "chained", version: 1, target: 3, want: `{"chapter":1,"title":"Akane"}`},
+		{name: "from the middle", version: 2, target: 3, want: `{"chapter":1,"name":"Akane"}`},
+		{name: "current", version: 3, target: 3, want: `{"name":"Akane"}`},
+		{name: "failing", version: 1, target: 4, wantErr: true},
+		{name: "missing", version: 4, target: 5, wantErr: true},
+	}
+
+	for _, c := range cases {
+		got, err := migrate([]byte(`{"name":"Akane"}`), c.version, c.target)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%v: got %s, want an error", c.name, got)
+			}
+			continue
+		}
+		if err != nil || string(got) != c.want {
+			t.Errorf("%v: got %s (%v), want %v", c.name, got, err, c.want)
+		}
+	}
+
+	if _, err := migrate([]byte(`[1]`), 1, 3); err != ErrCorrupted {
+		t.Errorf("migrating a save that is not an object gave %v, want %v", err, ErrCorrupted)
+	}
+}
@@ -0,0 +1,100 @@
+package story
+
+import (
+	"fmt"
+)
+
+//A line said, as it is kept in the backlog
+type BacklogEntry struct {
+	Name  string     `json:"name"`
+	Color [3]float64 `json:"color"`
+	Text  string     `json:"text"`
+	//sound of the line being spoken, or an empty string
+	Voice string `json:"voice"`
+}
+
+//Lines said most recently, from the oldest to the newest
+type Backlog struct {
+	entries []BacklogEntry
+	limit   int
+	version int
+	//lines added with Add since the entries were last replaced, and the number of times they were replaced, which tell
+	//views to add rows for the new lines only
+	added    int
+	replaced int
+}
+
+//Creates an empty backlog.
+//	*InitBacklog()
+//	*InitBacklog(limit)
+//Where:
+//	limit is the number of lines kept before the oldest are dropped.  It is defaulted to 200.
+func InitBacklog(limit ...int) *Backlog {
+	b := &Backlog{
+		entries: make([]BacklogEntry, 0),
+		limit:   200,
+	}
+
+	switch len(limit) {
+	case 0:
+	case 1:
+		b.SetLimit(limit[0])
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one limit, found %v.", len(limit)))
+	}
+	return b
+}
+
+//Sets the number of lines kept, dropping the oldest ones beyond it
+func (b *Backlog) SetLimit(limit int) {
+	if limit < 1 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a limit of 1 or more, found %v", limit))
+	}
+	b.limit = limit
+	b.trim()
+	b.version++
+}
+
+func (b *Backlog) GetLimit() int {
+	return b.limit
+}
+
+func (b *Backlog) Add(e BacklogEntry) {
+	b.entries = append(b.entries, e)
+	b.trim()
+	b.added++
+	b.version++
+}
+
+func (b *Backlog) trim() {
+	if over := len(b.entries) - b.limit; over > 0 {
+		b.entries = append(b.entries[:0], b.entries[over:]...)
+	}
+}
+
+//Replaces the lines kept, such as when a game is loaded
+func (b *Backlog) SetEntries(entries []BacklogEntry) {
+	b.entries = append(b.entries[:0], entries...)
+	b.trim()
+	b.added = 0
+	b.replaced++
+	b.version++
+}
+
+//Returns the lines kept, from the oldest to the newest
+func (b *Backlog) GetEntries() []BacklogEntry {
+	entries := make([]BacklogEntry, len(b.entries))
+	copy(entries, b.entries)
+	return entries
+}
+
+func (b *Backlog) GetLength() int {
+	return len(b.entries)
+}
+
+func (b *Backlog) Clear() {
+	b.entries = b.entries[:0]
+	b.added = 0
+	b.replaced++
+	b.version++
+}
@@ -0,0 +1,151 @@
+package story
+
+import (
+	"github.com/koinuri/game-project/main/framework"
+	"github.com/koinuri/game-project/main/ui"
+)
+
+//Scroll panel listing the backlog of a runner with the newest line at the bottom, and a button replaying the voice of
+//every line that has one.  It keeps to the bottom as lines are added unless the player scrolled up.
+type BacklogView struct {
+	*ui.ScrollPanel
+	runner *Runner
+	rows   *backlogRows
+	theme  *ui.Theme
+	follow bool
+	pinned float64
+}
+
+//Box of the rows of a backlog view, one for each entry.  Rows are added for new lines and freed when their lines are
+//dropped, and they are only all built again when the entries of the backlog are replaced.
+type backlogRows struct {
+	*ui.Box
+	view     *BacklogView
+	backlog  *Backlog
+	version  int
+	added    int
+	replaced int
+}
+
+//Creates a view of the backlog of the runner.
+//	*InitBacklogView(runner)
+//	*InitBacklogView(runner, theme)
+//Where:
+//	theme is the theme the lines and buttons are drawn with.  It is defaulted to ui.DefaultTheme.
+//Give it a size with SetMinSize or by its container, as with any scroll panel.
+func InitBacklogView(r *Runner, theme ...*ui.Theme) *BacklogView {
+	if r == nil {
+		panic("Invalid argument.  The runner cannot be nil")
+	}
+
+	v := &BacklogView{
+		runner: r,
+		theme:  ui.DefaultTheme(),
+		follow: true,
+	}
+	if len(theme) > 0 && theme[0] != nil {
+		v.theme = theme[0]
+	}
+
+	v.rows = &backlogRows{Box: ui.InitVBox(), view: v, version: -1}
+	v.ScrollPanel = ui.InitScrollPanel(v.rows, v.theme)
+	return v
+}
+
+//Scrolls to the newest line and keeps to it as lines are added, such as when the view is opened
+func (v *BacklogView) ScrollToEnd() {
+	v.follow = true
+}
+
+func (v *BacklogView) GetArtists() []framework.Artist {
+	//the player scrolling up lets go of the bottom, and scrolling back down takes hold of it again
+	if v.follow && v.GetScroll() < v.pinned {
+		v.follow = false
+	}
+	if v.GetScroll() >= v.GetMaxScroll() {
+		v.follow = true
+	}
+
+	//wrapped lines only know their height once drawn, so the bottom is found again every frame
+	if v.follow {
+		v.SetScroll(v.GetMaxScroll())
+	}
+	v.pinned = v.GetScroll()
+
+	return v.ScrollPanel.GetArtists()
+}
+
+func (rows *backlogRows) GetPreferredSize() (float64, float64) {
+	b := rows.view.runner.GetBacklog()
+	if b != rows.backlog || b.replaced != rows.replaced {
+		rows.backlog = b
+		rows.replaced = b.replaced
+		//every line kept is new to the view
+		rows.added = b.added - b.GetLength()
+		rows.clear()
+	}
+	if b.version != rows.version {
+		rows.version = b.version
+		rows.update()
+	}
+	return rows.Box.GetPreferredSize()
+}
+
+//Frees every row
+func (rows *backlogRows) clear() {
+	for _, row := range rows.GetChildren() {
+		ui.Delete(row)
+	}
+	rows.Clear()
+}
+
+//Adds rows for the lines added since the last update, and frees the rows of the oldest lines the backlog dropped
+func (rows *backlogRows) update() {
+	entries := rows.backlog.GetEntries()
+	added := rows.backlog.added - rows.added
+	if added > len(entries) {
+		added = len(entries)
+	}
+	rows.added = rows.backlog.added
+
+	children := rows.GetChildren()
+	dropped := len(children) + added - len(entries)
+	if dropped > len(children) {
+		dropped = len(children)
+	}
+	for _, row := range children[:dropped] {
+		ui.Delete(row)
+	}
+	rows.Clear()
+	rows.Add(children[dropped:]...)
+
+	for _, e := range entries[len(entries)-added:] {
+		rows.Add(rows.row(e))
+	}
+}
+
+func (rows *backlogRows) row(e BacklogEntry) ui.Widget {
+	theme := rows.view.theme
+	row := ui.InitVBox()
+	row.SetSpacing(0)
+
+	if e.Name != "" {
+		row.Add(ui.InitLabel(e.Name, theme))
+	}
+
+	text := ui.InitLabel(e.Text, theme)
+	text.SetWrap(true)
+	row.Add(text)
+
+	if e.Voice != "" {
+		voice := e.Voice
+		replay := ui.InitButton("Voice", theme)
+		replay.OnClick(func() {
+			rows.view.runner.PlayVoice(voice)
+		})
+
+		//a horizontal box keeps the button to its own width rather than the width of the row
+		row.Add(ui.InitHBox(replay))
+	}
+	return row
+}
@@ -0,0 +1,387 @@
+package story
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//Expressions of the scripts, used by conditions and assignments.  Values are float64, bool or string, and variables that
+//were never set are 0.
+type expression interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+type literal struct {
+	value interface{}
+}
+
+type variable struct {
+	name string
+}
+
+type unary struct {
+	op      string
+	operand expression
+}
+
+type binary struct {
+	op    string
+	left  expression
+	right expression
+}
+
+func (l literal) eval(vars map[string]interface{}) (interface{}, error) {
+	return l.value, nil
+}
+
+func (v variable) eval(vars map[string]interface{}) (interface{}, error) {
+	if value, succ := vars[v.name]; succ {
+		return value, nil
+	}
+	return 0.0, nil
+}
+
+func (u unary) eval(vars map[string]interface{}) (interface{}, error) {
+	v, err := u.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.op {
+	case "!":
+		return !truthy(v), nil
+	case "-":
+		f, succ := v.(float64)
+		if !succ {
+			return nil, fmt.Errorf("cannot negate %q", format(v))
+		}
+		return -f, nil
+	}
+	return nil, fmt.Errorf("unknown operator %v", u.op)
+}
+
+func (b binary) eval(vars map[string]interface{}) (interface{}, error) {
+	l, err := b.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	//the right side is only evaluated when it decides the result
+	switch b.op {
+	case "&&":
+		if !truthy(l) {
+			return false, nil
+		}
+		r, err := b.right.eval(vars)
+		return truthy(r), err
+	case "||":
+		if truthy(l) {
+			return true, nil
+		}
+		r, err := b.right.eval(vars)
+		return truthy(r), err
+	}
+
+	r, err := b.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch b.op {
+	case "==":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	}
+
+	//strings are joined by + and compared in order, and numbers do everything else
+	if ls, succ := l.(string); succ {
+		rs, succ := r.(string)
+		if b.op == "+" {
+			return ls + format(r), nil
+		}
+		if succ {
+			switch b.op {
+			case "<":
+				return ls < rs, nil
+			case "<=":
+				return ls <= rs, nil
+			case ">":
+				return ls > rs, nil
+			case ">=":
+				return ls >= rs, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot use %v on %q and %q", b.op, format(l), format(r))
+	}
+
+	lf, lsucc := l.(float64)
+	rf, rsucc := r.(float64)
+	if !lsucc || !rsucc {
+		return nil, fmt.Errorf("cannot use %v on %q and %q", b.op, format(l), format(r))
+	}
+
+	switch b.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return lf / rf, nil
+	case "%":
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(lf, rf), nil
+	case "<":
+		return lf < rf, nil
+	case "<=":
+		return lf <= rf, nil
+	case ">":
+		return lf > rf, nil
+	case ">=":
+		return lf >= rf, nil
+	}
+	return nil, fmt.Errorf("unknown operator %v", b.op)
+}
+
+//Returns whether the value counts as true in a condition, which every value but false, 0 and "" does
+func truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case float64:
+		return t != 0
+	case string:
+		return t != ""
+	}
+	return v != nil
+}
+
+//Returns the value as it is shown in text, with whole numbers written without decimals
+func format(v interface{}) string {
+	switch t := v.(type) {
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case string:
+		return t
+	}
+	return fmt.Sprint(v)
+}
+
+//Operators from the loosest to the tightest binding.  Words are aliases of the symbols, as in "met and not angry".
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+var aliases = map[string]string{
+	"and": "&&",
+	"or":  "||",
+	"not": "!",
+}
+
+//Reads expressions one token at a time
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func parseExpression(source string) (expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("expected an expression")
+	}
+
+	p := &parser{tokens: tokens}
+	e, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) parse(level int) (expression, error) {
+	if level == len(precedence) {
+		return p.unary()
+	}
+
+	left, err := p.parse(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peek()
+		found := false
+		for _, o := range precedence[level] {
+			if op == o {
+				found = true
+			}
+		}
+		if !found {
+			return left, nil
+		}
+
+		p.pos++
+		right, err := p.parse(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op, left, right}
+	}
+}
+
+func (p *parser) unary() (expression, error) {
+	switch op := p.peek(); op {
+	case "!", "-":
+		p.pos++
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op, operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (expression, error) {
+	token := p.peek()
+	if token == "" {
+		return nil, fmt.Errorf("unexpected end of the expression")
+	}
+	p.pos++
+
+	switch {
+	case token == "(":
+		e, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("expected )")
+		}
+		p.pos++
+		return e, nil
+	case token == "true" || token == "false":
+		return literal{token == "true"}, nil
+	case token[0] == '"':
+		s, err := strconv.Unquote(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %v", token)
+		}
+		return literal{s}, nil
+	case unicode.IsDigit(rune(token[0])) || token[0] == '.':
+		f, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %v", token)
+		}
+		return literal{f}, nil
+	case isName(token):
+		return variable{token}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", token)
+}
+
+//Returns true if the token can name a variable, a label or a character
+func isName(token string) bool {
+	if token == "" {
+		return false
+	}
+	for i, r := range token {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return true
+}
+
+//Splits an expression into numbers, strings, names and operators
+func tokenize(source string) ([]string, error) {
+	tokens := make([]string, 0)
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			end, err := closingQuote(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end + 1
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			word := string(runes[start:i])
+			if alias, succ := aliases[word]; succ {
+				word = alias
+			}
+			tokens = append(tokens, word)
+		default:
+			if i+1 < len(runes) {
+				two := string(runes[i : i+2])
+				if strings.Contains(" == != <= >= && || ", " "+two+" ") {
+					tokens = append(tokens, two)
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("<>+-*/%!()", r) {
+				return nil, fmt.Errorf("unexpected %q", string(r))
+			}
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+//Returns the index of the quote closing the string starting at the index, skipping escaped quotes
+func closingQuote(runes []rune, start int) (int, error) {
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			i++
+		case '"':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unclosed string")
+}
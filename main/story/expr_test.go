@@ -0,0 +1,109 @@
+package story
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]interface{}{
+		"met":       true,
+		"angry":     false,
+		"affection": 3.0,
+		"player":    "Mio",
+	}
+
+	cases := []struct {
+		name   string
+		source string
+		want   interface{}
+	}{
+		{name: "number", source: "1.5", want: 1.5},
+		{name: "string", source: `"a \"b\""`, want: `a "b"`},
+		{name: "unset variable", source: "missing", want: 0.0},
+		{name: "multiplication before addition", source: "1 + 2 * 3", want: 7.0},
+		{name: "parentheses", source: "(1 + 2) * 3", want: 9.0},
+		{name: "left to right", source: "10 - 4 - 3", want: 3.0},
+		{name: "division left to right", source: "12 / 3 / 2", want: 2.0},
+		{name: "modulo", source: "7 % 4", want: 3.0},
+		{name: "negation", source: "-affection + 1", want: -2.0},
+		{name: "addition before comparison", source: "affection + 1 > 3", want: true},
+		{name: "comparison before equality", source: "1 < 2 == true", want: true},
+		{name: "and before or", source: "true or false and false", want: true},
+		{name: "not before and", source: "not angry and met", want: true},
+		{name: "and alias", source: "met and affection >= 3", want: true},
+		{name: "or alias", source: "angry or affection < 3", want: false},
+		{name: "not alias", source: "not met", want: false},
+		{name: "symbols", source: "!angry && (met || angry)", want: true},
+		{name: "and skips the right side", source: "angry and 1 / 0", want: false},
+		{name: "or skips the right side", source: "met or 1 / 0", want: true},
+		{name: "truthy string", source: `"" or player`, want: true},
+		{name: "string joined", source: `"Hi, " + player + " x" + affection`, want: "Hi, Mio x3"},
+		{name: "string order", source: `player < "Nao"`, want: true},
+		{name: "different types", source: `affection == "3"`, want: false},
+	}
+
+	for _, c := range cases {
+		e, err := parseExpression(c.source)
+		if err != nil {
+			t.Errorf("%v: parsing %q failed: %v", c.name, c.source, err)
+			continue
+		}
+		got, err := e.eval(vars)
+		if err != nil {
+			t.Errorf("%v: evaluating %q failed: %v", c.name, c.source, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%v: %q is %#v, want %#v", c.name, c.source, got, c.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "division by zero", source: "1 / 0", want: "division by zero"},
+		{name: "modulo by zero", source: "5 % (2 - 2)", want: "division by zero"},
+		{name: "unset divisor", source: "1 / missing", want: "division by zero"},
+		{name: "negated string", source: `-"a"`, want: "cannot negate"},
+		{name: "string minus", source: `"a" - 1`, want: "cannot use -"},
+		{name: "bool plus", source: "true + 1", want: "cannot use +"},
+	}
+
+	for _, c := range cases {
+		e, err := parseExpression(c.source)
+		if err != nil {
+			t.Errorf("%v: parsing %q failed: %v", c.name, c.source, err)
+			continue
+		}
+		if _, err := e.eval(map[string]interface{}{}); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%v: evaluating %q gave %v, want an error with %q", c.name, c.source, err, c.want)
+		}
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "empty", source: "  ", want: "expected an expression"},
+		{name: "unclosed string", source: `"abc`, want: "unclosed string"},
+		{name: "unclosed parenthesis", source: "(1 + 2", want: "expected )"},
+		{name: "missing operand", source: "1 +", want: "unexpected end"},
+		{name: "trailing token", source: "1 2", want: `unexpected "2"`},
+		{name: "unknown character", source: "a = b", want: `unexpected "="`},
+		{name: "invalid number", source: "1.2.3", want: "invalid number"},
+	}
+
+	for _, c := range cases {
+		if _, err := parseExpression(c.source); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%v: parsing %q gave %v, want an error with %q", c.name, c.source, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package story
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/koinuri/game-project/main/global"
+)
+
+const readLogVersion = 1
+
+//Layout of the read log files
+type readLogFile struct {
+	Version int      `json:"version"`
+	Lines   []string `json:"lines"`
+}
+
+//Lines the player has gone past, kept across playthroughs so skip mode only skips what was read before.  Lines are told
+//apart by their script, speaker and text, so the log stays right when other lines of the script are edited.
+type ReadLog struct {
+	file  string
+	lines map[string]bool
+}
+
+//Creates an empty read log that is not saved to any file
+func InitReadLog() *ReadLog {
+	return &ReadLog{lines: make(map[string]bool)}
+}
+
+//Loads the read log from the file in global.UserDirectory, starting an empty one if there is no such file yet.  Save
+//writes it back to the same file.
+func LoadReadLog(name string) *ReadLog {
+	l := InitReadLog()
+	l.file = filepath.Join(global.UserDirectory, name)
+
+	data, err := os.ReadFile(l.file)
+	if os.IsNotExist(err) {
+		return l
+	}
+
+	var file readLogFile
+	if err == nil {
+		err = json.Unmarshal(data, &file)
+	}
+	if err == nil && file.Version > readLogVersion {
+		err = fmt.Errorf("the version %v is newer than the game, which reads up to %v", file.Version, readLogVersion)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the file \"%v\".\n%v", l.file, err))
+	}
+
+	for _, line := range file.Lines {
+		l.lines[line] = true
+	}
+	return l
+}
+
+//Writes the log to the file it was loaded from, replacing the file only once the whole log is written
+func (l *ReadLog) Save() error {
+	if l.file == "" {
+		return errors.New("the read log was not loaded from a file")
+	}
+
+	file := readLogFile{Version: readLogVersion, Lines: make([]string, 0, len(l.lines))}
+	for line := range l.lines {
+		file.Lines = append(file.Lines, line)
+	}
+	sort.Strings(file.Lines)
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	return global.WriteFile(l.file, data)
+}
+
+//Returns the file the log is saved to, or an empty string if it is not saved
+func (l *ReadLog) GetFile() string {
+	return l.file
+}
+
+//Returns the number of lines read
+func (l *ReadLog) GetCount() int {
+	return len(l.lines)
+}
+
+//Forgets every line read
+func (l *ReadLog) Clear() {
+	l.lines = make(map[string]bool)
+}
+
+func (l *ReadLog) isRead(script *Script, st statement) bool {
+	return l.lines[readKey(script, st)]
+}
+
+func (l *ReadLog) markRead(script *Script, st statement) {
+	l.lines[readKey(script, st)] = true
+}
+
+//Returns the key of a line, a hash of its script, speaker and text
+func readKey(script *Script, st statement) string {
+	h := fnv.New64a()
+	h.Write([]byte(script.name + "\x00" + st.name + "\x00" + st.text))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
@@ -0,0 +1,491 @@
+package story
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koinuri/game-project/main/audio"
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Most statements run in one go without stopping for the player, to catch scripts jumping around in a loop
+const maxSteps = 100000
+
+type runnerState int
+
+const (
+	stateIdle runnerState = iota
+	stateSaying
+	stateChoosing
+	stateWaiting
+	stateFinished
+)
+
+//Interpreter running a script, showing its lines in a text box and its characters on a stage.  In auto mode lines go on by
+//themselves once they had time to be read, and in skip mode the lines the read log has are passed over until one that is
+//not.
+type Runner struct {
+	script    *Script
+	stage     *Stage
+	box       *TextBox
+	pc        int
+	calls     []int
+	vars      map[string]interface{}
+	state     runnerState
+	choices   []choice
+	wait      float64
+	line      statement
+	backlog   *Backlog
+	readLog   *ReadLog
+	mixer     *audio.Mixer
+	voice     *audio.Voice
+	auto      bool
+	autoSpeed float64
+	autoDelay float64
+	autoWait  float64
+	skip      bool
+}
+
+//Creates a runner for the script, which starts running with Start.
+//	*InitRunner(script, stage, box)
+//Where:
+//	stage is the stage the characters of the script are shown on.  Runners can share a stage to run several scripts with
+//	the same characters.
+//	box is the text box the lines are typed in.
+//The runner keeps the last 200 lines in a backlog and tracks the lines read in a log that is not saved until one loaded
+//with LoadReadLog is set with SetReadLog.
+func InitRunner(script *Script, stage *Stage, box *TextBox) *Runner {
+	if script == nil || stage == nil || box == nil {
+		panic("Invalid argument.  The script, stage and text box cannot be nil")
+	}
+
+	stage.define(script)
+	return &Runner{
+		script:    script,
+		stage:     stage,
+		box:       box,
+		calls:     make([]int, 0),
+		vars:      make(map[string]interface{}),
+		choices:   make([]choice, 0),
+		backlog:   InitBacklog(),
+		readLog:   InitReadLog(),
+		autoSpeed: 20,
+		autoDelay: 1,
+	}
+}
+
+//Starts running the script from its first line or from the label given, keeping the variables.
+//	*Start()
+//	*Start(label)
+func (r *Runner) Start(label ...string) {
+	switch len(label) {
+	case 0:
+		r.pc = 0
+	case 1:
+		pc, succ := r.script.labels[label[0]]
+		if !succ {
+			panic(fmt.Sprintf("Invalid argument.  Could not find any label with the name \"%v\" in \"%v\".", label[0], r.script.name))
+		}
+		r.pc = pc
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one label, found %v.", len(label)))
+	}
+
+	r.calls = r.calls[:0]
+	r.choices = r.choices[:0]
+	r.stopVoice()
+	r.box.Clear()
+	r.run()
+}
+
+//Types the line and plays the stage, going on with the script once a wait is over, or once the line had time to be read
+//in auto mode.  Skip mode goes on by one line every update.
+func (r *Runner) Update(dt float64) {
+	r.stage.Update(dt)
+	r.box.Update(dt)
+
+	if r.skip {
+		r.skipLine()
+		return
+	}
+
+	if r.state == stateSaying && r.auto && !r.box.IsTyping() && !r.isVoicePlaying() {
+		r.autoWait += dt
+		if r.autoWait >= r.autoDelay+float64(len([]rune(r.box.GetText())))/r.autoSpeed {
+			r.next()
+		}
+		return
+	}
+
+	if r.state != stateWaiting {
+		return
+	}
+	if r.wait >= 0 {
+		r.wait -= dt
+		if r.wait > 0 {
+			return
+		}
+	} else if r.stage.IsAnimating() {
+		return
+	}
+	r.run()
+}
+
+//Answers the player clicking or pressing a key: shows the rest of the line being typed, or goes on to the next line.
+//A wait is cut short, and nothing happens while a choice is to be made.
+func (r *Runner) Advance() {
+	switch r.state {
+	case stateSaying:
+		if r.box.IsTyping() {
+			r.box.Finish()
+			return
+		}
+		r.next()
+	case stateWaiting:
+		r.stage.Finish()
+		r.run()
+	}
+}
+
+//Goes past the line being said
+func (r *Runner) next() {
+	r.readLog.markRead(r.script, r.line)
+	r.stopVoice()
+	r.run()
+}
+
+//Passes over the line being said if it was read before, or leaves skip mode if it was not
+func (r *Runner) skipLine() {
+	switch r.state {
+	case stateSaying:
+		if !r.readLog.isRead(r.script, r.line) {
+			r.skip = false
+			r.PlayVoice(r.line.voice)
+			return
+		}
+		r.box.Finish()
+		r.next()
+	case stateWaiting:
+		r.stage.Finish()
+		r.run()
+	default:
+		//choices are for the player to make, so skipping stops at them
+		r.skip = false
+	}
+}
+
+//Sets whether lines go on by themselves once they are typed, the voice is over and they had time to be read
+func (r *Runner) SetAuto(auto bool) {
+	r.auto = auto
+	r.autoWait = 0
+}
+
+func (r *Runner) IsAuto() bool {
+	return r.auto
+}
+
+//Sets how fast lines are read in auto mode, which waits the delay then a second for every number of letters given.  It
+//is defaulted to 20 letters a second after a delay of 1 second.
+func (r *Runner) SetAutoSpeed(lettersPerSecond float64, delay float64) {
+	if lettersPerSecond <= 0 || delay < 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a speed above 0 and a delay of 0 or more, found %v and %v", lettersPerSecond, delay))
+	}
+	r.autoSpeed = lettersPerSecond
+	r.autoDelay = delay
+}
+
+func (r *Runner) GetAutoSpeed() (float64, float64) {
+	return r.autoSpeed, r.autoDelay
+}
+
+//Sets whether lines read before are passed over.  Skip mode stops by itself at the first line not read and at choices.
+func (r *Runner) SetSkip(skip bool) {
+	r.skip = skip
+}
+
+func (r *Runner) IsSkipping() bool {
+	return r.skip
+}
+
+//Returns true if the line being said was read before, so skip mode can pass over it
+func (r *Runner) IsLineRead() bool {
+	return r.state == stateSaying && r.readLog.isRead(r.script, r.line)
+}
+
+//Sets the log the lines read are tracked in, usually one loaded with LoadReadLog and shared by every runner of the game
+func (r *Runner) SetReadLog(log *ReadLog) {
+	if log == nil {
+		panic("Invalid argument.  The read log cannot be nil")
+	}
+	r.readLog = log
+}
+
+func (r *Runner) GetReadLog() *ReadLog {
+	return r.readLog
+}
+
+//Sets the backlog the lines said are added to, such as to share one between runners
+func (r *Runner) SetBacklog(backlog *Backlog) {
+	if backlog == nil {
+		panic("Invalid argument.  The backlog cannot be nil")
+	}
+	r.backlog = backlog
+}
+
+func (r *Runner) GetBacklog() *Backlog {
+	return r.backlog
+}
+
+//Sets the mixer the voices of the lines are played with on the voice bus.  Voices are not played without one.
+func (r *Runner) SetMixer(mixer *audio.Mixer) {
+	r.stopVoice()
+	r.mixer = mixer
+}
+
+func (r *Runner) GetMixer() *audio.Mixer {
+	return r.mixer
+}
+
+//Plays a voice, stopping the one playing, such as to replay the voice of a line from the backlog
+func (r *Runner) PlayVoice(dir string) {
+	r.stopVoice()
+	if r.mixer != nil && dir != "" {
+		r.voice = r.mixer.Play(audio.InitSound(dir), audio.BusVoice)
+	}
+}
+
+func (r *Runner) stopVoice() {
+	if r.voice != nil {
+		r.voice.Stop()
+		r.voice = nil
+	}
+}
+
+func (r *Runner) isVoicePlaying() bool {
+	return r.voice != nil && !r.voice.IsDone()
+}
+
+//Returns the texts of the choices the player is to make one of, or nothing when there is no choice to make
+func (r *Runner) GetChoices() []string {
+	texts := make([]string, len(r.choices))
+	for i, c := range r.choices {
+		texts[i] = r.interpolate(c.text)
+	}
+	return texts
+}
+
+//Makes the choice at the index of GetChoices and goes on with the script
+func (r *Runner) Choose(index int) {
+	if r.state != stateChoosing {
+		panic("Invalid state.  There is no choice to make")
+	}
+	if index < 0 || index >= len(r.choices) {
+		panic(fmt.Sprintf("Invalid argument.  Expected a choice from 0 to %v, found %v", len(r.choices)-1, index))
+	}
+
+	c := r.choices[index]
+	r.choices = r.choices[:0]
+	if c.target != "" {
+		r.pc = r.script.labels[c.target]
+	}
+	r.run()
+}
+
+//Returns true while the runner waits for the player to go on from a line
+func (r *Runner) IsSaying() bool {
+	return r.state == stateSaying
+}
+
+//Returns true while the runner waits for the player to make a choice
+func (r *Runner) IsChoosing() bool {
+	return r.state == stateChoosing
+}
+
+//Returns true once the script has ended
+func (r *Runner) IsFinished() bool {
+	return r.state == stateFinished
+}
+
+//Sets a variable of the script to a number, bool or string
+func (r *Runner) SetVariable(name string, value interface{}) {
+	switch v := value.(type) {
+	case int:
+		value = float64(v)
+	case float32:
+		value = float64(v)
+	case float64, bool, string:
+	default:
+		panic(fmt.Sprintf("Invalid argument.  Expected a number, bool or string, found %T", value))
+	}
+	r.vars[name] = value
+}
+
+//Returns the value of a variable of the script, which is a float64, bool or string, or 0 if it was never set
+func (r *Runner) GetVariable(name string) interface{} {
+	if v, succ := r.vars[name]; succ {
+		return v
+	}
+	return 0.0
+}
+
+func (r *Runner) GetScript() *Script {
+	return r.script
+}
+
+func (r *Runner) GetStage() *Stage {
+	return r.stage
+}
+
+func (r *Runner) GetTextBox() *TextBox {
+	return r.box
+}
+
+//Returns the artists of the stage with the text box in front of them
+func (r *Runner) GetArtists() []framework.Artist {
+	return append(r.stage.GetArtists(), r.box.GetArtists()...)
+}
+
+//Runs statements until one waits for the player or for time to pass
+func (r *Runner) run() {
+	r.state = stateIdle
+
+	for steps := 0; r.state == stateIdle; steps++ {
+		if r.pc >= len(r.script.statements) {
+			r.state = stateFinished
+			return
+		}
+		st := r.script.statements[r.pc]
+		if steps == maxSteps {
+			r.fail(st, fmt.Errorf("the script ran %v statements without stopping", maxSteps))
+		}
+
+		r.pc++
+		r.exec(st)
+	}
+}
+
+func (r *Runner) exec(st statement) {
+	switch st.command {
+	case commandSay:
+		r.say(st)
+	case commandShow:
+		x := -1.0
+		if st.position {
+			x = st.x
+		}
+		r.stage.Show(st.name, st.text, x, st.seconds)
+	case commandHide:
+		r.stage.Hide(st.name, st.seconds)
+	case commandMove:
+		r.stage.Move(st.name, st.x, st.seconds)
+	case commandSet:
+		r.set(st)
+	case commandJump, commandCall:
+		if st.expr != nil && !truthy(r.eval(st, st.expr)) {
+			return
+		}
+		if st.command == commandCall {
+			r.calls = append(r.calls, r.pc)
+		}
+		r.pc = r.script.labels[st.target]
+	case commandReturn:
+		if len(r.calls) == 0 {
+			r.state = stateFinished
+			return
+		}
+		r.pc = r.calls[len(r.calls)-1]
+		r.calls = r.calls[:len(r.calls)-1]
+	case commandMenu:
+		r.choices = r.choices[:0]
+		for _, c := range st.choices {
+			st.line = c.line
+			if c.cond == nil || truthy(r.eval(st, c.cond)) {
+				r.choices = append(r.choices, c)
+			}
+		}
+		//a menu with every choice hidden is passed over
+		if len(r.choices) > 0 {
+			r.state = stateChoosing
+		}
+	case commandWait:
+		r.wait = st.seconds
+		r.state = stateWaiting
+	case commandEnd:
+		r.state = stateFinished
+	}
+}
+
+func (r *Runner) say(st statement) {
+	e := BacklogEntry{Color: [3]float64{1, 1, 1}, Text: r.interpolate(st.text), Voice: st.voice}
+	if st.name != "" {
+		c := r.stage.character(st.name)
+		e.Name = c.GetName()
+		e.Color[0], e.Color[1], e.Color[2] = c.GetColor()
+	}
+
+	r.line = st
+	r.state = stateSaying
+	r.autoWait = 0
+	r.backlog.Add(e)
+	r.box.Say(e.Name, e.Color[0], e.Color[1], e.Color[2], e.Text)
+
+	//voices of lines skipped over would only be cut off right away
+	if !r.skip {
+		r.PlayVoice(st.voice)
+	}
+}
+
+func (r *Runner) set(st statement) {
+	value := r.eval(st, st.expr)
+	if st.op != "=" {
+		var err error
+		value, err = binary{st.op[:1], literal{r.GetVariable(st.name)}, literal{value}}.eval(r.vars)
+		if err != nil {
+			r.fail(st, err)
+		}
+	}
+	r.vars[st.name] = value
+}
+
+func (r *Runner) eval(st statement, e expression) interface{} {
+	v, err := e.eval(r.vars)
+	if err != nil {
+		r.fail(st, err)
+	}
+	return v
+}
+
+func (r *Runner) fail(st statement, err error) {
+	panic(fmt.Sprintf("Invalid script \"%v\".  Line %v: %v", r.script.name, st.line, err))
+}
+
+//Replaces every {variable} in the text with the value of the variable
+func (r *Runner) interpolate(text string) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(text, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start:], "}")
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name := text[start+1 : end]
+		if isName(name) {
+			b.WriteString(text[:start])
+			b.WriteString(format(r.GetVariable(name)))
+		} else {
+			b.WriteString(text[:end+1])
+		}
+		text = text[end+1:]
+	}
+	b.WriteString(text)
+	return b.String()
+}
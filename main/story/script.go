@@ -0,0 +1,519 @@
+package story
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/koinuri/game-project/main/asset"
+)
+
+type command int
+
+const (
+	commandSay command = iota
+	commandShow
+	commandHide
+	commandMove
+	commandSet
+	commandJump
+	commandCall
+	commandReturn
+	commandMenu
+	commandWait
+	commandEnd
+)
+
+//Named positions on the stage, as fractions of its width
+var positions = map[string]float64{
+	"left":   .25,
+	"center": .5,
+	"right":  .75,
+}
+
+//One line of a script, or every choice of a menu
+type statement struct {
+	command command
+	line    int
+	//speaker of a line, or the character shown, hidden or moved
+	name string
+	//text of a line, or the image a character is shown with
+	text string
+	//sound of the line being spoken
+	voice string
+	//label jumped to or called
+	target string
+	//operator of an assignment
+	op string
+	//value of an assignment, or condition a jump or call only happens under
+	expr expression
+	//position a character is shown at or moved to, if set
+	x        float64
+	position bool
+	//seconds the command takes, where negative waits for the stage
+	seconds float64
+	choices []choice
+}
+
+type choice struct {
+	line   int
+	text   string
+	cond   expression
+	target string
+}
+
+//Someone who can speak and be shown, as defined by the script
+type definition struct {
+	name  string
+	image string
+	color [3]float64
+}
+
+//Parsed script, ready to be run by a Runner
+type Script struct {
+	name       string
+	statements []statement
+	labels     map[string]int
+	characters map[string]definition
+}
+
+//Loads a script, panicking with the line of the first error found.
+//	# comments start with a hash
+//	character akane "Akane" kotonoha-7.png #f0a0a0
+//
+//	label start
+//	show akane at left fade 0.5
+//	akane "Hello, {player}." voice akane/hello.ogg
+//	"Narration is a line without a speaker."
+//	move akane right 1
+//	wait
+//	$ met = true
+//	$ affection += 1
+//	* "Wave back" -> wave
+//	* "Say something nice" if affection > 2 -> compliment
+//	* "Leave"
+//	hide akane
+//	end
+//
+//	label wave
+//	show akane smile.png
+//	if met and affection >= 1 jump start
+//	call aside
+//	end
+//
+//	label aside
+//	"Calls come back with return."
+//	return
+//Where:
+//	character defines the name shown for the speaker, the image the character is shown with and the color of the name.
+//	show places a character on the stage, optionally with another image, at left, center, right or a fraction of the
+//	width of the stage, fading in over the seconds given.
+//	hide takes a character off the stage, optionally fading out over the seconds given.
+//	move slides a character to a position over the seconds given.
+//	wait pauses for the seconds given, or until the characters stop moving.
+//	$ sets a variable with =, +=, -=, *= or /= to an expression of numbers, strings, true, false and variables, joined by
+//	+ - * / % == != < <= > >= and or not and parentheses.  Variables never set are 0.
+//	* lines next to each other are the choices of one menu, each optionally shown only when its condition holds, and
+//	jumping to a label or going on after the menu.
+//	voice after a line is the sound of it being spoken, played on the voice bus.
+//	{variable} in lines is replaced with the value of the variable.
+//The script starts at its first line, or at the label given to Runner.Start.
+func LoadScript(dir string) *Script {
+	data, err := asset.ReadFile(dir)
+	if err == nil {
+		var s *Script
+		s, err = parseScript(dir, string(data))
+		if err == nil {
+			return s
+		}
+	}
+	panic(fmt.Sprintf("Could not load the file %v.\n%v", asset.Describe(dir), err))
+}
+
+func parseScript(name string, source string) (*Script, error) {
+	s := &Script{
+		name:       name,
+		statements: make([]statement, 0),
+		labels:     make(map[string]int),
+		characters: make(map[string]definition),
+	}
+
+	for i, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if err := s.parseLine(i+1, line); err != nil {
+			return nil, fmt.Errorf("line %v: %v", i+1, err)
+		}
+	}
+
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Script) parseLine(number int, line string) error {
+	st := statement{line: number}
+
+	switch {
+	case line[0] == '"':
+		return s.parseSay(st, line)
+	case line[0] == '$':
+		return s.parseSet(st, strings.TrimSpace(line[1:]))
+	case line[0] == '*':
+		return s.parseChoice(st, strings.TrimSpace(line[1:]))
+	}
+
+	word, rest := firstWord(line)
+	switch word {
+	case "character":
+		return s.parseCharacter(rest)
+	case "label":
+		if !isName(rest) {
+			return fmt.Errorf("invalid label %q", rest)
+		}
+		if _, succ := s.labels[rest]; succ {
+			return fmt.Errorf("the label %q already exists", rest)
+		}
+		s.labels[rest] = len(s.statements)
+		return nil
+	case "jump", "call":
+		if !isName(rest) {
+			return fmt.Errorf("invalid label %q", rest)
+		}
+		st.command = commandJump
+		if word == "call" {
+			st.command = commandCall
+		}
+		st.target = rest
+	case "return", "end":
+		if rest != "" {
+			return fmt.Errorf("unexpected %q after %v", rest, word)
+		}
+		st.command = commandReturn
+		if word == "end" {
+			st.command = commandEnd
+		}
+	case "if":
+		return s.parseIf(st, rest)
+	case "show", "hide", "move":
+		return s.parseStage(st, word, strings.Fields(rest))
+	case "wait":
+		st.command = commandWait
+		st.seconds = -1
+		if rest != "" {
+			var err error
+			if st.seconds, err = seconds(rest); err != nil {
+				return err
+			}
+		}
+	default:
+		if !isName(word) || rest == "" || rest[0] != '"' {
+			return fmt.Errorf("unknown command %q", word)
+		}
+		st.name = word
+		return s.parseSay(st, rest)
+	}
+
+	s.statements = append(s.statements, st)
+	return nil
+}
+
+//Reads "<text>" [voice <file>], after the speaker if there is one
+func (s *Script) parseSay(st statement, rest string) error {
+	text, rest, err := quoted(rest)
+	if err != nil {
+		return err
+	}
+
+	if rest != "" {
+		word, voice := firstWord(rest)
+		if word != "voice" || voice == "" || strings.ContainsAny(voice, " \t") {
+			return fmt.Errorf("unexpected %q after the line", rest)
+		}
+		st.voice = voice
+	}
+
+	st.command = commandSay
+	st.text = text
+	s.statements = append(s.statements, st)
+	return nil
+}
+
+//Reads character <id> "<name>" [image] [#rrggbb]
+func (s *Script) parseCharacter(rest string) error {
+	id, rest := firstWord(rest)
+	if !isName(id) {
+		return fmt.Errorf("invalid character %q", id)
+	}
+	if _, succ := s.characters[id]; succ {
+		return fmt.Errorf("the character %q already exists", id)
+	}
+
+	name, rest, err := quoted(rest)
+	if err != nil {
+		return err
+	}
+
+	d := definition{name: name, color: [3]float64{1, 1, 1}}
+	for _, field := range strings.Fields(rest) {
+		if field[0] != '#' {
+			d.image = field
+			continue
+		}
+
+		rgb, err := strconv.ParseUint(field[1:], 16, 32)
+		if err != nil || len(field) != 7 {
+			return fmt.Errorf("invalid color %q.  Expected #rrggbb", field)
+		}
+		d.color = [3]float64{float64(rgb>>16&0xff) / 255, float64(rgb>>8&0xff) / 255, float64(rgb&0xff) / 255}
+	}
+
+	s.characters[id] = d
+	return nil
+}
+
+//Reads $ <variable> <op> <expression>
+func (s *Script) parseSet(st statement, rest string) error {
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+	if end < 0 {
+		return fmt.Errorf("expected an assignment")
+	}
+
+	st.name = rest[:end]
+	if !isName(st.name) {
+		return fmt.Errorf("invalid variable %q", st.name)
+	}
+
+	rest = strings.TrimSpace(rest[end:])
+	for _, op := range []string{"+=", "-=", "*=", "/=", "="} {
+		if strings.HasPrefix(rest, op) {
+			st.op = op
+			break
+		}
+	}
+	if st.op == "" {
+		return fmt.Errorf("expected =, +=, -=, *= or /= after %v", st.name)
+	}
+
+	e, err := parseExpression(rest[len(st.op):])
+	if err != nil {
+		return err
+	}
+
+	st.command = commandSet
+	st.expr = e
+	s.statements = append(s.statements, st)
+	return nil
+}
+
+//Reads * "<text>" [if <condition>] [-> <label>], adding it to the menu of the line before if there is one
+func (s *Script) parseChoice(st statement, rest string) error {
+	text, rest, err := quoted(rest)
+	if err != nil {
+		return err
+	}
+	c := choice{line: st.line, text: text}
+
+	if i := strings.LastIndex(rest, "->"); i >= 0 {
+		c.target = strings.TrimSpace(rest[i+2:])
+		if !isName(c.target) {
+			return fmt.Errorf("invalid label %q", c.target)
+		}
+		rest = strings.TrimSpace(rest[:i])
+	}
+
+	if rest != "" {
+		word, cond := firstWord(rest)
+		if word != "if" {
+			return fmt.Errorf("unexpected %q after the choice", rest)
+		}
+		if c.cond, err = parseExpression(cond); err != nil {
+			return err
+		}
+	}
+
+	last := len(s.statements) - 1
+	if last >= 0 && s.statements[last].command == commandMenu && s.statements[last].line == st.line-1 {
+		s.statements[last].choices = append(s.statements[last].choices, c)
+		s.statements[last].line = st.line
+		return nil
+	}
+
+	st.command = commandMenu
+	st.choices = []choice{c}
+	s.statements = append(s.statements, st)
+	return nil
+}
+
+//Reads if <condition> jump <label> or if <condition> call <label>
+func (s *Script) parseIf(st statement, rest string) error {
+	i := strings.LastIndex(rest, " jump ")
+	st.command = commandJump
+	if c := strings.LastIndex(rest, " call "); c > i {
+		i = c
+		st.command = commandCall
+	}
+	if i < 0 {
+		return fmt.Errorf("expected jump or call after the condition")
+	}
+
+	st.target = strings.TrimSpace(rest[i+6:])
+	if !isName(st.target) {
+		return fmt.Errorf("invalid label %q", st.target)
+	}
+
+	e, err := parseExpression(rest[:i])
+	if err != nil {
+		return err
+	}
+	st.expr = e
+
+	s.statements = append(s.statements, st)
+	return nil
+}
+
+//Reads show <id> [image] [at <position>] [fade <seconds>], hide <id> [fade <seconds>] and move <id> <position> [seconds]
+func (s *Script) parseStage(st statement, word string, fields []string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("expected a character after %v", word)
+	}
+	st.name = fields[0]
+	fields = fields[1:]
+
+	switch word {
+	case "show":
+		st.command = commandShow
+		if len(fields) > 0 && fields[0] != "at" && fields[0] != "fade" {
+			st.text = fields[0]
+			fields = fields[1:]
+		}
+		if len(fields) > 1 && fields[0] == "at" {
+			x, err := position(fields[1])
+			if err != nil {
+				return err
+			}
+			st.x, st.position = x, true
+			fields = fields[2:]
+		}
+	case "hide":
+		st.command = commandHide
+	case "move":
+		st.command = commandMove
+		if len(fields) == 0 {
+			return fmt.Errorf("expected a position to move %v to", st.name)
+		}
+		x, err := position(fields[0])
+		if err != nil {
+			return err
+		}
+		st.x, st.position = x, true
+		fields = fields[1:]
+		if len(fields) == 1 {
+			if st.seconds, err = seconds(fields[0]); err != nil {
+				return err
+			}
+			fields = nil
+		}
+	}
+
+	if len(fields) == 2 && fields[0] == "fade" {
+		var err error
+		if st.seconds, err = seconds(fields[1]); err != nil {
+			return err
+		}
+		fields = nil
+	}
+	if len(fields) > 0 {
+		return fmt.Errorf("unexpected %q after %v", strings.Join(fields, " "), word)
+	}
+
+	s.statements = append(s.statements, st)
+	return nil
+}
+
+func seconds(field string) (float64, error) {
+	seconds, err := strconv.ParseFloat(field, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid number of seconds %q", field)
+	}
+	return seconds, nil
+}
+
+func position(field string) (float64, error) {
+	if x, succ := positions[field]; succ {
+		return x, nil
+	}
+	x, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid position %q.  Expected left, center, right or a fraction of the stage", field)
+	}
+	return x, nil
+}
+
+//Makes sure every label and character used exists
+func (s *Script) check() error {
+	for _, st := range s.statements {
+		switch st.command {
+		case commandJump, commandCall:
+			if _, succ := s.labels[st.target]; !succ {
+				return fmt.Errorf("line %v: unknown label %q", st.line, st.target)
+			}
+		case commandMenu:
+			for _, c := range st.choices {
+				if _, succ := s.labels[c.target]; c.target != "" && !succ {
+					return fmt.Errorf("line %v: unknown label %q", c.line, c.target)
+				}
+			}
+		case commandSay, commandShow, commandHide, commandMove:
+			if _, succ := s.characters[st.name]; st.name != "" && !succ {
+				return fmt.Errorf("line %v: unknown character %q", st.line, st.name)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Script) GetName() string {
+	return s.name
+}
+
+//Returns true if the script has the label
+func (s *Script) HasLabel(label string) bool {
+	_, succ := s.labels[label]
+	return succ
+}
+
+//Splits the first word off the line
+func firstWord(line string) (string, string) {
+	i := strings.IndexFunc(line, unicode.IsSpace)
+	if i < 0 {
+		return line, ""
+	}
+	return line[:i], strings.TrimSpace(line[i:])
+}
+
+//Reads the quoted string at the start of the line and returns it with the rest of the line
+func quoted(line string) (string, string, error) {
+	if line == "" || line[0] != '"' {
+		return "", "", fmt.Errorf("expected a quoted text")
+	}
+
+	runes := []rune(line)
+	end, err := closingQuote(runes, 0)
+	if err != nil {
+		return "", "", err
+	}
+
+	text, err := strconv.Unquote(string(runes[:end+1]))
+	if err != nil {
+		return "", "", fmt.Errorf("invalid text %v", string(runes[:end+1]))
+	}
+	return text, strings.TrimSpace(string(runes[end+1:])), nil
+}
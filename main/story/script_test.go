@@ -0,0 +1,185 @@
+package story
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseIf(t *testing.T) {
+	cases := []struct {
+		name    string
+		line    string
+		command command
+		target  string
+		vars    map[string]interface{}
+		want    bool
+	}{
+		{
+			name: "jump", line: "if met jump a",
+			command: commandJump, target: "a", vars: map[string]interface{}{"met": true}, want: true,
+		},
+		{
+			name: "call", line: "if affection > 2 call b",
+			command: commandCall, target: "b", vars: map[string]interface{}{"affection": 2.0}, want: false,
+		},
+		{
+			name: "jump in a string of the condition", line: `if name == "a jump b" call b`,
+			command: commandCall, target: "b", vars: map[string]interface{}{"name": "a jump b"}, want: true,
+		},
+		{
+			name: "call in a string of the condition", line: `if name != "a call b" jump a`,
+			command: commandJump, target: "a", vars: map[string]interface{}{}, want: true,
+		},
+		{
+			name: "aliases", line: "if met and not angry jump a",
+			command: commandJump, target: "a", vars: map[string]interface{}{"met": true, "angry": true}, want: false,
+		},
+	}
+
+	for _, c := range cases {
+		s, err := parseScript("test", c.line+"\nlabel a\nlabel b\nend")
+		if err != nil {
+			t.Errorf("%v: parsing failed: %v", c.name, err)
+			continue
+		}
+
+		st := s.statements[0]
+		if st.command != c.command || st.target != c.target {
+			t.Errorf("%v: got command %v to %q, want %v to %q", c.name, st.command, st.target, c.command, c.target)
+			continue
+		}
+		got, err := st.expr.eval(c.vars)
+		if err != nil || got != c.want {
+			t.Errorf("%v: the condition is %v (%v), want %v", c.name, got, err, c.want)
+		}
+	}
+}
+
+func TestParseMenu(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		//number of choices of each menu, in order
+		want []int
+	}{
+		{
+			name:   "one menu",
+			source: "* \"a\" -> x\n* \"b\"\n* \"c\" if met -> x",
+			want:   []int{3},
+		},
+		{
+			name:   "comment between choices",
+			source: "* \"a\"\n# comment\n* \"b\"",
+			want:   []int{1, 1},
+		},
+		{
+			name:   "blank line between choices",
+			source: "* \"a\"\n\n* \"b\"",
+			want:   []int{1, 1},
+		},
+		{
+			name:   "line between choices",
+			source: "* \"a\"\n\"text\"\n* \"b\"\n* \"c\"",
+			want:   []int{1, 2},
+		},
+	}
+
+	for _, c := range cases {
+		s, err := parseScript("test", c.source+"\nlabel x\nend")
+		if err != nil {
+			t.Errorf("%v: parsing failed: %v", c.name, err)
+			continue
+		}
+
+		got := make([]int, 0)
+		for _, st := range s.statements {
+			if st.command == commandMenu {
+				got = append(got, len(st.choices))
+			}
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("%v: got menus of %v choices, want %v", c.name, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%v: got menus of %v choices, want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseChoice(t *testing.T) {
+	s, err := parseScript("test", "* \"Go -> there\" if met -> x\n* \"Stay\"\nlabel x\nend")
+	if err != nil {
+		t.Fatalf("parsing failed: %v", err)
+	}
+
+	choices := s.statements[0].choices
+	if choices[0].text != "Go -> there" || choices[0].target != "x" || choices[0].cond == nil {
+		t.Errorf("got the first choice %+v, want \"Go -> there\" to x under a condition", choices[0])
+	}
+	if choices[1].text != "Stay" || choices[1].target != "" || choices[1].cond != nil {
+		t.Errorf("got the second choice %+v, want \"Stay\" going on after the menu", choices[1])
+	}
+	//the menu takes the line of its last choice, so the next choice is only grouped when it follows it
+	if s.statements[0].line != 2 {
+		t.Errorf("got the menu on line %v, want 2", s.statements[0].line)
+	}
+}
+
+func TestParseScriptErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "unknown command", source: "dance", want: `line 1: unknown command "dance"`},
+		{name: "if without jump", source: "if met end", want: "line 1: expected jump or call"},
+		{name: "if with invalid label", source: "if met jump 1a", want: `line 1: invalid label "1a"`},
+		{name: "if with invalid condition", source: "if met and jump a\nlabel a", want: "line 1: unexpected end"},
+		{name: "unknown label", source: "\njump nowhere", want: `line 2: unknown label "nowhere"`},
+		{name: "unknown choice label", source: "* \"a\" -> nowhere", want: `line 1: unknown label "nowhere"`},
+		{name: "choice without if", source: "* \"a\" when met", want: "line 1: unexpected"},
+		{name: "duplicate label", source: "label a\nlabel a", want: `line 2: the label "a" already exists`},
+		{name: "unknown character", source: "akane \"Hi\"", want: `line 1: unknown character "akane"`},
+		{name: "invalid color", source: "character akane \"Akane\" #fff", want: `line 1: invalid color "#fff"`},
+		{name: "invalid assignment", source: "$ a == 1", want: "line 1: unexpected \"=\""},
+		{name: "unclosed line", source: "\"Hello", want: "line 1: unclosed string"},
+		{name: "text after a line", source: "\"Hello\" loudly", want: "line 1: unexpected \"loudly\""},
+		{name: "invalid position", source: "character a \"A\"\nshow a at top", want: `line 2: invalid position "top"`},
+		{name: "negative seconds", source: "wait -1", want: `line 1: invalid number of seconds "-1"`},
+	}
+
+	for _, c := range cases {
+		if _, err := parseScript("test", c.source); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%v: parsing gave %v, want an error with %q", c.name, err, c.want)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	r := &Runner{vars: map[string]interface{}{"player": "Mio", "affection": 3.0, "met": true}}
+
+	cases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "no variable", text: "Hello.", want: "Hello."},
+		{name: "string", text: "Hello, {player}.", want: "Hello, Mio."},
+		{name: "whole number", text: "{affection} points", want: "3 points"},
+		{name: "bool", text: "{met}", want: "true"},
+		{name: "unset", text: "{missing}", want: "0"},
+		{name: "several", text: "{player}{player} {affection}", want: "MioMio 3"},
+		{name: "not a name", text: "{a b} and {player}", want: "{a b} and Mio"},
+		{name: "unclosed", text: "{player", want: "{player"},
+	}
+
+	for _, c := range cases {
+		if got := r.interpolate(c.text); got != c.want {
+			t.Errorf("%v: %q became %q, want %q", c.name, c.text, got, c.want)
+		}
+	}
+}
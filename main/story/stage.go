@@ -0,0 +1,303 @@
+package story
+
+import (
+	"fmt"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Seconds long enough for any animation of the stage to finish
+const finishSeconds = 1e6
+
+//Characters standing on a canvas, drawn in the order they were shown
+type Stage struct {
+	canvas     framework.Canvas
+	characters map[string]*Character
+	shown      []*Character
+	animator   framework.Animator
+}
+
+//Someone defined by a script, shown on the stage as an object holding the image of the character
+type Character struct {
+	id    string
+	name  string
+	image string
+	color [3]float64
+	//objects made for every image the character has been shown with, so switching back does not load it again
+	poses map[string]*framework.Object
+	obj   *framework.Object
+	x     float64
+	fade  *framework.Tween
+	move  *framework.Tween
+	shown bool
+	//whether the character is fading out to be taken off the stage
+	hiding bool
+}
+
+//Creates an empty stage.
+//	*InitStage()
+//	*InitStage(canvas)
+//Where:
+//	canvas is the part of the window the characters stand in.  It is defaulted to the whole window.
+func InitStage(canvas ...framework.Canvas) *Stage {
+	s := &Stage{
+		canvas:     framework.InitCanvas(),
+		characters: make(map[string]*Character),
+		shown:      make([]*Character, 0),
+		animator:   framework.InitAnimator(),
+	}
+
+	switch len(canvas) {
+	case 0:
+	case 1:
+		s.canvas = canvas[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one canvas, found %v.", len(canvas)))
+	}
+	return s
+}
+
+//Adds the characters of the script that the stage does not have yet
+func (s *Stage) define(script *Script) {
+	for id, d := range script.characters {
+		if _, succ := s.characters[id]; succ {
+			continue
+		}
+		s.characters[id] = &Character{
+			id:    id,
+			name:  d.name,
+			image: d.image,
+			color: d.color,
+			poses: make(map[string]*framework.Object),
+			x:     .5,
+		}
+	}
+}
+
+//Returns the character with the id, or nil if no script run on the stage defines it
+func (s *Stage) GetCharacter(id string) *Character {
+	return s.characters[id]
+}
+
+//Returns the characters on the stage, from the back to the front
+func (s *Stage) GetShown() []*Character {
+	shown := make([]*Character, len(s.shown))
+	copy(shown, s.shown)
+	return shown
+}
+
+//Puts the character on the stage, or changes how it is shown if it is already there.
+//	*Show(id, image, x, fade)
+//Where:
+//	image is the image to show the character with.  An empty string keeps the current one.
+//	x is the fraction of the width of the stage the character stands at, or a negative number to keep the current one.
+//	fade is the seconds the character takes to fade in if it was not on the stage.
+func (s *Stage) Show(id string, image string, x float64, fade float64) {
+	c := s.character(id)
+
+	if image != "" {
+		c.image = image
+	}
+	if x >= 0 {
+		c.x = x
+		s.stop(c.move)
+		c.move = nil
+	}
+	s.pose(c)
+
+	if !c.shown {
+		c.shown = true
+		s.shown = append(s.shown, c)
+		c.obj.SetAlpha(1)
+		if fade > 0 {
+			c.obj.SetAlpha(0)
+		}
+	}
+
+	s.stop(c.fade)
+	c.fade = nil
+	c.hiding = false
+	if c.obj.GetAlpha() < 1 {
+		c.fade = framework.InitTween(c.obj, framework.TweenAlpha, fade, 1)
+		s.animator.Play(c.fade)
+	}
+}
+
+//Takes the character off the stage after it fades out over the seconds given
+func (s *Stage) Hide(id string, fade float64) {
+	c := s.character(id)
+	if !c.shown {
+		return
+	}
+
+	s.stop(c.fade)
+	c.fade = nil
+	if fade <= 0 {
+		s.remove(c)
+		return
+	}
+
+	c.hiding = true
+	c.fade = framework.InitTween(c.obj, framework.TweenAlpha, fade, 0)
+	c.fade.OnComplete(func() {
+		c.fade = nil
+		s.remove(c)
+	})
+	s.animator.Play(c.fade)
+}
+
+//Slides the character to the fraction of the width of the stage over the seconds given
+func (s *Stage) Move(id string, x float64, seconds float64) {
+	c := s.character(id)
+	c.x = x
+	s.stop(c.move)
+	c.move = nil
+	s.pose(c)
+	if seconds <= 0 || !c.shown {
+		return
+	}
+
+	px, py := s.point(x)
+
+	c.move = framework.InitTween(c.obj, framework.TweenPosition, seconds, px, py)
+	c.move.SetEasing(framework.QuadInOut)
+	c.move.OnComplete(func() {
+		c.move = nil
+	})
+	s.animator.Play(c.move)
+}
+
+//Takes every character off the stage
+func (s *Stage) Clear() {
+	for _, c := range s.shown {
+		s.stop(c.fade)
+		s.stop(c.move)
+		c.fade, c.move = nil, nil
+		c.shown = false
+		c.hiding = false
+	}
+	s.shown = s.shown[:0]
+}
+
+//Returns true while any character is fading or moving
+func (s *Stage) IsAnimating() bool {
+	return s.animator.IsPlaying()
+}
+
+//Finishes every fade and move at once
+func (s *Stage) Finish() {
+	for s.animator.IsPlaying() {
+		s.animator.Update(finishSeconds)
+	}
+}
+
+func (s *Stage) Update(dt float64) {
+	s.animator.Update(dt)
+}
+
+func (s *Stage) GetArtists() []framework.Artist {
+	artists := make([]framework.Artist, 0)
+	for _, c := range s.shown {
+		artists = append(artists, c.obj.GetArtists()...)
+	}
+	return artists
+}
+
+func (s *Stage) GetCanvas() framework.Canvas {
+	return s.canvas
+}
+
+func (s *Stage) character(id string) *Character {
+	c, succ := s.characters[id]
+	if !succ {
+		panic(fmt.Sprintf("Invalid argument.  Could not find any character with the id \"%v\".", id))
+	}
+	return c
+}
+
+//Returns the point at the bottom of the stage at the fraction of its width
+func (s *Stage) point(x float64) (float64, float64) {
+	left := float64(s.canvas.X - s.canvas.Width/2)
+	bottom := float64(s.canvas.Y - s.canvas.Height/2)
+	return left + float64(s.canvas.Width)*x, bottom
+}
+
+func (s *Stage) stop(t *framework.Tween) {
+	if t != nil {
+		s.animator.Stop(t)
+	}
+}
+
+func (s *Stage) remove(c *Character) {
+	c.shown = false
+	c.hiding = false
+	for i, n := range s.shown {
+		if n == c {
+			s.shown = append(s.shown[:i], s.shown[i+1:]...)
+			return
+		}
+	}
+}
+
+//Switches the character to the object showing its image, carrying over how faded the last one was.  Moves playing on
+//the last object are cut short to where they were going.
+func (s *Stage) pose(c *Character) {
+	obj, succ := c.poses[c.image]
+	if !succ {
+		o := framework.InitObject(s.canvas.Width/2, s.canvas.Height, framework.BottomCenter)
+		if c.image != "" {
+			sprite := o.CreateSprite("image", c.image, framework.BottomCenter)
+			sprite.SetAnchor(.5, 1)
+		}
+		obj = &o
+		c.poses[c.image] = obj
+	}
+
+	if c.obj != nil && c.obj != obj {
+		s.stop(c.fade)
+		s.stop(c.move)
+		c.fade, c.move = nil, nil
+		obj.SetAlpha(c.obj.GetAlpha())
+	}
+	if c.move == nil {
+		obj.Move(s.point(c.x))
+	}
+	c.obj = obj
+}
+
+func (c *Character) GetID() string {
+	return c.id
+}
+
+//Returns the name shown when the character speaks
+func (c *Character) GetName() string {
+	return c.name
+}
+
+func (c *Character) SetName(name string) {
+	c.name = name
+}
+
+//Returns the color the name of the character is shown in
+func (c *Character) GetColor() (float64, float64, float64) {
+	return c.color[0], c.color[1], c.color[2]
+}
+
+//Returns the image the character is shown with
+func (c *Character) GetImage() string {
+	return c.image
+}
+
+//Returns the fraction of the width of the stage the character stands at, or is moving to
+func (c *Character) GetX() float64 {
+	return c.x
+}
+
+func (c *Character) IsShown() bool {
+	return c.shown
+}
+
+//Returns the object showing the character, for effects beyond what scripts do.  It changes when the image does.
+func (c *Character) GetObject() *framework.Object {
+	return c.obj
+}
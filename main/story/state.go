@@ -0,0 +1,139 @@
+package story
+
+import (
+	"fmt"
+	"sort"
+)
+
+//Where a runner is in its script and what it has done, for saving the game.  It only holds numbers, bools and strings, so
+//it can be written as json.
+type State struct {
+	Script     string                 `json:"script"`
+	Position   Position               `json:"position"`
+	Calls      []Position             `json:"calls"`
+	Variables  map[string]interface{} `json:"variables"`
+	Characters []CharacterState       `json:"characters"`
+	Backlog    []BacklogEntry         `json:"backlog"`
+	Finished   bool                   `json:"finished"`
+}
+
+//Statement of a script, given both by the label before it and by its index.  The label is tried first, so saves made
+//before lines were added to other parts of the script still resume at the right line.
+type Position struct {
+	Label  string `json:"label"`
+	Offset int    `json:"offset"`
+	Index  int    `json:"index"`
+}
+
+//Character on the stage, from the back to the front
+type CharacterState struct {
+	ID    string  `json:"id"`
+	Image string  `json:"image"`
+	X     float64 `json:"x"`
+}
+
+//Returns the state of the runner.  A line or a choice is resumed by saying it or asking it again, and a wait is resumed
+//past its end.
+func (r *Runner) GetState() State {
+	pc := r.pc
+	if r.state == stateSaying || r.state == stateChoosing {
+		pc--
+	}
+
+	state := State{
+		Script:     r.script.name,
+		Position:   r.script.position(pc),
+		Calls:      make([]Position, len(r.calls)),
+		Variables:  make(map[string]interface{}),
+		Characters: make([]CharacterState, 0),
+		Backlog:    r.backlog.GetEntries(),
+		Finished:   r.state == stateFinished,
+	}
+	for i, call := range r.calls {
+		state.Calls[i] = r.script.position(call)
+	}
+	for name, v := range r.vars {
+		state.Variables[name] = v
+	}
+
+	//characters fading out are as good as gone, and moves as good as done
+	for _, c := range r.stage.shown {
+		if !c.hiding {
+			state.Characters = append(state.Characters, CharacterState{ID: c.id, Image: c.image, X: c.x})
+		}
+	}
+	return state
+}
+
+//Puts the runner and its stage back in the state given, which must be of the same script
+func (r *Runner) SetState(state State) {
+	if state.Script != r.script.name {
+		panic(fmt.Sprintf("Invalid argument.  Expected a state of the script \"%v\", found \"%v\"", r.script.name, state.Script))
+	}
+
+	r.vars = make(map[string]interface{})
+	for name, v := range state.Variables {
+		r.SetVariable(name, v)
+	}
+
+	r.calls = r.calls[:0]
+	for _, call := range state.Calls {
+		r.calls = append(r.calls, r.script.find(call))
+	}
+
+	r.stage.Clear()
+	for _, c := range state.Characters {
+		if _, succ := r.stage.characters[c.ID]; succ {
+			r.stage.Show(c.ID, c.Image, c.X, 0)
+		}
+	}
+
+	r.skip = false
+	r.choices = r.choices[:0]
+	r.stopVoice()
+	r.box.Clear()
+
+	if state.Finished {
+		r.state = stateFinished
+	} else {
+		r.pc = r.script.find(state.Position)
+		r.run()
+	}
+
+	//saying the line again added it to the backlog, which already has it
+	r.backlog.SetEntries(state.Backlog)
+}
+
+//Returns the position of the statement at the index, from the last label before it
+func (s *Script) position(index int) Position {
+	p := Position{Index: index}
+
+	names := make([]string, 0, len(s.labels))
+	for name := range s.labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	last := -1
+	for _, name := range names {
+		if at := s.labels[name]; at <= index && at > last {
+			last = at
+			p.Label = name
+		}
+	}
+	if last >= 0 {
+		p.Offset = index - last
+	}
+	return p
+}
+
+//Returns the index of the statement at the position
+func (s *Script) find(p Position) int {
+	if at, succ := s.labels[p.Label]; succ && at+p.Offset <= len(s.statements) {
+		return at + p.Offset
+	}
+	if p.Index < 0 || p.Index > len(s.statements) {
+		return len(s.statements)
+	}
+	return p.Index
+}
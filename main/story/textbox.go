@@ -0,0 +1,200 @@
+package story
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Box showing the lines of a script one letter at a time, with the name of the speaker above them
+type TextBox struct {
+	obj     framework.Object
+	canvas  framework.Canvas
+	frame   *framework.NineSlice
+	name    *framework.Text
+	body    *framework.Text
+	font    *framework.Font
+	padding float64
+	//the line broken where it wraps when fully shown, so words do not jump to the next line while they are typed
+	lines  []rune
+	shown  float64
+	speed  float64
+	hidden bool
+}
+
+//Creates a text box covering the canvas.
+//	*InitTextBox(canvas)
+//	*InitTextBox(canvas, font)
+//Where:
+//	canvas is the part of the window the box covers, usually the bottom of it.
+//	font is the font of the name and the lines.  It is defaulted to DefaultFont.
+//The box is a translucent rectangle until SetFrame is called, and types 40 letters a second.
+func InitTextBox(canvas framework.Canvas, font ...*framework.Font) *TextBox {
+	t := &TextBox{
+		frame:   framework.InitRectangle(0, 0),
+		font:    framework.DefaultFont(),
+		padding: 16,
+		lines:   make([]rune, 0),
+		speed:   40,
+	}
+
+	switch len(font) {
+	case 0:
+	case 1:
+		if font[0] == nil {
+			panic("Invalid argument.  The font cannot be nil")
+		}
+		t.font = font[0]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected at most one font, found %v.", len(font)))
+	}
+
+	t.frame.SetColor(0, 0, 0)
+	t.frame.SetAlpha(.75)
+	t.name = framework.InitText("", t.font)
+	t.body = framework.InitText("", t.font)
+	t.name.SetAnchor(0, 0)
+	t.body.SetAnchor(0, 0)
+
+	t.SetCanvas(canvas)
+	return t
+}
+
+//Moves and resizes the box to cover the canvas
+func (t *TextBox) SetCanvas(canvas framework.Canvas) {
+	t.canvas = canvas
+	t.build()
+}
+
+func (t *TextBox) GetCanvas() framework.Canvas {
+	return t.canvas
+}
+
+//Replaces the rectangle behind the text, such as with a nine-slice of the box in the art of the game
+func (t *TextBox) SetFrame(frame *framework.NineSlice) {
+	if frame == nil {
+		panic("Invalid argument.  The frame cannot be nil")
+	}
+	t.frame = frame
+	t.build()
+}
+
+func (t *TextBox) GetFrame() *framework.NineSlice {
+	return t.frame
+}
+
+//Sets the space between the sides of the box and the text
+func (t *TextBox) SetPadding(padding float64) {
+	t.padding = padding
+	t.build()
+}
+
+func (t *TextBox) GetPadding() float64 {
+	return t.padding
+}
+
+//Rebuilds the object holding the artists of the box
+func (t *TextBox) build() {
+	width := float64(t.canvas.Width)
+	height := float64(t.canvas.Height)
+
+	t.obj = framework.InitObject(t.canvas.Width, t.canvas.Height, framework.TopLeft)
+	t.obj.Move(float64(t.canvas.X)-width/2, float64(t.canvas.Y)+height/2)
+
+	t.frame.SetOrigin(framework.TopLeft)
+	t.frame.SetAnchor(0, 0)
+	t.frame.SetSize(width, height)
+	t.name.Move(t.padding, -t.padding)
+	t.body.Move(t.padding, -t.padding-t.font.GetLineHeight()*1.25)
+
+	t.obj.AddArtist("frame", t.frame)
+	t.obj.AddArtist("name", t.name)
+	t.obj.AddArtist("body", t.body)
+}
+
+//Starts typing a line said by the speaker, given by its name and color.  An empty name is narration.
+func (t *TextBox) Say(name string, r, g, b float64, text string) {
+	t.name.SetText(name)
+	t.name.SetColor(r, g, b)
+
+	//the full line is laid out once to find where it wraps
+	t.body.SetWrapWidth(float64(t.canvas.Width) - t.padding*2)
+	t.body.SetText(text)
+	t.lines = []rune(strings.Join(t.body.GetLines(), "\n"))
+	t.body.SetWrapWidth(0)
+
+	t.shown = 0
+	if t.speed <= 0 {
+		t.shown = float64(len(t.lines))
+	}
+	t.body.SetText(string(t.lines[:int(t.shown)]))
+}
+
+//Empties the box
+func (t *TextBox) Clear() {
+	t.name.SetText("")
+	t.body.SetText("")
+	t.lines = t.lines[:0]
+	t.shown = 0
+}
+
+//Sets the letters typed every second, where 0 shows lines at once
+func (t *TextBox) SetSpeed(lettersPerSecond float64) {
+	t.speed = lettersPerSecond
+}
+
+func (t *TextBox) GetSpeed() float64 {
+	return t.speed
+}
+
+//Returns true while the line is still being typed
+func (t *TextBox) IsTyping() bool {
+	return int(t.shown) < len(t.lines)
+}
+
+//Shows the rest of the line at once
+func (t *TextBox) Finish() {
+	t.shown = float64(len(t.lines))
+	t.body.SetText(string(t.lines))
+}
+
+//Returns the line being typed, as it is wrapped in the box
+func (t *TextBox) GetText() string {
+	return string(t.lines)
+}
+
+func (t *TextBox) GetName() string {
+	return t.name.GetText()
+}
+
+func (t *TextBox) SetVisible(visible bool) {
+	t.hidden = !visible
+}
+
+func (t *TextBox) IsVisible() bool {
+	return !t.hidden
+}
+
+//Types the letters due after dt seconds
+func (t *TextBox) Update(dt float64) {
+	if !t.IsTyping() {
+		return
+	}
+
+	before := int(t.shown)
+	t.shown += dt * t.speed
+	if t.speed <= 0 || int(t.shown) > len(t.lines) {
+		t.shown = float64(len(t.lines))
+	}
+	if int(t.shown) != before {
+		t.body.SetText(string(t.lines[:int(t.shown)]))
+	}
+}
+
+func (t *TextBox) GetArtists() []framework.Artist {
+	if t.hidden {
+		return []framework.Artist{}
+	}
+	return t.obj.GetArtists()
+}
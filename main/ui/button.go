@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Widget showing a text that does something when clicked
+type Button struct {
+	Base
+	skin    *framework.NineSlice
+	label   *framework.Text
+	onClick func()
+}
+
+//Creates a button.
+//	*InitButton(text)
+//	*InitButton(text, theme)
+//Where:
+//	theme is the theme the button is drawn with.  It is defaulted to DefaultTheme.
+func InitButton(text string, theme ...*Theme) *Button {
+	b := &Button{Base: initBase(themeArgument(theme), true)}
+
+	b.skin = b.theme.style("button").skin()
+	b.label = b.text(text)
+	b.add("skin", b.skin)
+	b.add("text", b.label)
+	return b
+}
+
+func (b *Button) SetText(text string) {
+	b.label.SetText(text)
+}
+
+func (b *Button) GetText() string {
+	return b.label.GetText()
+}
+
+//Sets the function called when the button is clicked or activated with the keyboard or a gamepad
+func (b *Button) OnClick(f func()) {
+	b.onClick = f
+}
+
+func (b *Button) activate() {
+	if b.onClick != nil {
+		b.onClick()
+	}
+}
+
+func (b *Button) release(x, y float64, inside bool) {
+	if inside {
+		b.activate()
+	}
+}
+
+func (b *Button) GetPreferredSize() (float64, float64) {
+	s := b.theme.style("button")
+	w, h := b.label.GetSize()
+	return b.fit(w+s.padding*2, h+s.padding*2)
+}
+
+func (b *Button) GetArtists() []framework.Artist {
+	s := b.theme.style("button")
+	state := b.state()
+
+	b.skin.SetSize(b.width, b.height)
+	tint(b.skin, s.color(state))
+
+	w, h := b.label.GetSize()
+	place(b.label, (b.width-w)/2, (b.height-h)/2)
+	tint(b.label, s.textColor(state))
+
+	return b.Base.GetArtists()
+}
+
+//Widget showing an image that does something when clicked.  The image is tinted by the colors of the imageButton style,
+//and drawn over the image of the style if it has one.
+type ImageButton struct {
+	Base
+	skin    *framework.NineSlice
+	icon    *framework.NineSlice
+	onClick func()
+}
+
+//Creates a button showing an image.
+//	*InitImageButton(dir)
+//	*InitImageButton(dir, theme)
+//Where:
+//	dir is the location of the image, relative to the executable file.
+//	theme is the theme the button is drawn with.  It is defaulted to DefaultTheme.
+func InitImageButton(dir string, theme ...*Theme) *ImageButton {
+	b := &ImageButton{Base: initBase(themeArgument(theme), true)}
+
+	if s := b.theme.style("imageButton"); s.image != nil {
+		b.skin = s.skin()
+		b.add("skin", b.skin)
+	}
+
+	b.icon = framework.InitNineSlice(dir, 0)
+	b.icon.SetOrigin(framework.Center)
+	b.icon.SetAnchor(0, 0)
+	b.add("icon", b.icon)
+	return b
+}
+
+//Sets the function called when the button is clicked or activated with the keyboard or a gamepad
+func (b *ImageButton) OnClick(f func()) {
+	b.onClick = f
+}
+
+func (b *ImageButton) activate() {
+	if b.onClick != nil {
+		b.onClick()
+	}
+}
+
+func (b *ImageButton) release(x, y float64, inside bool) {
+	if inside {
+		b.activate()
+	}
+}
+
+//Returns the size of the image with the padding of the style around it
+func (b *ImageButton) GetPreferredSize() (float64, float64) {
+	s := b.theme.style("imageButton")
+	w, h := b.icon.GetSize()
+	return b.fit(w+s.padding*2, h+s.padding*2)
+}
+
+func (b *ImageButton) GetArtists() []framework.Artist {
+	s := b.theme.style("imageButton")
+	c := s.color(b.state())
+
+	if b.skin != nil {
+		b.skin.SetSize(b.width, b.height)
+		tint(b.skin, c)
+	}
+
+	//the image keeps its proportions, shrunk to fit within the padding
+	w, h := b.icon.GetSize()
+	k := 1.0
+	if w > 0 && h > 0 {
+		k = math.Min(1, math.Min((b.width-s.padding*2)/w, (b.height-s.padding*2)/h))
+	}
+	b.icon.Scale(math.Max(k, 0))
+	place(b.icon, b.width/2, b.height/2)
+	tint(b.icon, c)
+
+	return b.Base.GetArtists()
+}
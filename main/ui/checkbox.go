@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Widget that is checked or unchecked by clicking it, with a text next to its box
+type Checkbox struct {
+	Base
+	box      *framework.NineSlice
+	check    *framework.NineSlice
+	label    *framework.Text
+	checked  bool
+	onChange func(bool)
+}
+
+//Creates an unchecked checkbox.
+//	*InitCheckbox(text)
+//	*InitCheckbox(text, theme)
+//Where:
+//	theme is the theme the checkbox is drawn with.  It is defaulted to DefaultTheme.
+func InitCheckbox(text string, theme ...*Theme) *Checkbox {
+	c := &Checkbox{Base: initBase(themeArgument(theme), true)}
+
+	c.box = c.theme.style("checkbox").skin()
+	c.check = c.theme.style("check").skin()
+	c.label = c.text(text)
+	c.add("box", c.box)
+	c.add("check", c.check)
+	c.add("text", c.label)
+	return c
+}
+
+func (c *Checkbox) SetText(text string) {
+	c.label.SetText(text)
+}
+
+func (c *Checkbox) GetText() string {
+	return c.label.GetText()
+}
+
+//Checks or unchecks the checkbox without calling the function set with OnChange
+func (c *Checkbox) SetChecked(checked bool) {
+	c.checked = checked
+}
+
+func (c *Checkbox) IsChecked() bool {
+	return c.checked
+}
+
+//Sets the function called with the new value when the player checks or unchecks the checkbox
+func (c *Checkbox) OnChange(f func(bool)) {
+	c.onChange = f
+}
+
+func (c *Checkbox) activate() {
+	c.checked = !c.checked
+	if c.onChange != nil {
+		c.onChange(c.checked)
+	}
+}
+
+func (c *Checkbox) release(x, y float64, inside bool) {
+	if inside {
+		c.activate()
+	}
+}
+
+func (c *Checkbox) GetPreferredSize() (float64, float64) {
+	s := c.theme.style("checkbox")
+	w, h := c.label.GetSize()
+	if w > 0 {
+		w += s.padding
+	}
+	return c.fit(s.width+w, math.Max(s.height, h))
+}
+
+func (c *Checkbox) GetArtists() []framework.Artist {
+	s := c.theme.style("checkbox")
+	cs := c.theme.style("check")
+	state := c.state()
+
+	c.box.SetSize(s.width, s.height)
+	place(c.box, 0, (c.height-s.height)/2)
+	tint(c.box, s.color(state))
+
+	c.check.SetSize(cs.width, cs.height)
+	place(c.check, (s.width-cs.width)/2, (c.height-cs.height)/2)
+	tint(c.check, cs.color(state))
+	if !c.checked {
+		c.check.SetAlpha(0)
+	}
+
+	_, h := c.label.GetSize()
+	place(c.label, s.width+s.padding, (c.height-h)/2)
+	tint(c.label, s.textColor(state))
+
+	return c.Base.GetArtists()
+}
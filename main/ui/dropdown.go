@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Widget showing the selected item of a list, which opens over the other widgets when clicked to choose another
+type Dropdown struct {
+	Base
+	skin     *framework.NineSlice
+	label    *framework.Text
+	arrow    *framework.Text
+	list     *List
+	popup    *ScrollPanel
+	onChange func(int, string)
+	//the item chosen, which the list goes back to when it is closed after the player browsed it without choosing
+	committed int
+}
+
+//Creates a dropdown of items with the first one selected.
+//	*InitDropdown(items)
+//	*InitDropdown(items, theme)
+//Where:
+//	theme is the theme the dropdown is drawn with.  It is defaulted to DefaultTheme.
+func InitDropdown(items []string, theme ...*Theme) *Dropdown {
+	d := &Dropdown{Base: initBase(themeArgument(theme), true)}
+
+	d.list = initList(items, d.theme, "popup")
+	d.list.OnActivate(func(index int, item string) {
+		d.choose(index)
+	})
+	d.popup = InitScrollPanel(d.list, d.theme)
+
+	d.skin = d.theme.style("dropdown").skin()
+	d.label = d.text("")
+	d.arrow = d.text("v")
+	d.add("skin", d.skin)
+	d.add("text", d.label)
+	d.add("arrow", d.arrow)
+
+	d.SetSelected(0)
+	return d
+}
+
+//Replaces the items, keeping the selection if it is still within them
+func (d *Dropdown) SetItems(items []string) {
+	d.list.SetItems(items)
+	d.SetSelected(d.committed)
+}
+
+func (d *Dropdown) GetItems() []string {
+	return d.list.GetItems()
+}
+
+//Selects the item at the index without calling the function set with OnChange.  -1 selects none of them.
+func (d *Dropdown) SetSelected(index int) {
+	d.list.SetSelected(index)
+	d.committed = d.list.GetSelected()
+	if d.committed >= 0 {
+		d.label.SetText(d.list.GetItems()[d.committed])
+	} else {
+		d.label.SetText("")
+	}
+}
+
+//Returns the index of the chosen item, or -1 if none of them is, even while the player browses the open list
+func (d *Dropdown) GetSelected() int {
+	return d.committed
+}
+
+//Sets the function called with the index and text of the item the player chooses
+func (d *Dropdown) OnChange(f func(int, string)) {
+	d.onChange = f
+}
+
+//Returns true while the list is open
+func (d *Dropdown) IsOpen() bool {
+	return d.ui != nil && d.ui.overlay == d.popup
+}
+
+func (d *Dropdown) held() []Widget {
+	return []Widget{d.popup}
+}
+
+func (d *Dropdown) overlayClosed() {
+	d.list.SetSelected(d.committed)
+}
+
+func (d *Dropdown) choose(index int) {
+	d.SetSelected(index)
+	if d.ui != nil {
+		d.ui.closeOverlay()
+	}
+	if d.onChange != nil {
+		d.onChange(index, d.list.GetItems()[index])
+	}
+}
+
+//Opens the list below the dropdown, or above it if there is more room there
+func (d *Dropdown) activate() {
+	if d.ui == nil {
+		return
+	}
+	if d.IsOpen() {
+		d.ui.closeOverlay()
+		return
+	}
+
+	canvas := d.ui.GetBounds()
+	_, h := d.popup.GetPreferredSize()
+	below := d.y - d.height - float64(canvas.Bottom)
+	above := float64(canvas.Up) - d.y
+
+	if h <= below || below >= above {
+		h = math.Min(h, below)
+		d.popup.SetBounds(d.x, d.y-d.height, d.width, h)
+	} else {
+		h = math.Min(h, above)
+		d.popup.SetBounds(d.x, d.y+h, d.width, h)
+	}
+
+	d.ui.openOverlay(d.popup, d)
+	d.ui.Focus(d.list)
+}
+
+func (d *Dropdown) release(x, y float64, inside bool) {
+	if inside {
+		d.activate()
+	}
+}
+
+func (d *Dropdown) GetPreferredSize() (float64, float64) {
+	s := d.theme.style("dropdown")
+
+	width := 0.0
+	for _, item := range d.list.GetItems() {
+		w, _ := d.theme.font.Measure(item)
+		width = math.Max(width, w)
+	}
+	aw, _ := d.arrow.GetSize()
+	return d.fit(width+aw+s.padding*3, d.theme.font.GetLineHeight()+s.padding*2)
+}
+
+func (d *Dropdown) GetArtists() []framework.Artist {
+	s := d.theme.style("dropdown")
+	state := d.state()
+	if d.IsOpen() {
+		state = statePressed
+	}
+
+	d.skin.SetSize(d.width, d.height)
+	tint(d.skin, s.color(state))
+
+	_, h := d.label.GetSize()
+	place(d.label, s.padding, (d.height-h)/2)
+	tint(d.label, s.textColor(state))
+
+	aw, ah := d.arrow.GetSize()
+	place(d.arrow, d.width-s.padding-aw, (d.height-ah)/2)
+	tint(d.arrow, s.textColor(state))
+
+	return d.Base.GetArtists()
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Widget showing a text
+type Label struct {
+	Base
+	label *framework.Text
+	wrap  bool
+	align float64
+}
+
+//Creates a label.
+//	*InitLabel(text)
+//	*InitLabel(text, theme)
+//Where:
+//	theme is the theme the label is drawn with.  It is defaulted to DefaultTheme.
+func InitLabel(text string, theme ...*Theme) *Label {
+	l := &Label{Base: initBase(themeArgument(theme), false)}
+
+	l.label = l.text(text)
+	l.add("text", l.label)
+	return l
+}
+
+func (l *Label) SetText(text string) {
+	l.label.SetText(text)
+}
+
+func (l *Label) GetText() string {
+	return l.label.GetText()
+}
+
+//Sets whether the lines are broken to fit within the width of the label
+func (l *Label) SetWrap(wrap bool) {
+	l.wrap = wrap
+	if !wrap {
+		l.label.SetWrapWidth(0)
+	}
+}
+
+func (l *Label) IsWrapping() bool {
+	return l.wrap
+}
+
+//Sets where the text is within the width of the label, where 0 is the left side, 0.5 the middle and 1 the right side
+func (l *Label) SetAlignment(x float64) {
+	l.align = x
+	switch {
+	case x < .5:
+		l.label.SetAlignment(framework.AlignLeft)
+	case x > .5:
+		l.label.SetAlignment(framework.AlignRight)
+	default:
+		l.label.SetAlignment(framework.AlignCenter)
+	}
+}
+
+func (l *Label) GetAlignment() float64 {
+	return l.align
+}
+
+func (l *Label) GetPreferredSize() (float64, float64) {
+	s := l.theme.style("label")
+	w, h := l.label.GetSize()
+	return l.fit(w+s.padding*2, h+s.padding*2)
+}
+
+func (l *Label) GetArtists() []framework.Artist {
+	s := l.theme.style("label")
+
+	if l.wrap {
+		width := l.width - s.padding*2
+		if width < 1 {
+			width = 1
+		}
+		if width != l.label.GetWrapWidth() {
+			l.label.SetWrapWidth(width)
+		}
+	}
+
+	w, h := l.label.GetSize()
+	place(l.label, s.padding+(l.width-s.padding*2-w)*l.align, (l.height-h)/2)
+	tint(l.label, s.textColor(l.state()))
+
+	return l.Base.GetArtists()
+}
@@ -0,0 +1,451 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Container placing its widgets one after the other, below each other in a vertical box or side by side in a horizontal
+//box.  Every widget gets its preferred length, and the widgets set to expand share the length left over.
+type Box struct {
+	Base
+	children []Widget
+	vertical bool
+	spacing  float64
+	padding  float64
+	stretch  bool
+	align    float64
+}
+
+//Creates a box placing the widgets below each other
+func InitVBox(children ...Widget) *Box {
+	return initBox(true, children)
+}
+
+//Creates a box placing the widgets side by side
+func InitHBox(children ...Widget) *Box {
+	return initBox(false, children)
+}
+
+func initBox(vertical bool, children []Widget) *Box {
+	b := &Box{
+		Base:     initBase(DefaultTheme(), false),
+		children: append([]Widget(nil), children...),
+		vertical: vertical,
+		spacing:  -1,
+		stretch:  true,
+	}
+	b.passive = true
+	return b
+}
+
+func (b *Box) Add(children ...Widget) {
+	b.children = append(b.children, children...)
+}
+
+//Removes the widget from the box, if it is in it
+func (b *Box) Remove(w Widget) {
+	for i, c := range b.children {
+		if c == w {
+			b.children = append(b.children[:i], b.children[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *Box) Clear() {
+	b.children = nil
+}
+
+func (b *Box) GetChildren() []Widget {
+	return b.children
+}
+
+func (b *Box) IsVertical() bool {
+	return b.vertical
+}
+
+//Sets the space left between the widgets.  It is defaulted to the spacing of the theme of the UI.
+func (b *Box) SetSpacing(spacing float64) {
+	b.spacing = spacing
+}
+
+func (b *Box) GetSpacing() float64 {
+	return spacingOf(&b.Base, b.spacing)
+}
+
+//Sets the space left around the widgets, within the bounds of the box.  It is defaulted to 0.
+func (b *Box) SetPadding(padding float64) {
+	b.padding = padding
+}
+
+func (b *Box) GetPadding() float64 {
+	return b.padding
+}
+
+//Sets whether the widgets are stretched across the box, which they are by default.  Widgets that are not stretched keep
+//their preferred size and are placed by the alignment.
+func (b *Box) SetStretch(stretch bool) {
+	b.stretch = stretch
+}
+
+//Sets where the widgets are across the box when they are not stretched, where 0 is the left or top side, 0.5 the middle
+//and 1 the right or bottom side
+func (b *Box) SetAlignment(align float64) {
+	b.align = align
+}
+
+//Returns the spacing of the container, or the spacing of the theme of its UI if it was not set
+func spacingOf(b *Base, spacing float64) float64 {
+	if spacing >= 0 {
+		return spacing
+	}
+	if b.ui != nil {
+		return b.ui.theme.spacing
+	}
+	return b.theme.spacing
+}
+
+func (b *Box) visibleChildren() []Widget {
+	visible := make([]Widget, 0, len(b.children))
+	for _, c := range b.children {
+		if c.IsVisible() {
+			visible = append(visible, c)
+		}
+	}
+	return visible
+}
+
+//Returns the length of the widget along the box and across it
+func (b *Box) lengths(w, h float64) (float64, float64) {
+	if b.vertical {
+		return h, w
+	}
+	return w, h
+}
+
+func (b *Box) GetPreferredSize() (float64, float64) {
+	children := b.visibleChildren()
+	spacing := b.GetSpacing()
+
+	along, across := 0.0, 0.0
+	for i, c := range children {
+		a, x := b.lengths(c.GetPreferredSize())
+		along += a
+		across = math.Max(across, x)
+		if i > 0 {
+			along += spacing
+		}
+	}
+
+	w, h := b.lengths(along+b.padding*2, across+b.padding*2)
+	return b.fit(w, h)
+}
+
+func (b *Box) layout() {
+	children := b.visibleChildren()
+	if len(children) == 0 {
+		return
+	}
+	spacing := b.GetSpacing()
+
+	length, breadth := b.lengths(b.width-b.padding*2, b.height-b.padding*2)
+
+	preferred := make([]float64, len(children))
+	total := spacing * float64(len(children)-1)
+	expanding := 0
+	for i, c := range children {
+		preferred[i], _ = b.lengths(c.GetPreferredSize())
+		total += preferred[i]
+		if c.base().expand {
+			expanding++
+		}
+	}
+
+	share := 0.0
+	if expanding > 0 && length > total {
+		share = (length - total) / float64(expanding)
+	}
+
+	position := b.padding
+	for i, c := range children {
+		along := preferred[i]
+		if c.base().expand {
+			along += share
+		}
+
+		across := breadth
+		offset := b.padding
+		if !b.stretch {
+			_, across = b.lengths(c.GetPreferredSize())
+			across = math.Min(across, breadth)
+			offset += (breadth - across) * b.align
+		}
+
+		if b.vertical {
+			c.SetBounds(b.x+offset, b.y-position, across, along)
+		} else {
+			c.SetBounds(b.x+position, b.y-offset, along, across)
+		}
+		position += along + spacing
+	}
+}
+
+//Container placing its widgets in rows of cells, filled from left to right.  Every column is as wide as its widest
+//widget and every row as tall as its tallest, and the columns share the width left over.
+type Grid struct {
+	Base
+	children []Widget
+	columns  int
+	spacing  float64
+	padding  float64
+}
+
+//Creates a grid of the number of columns given
+func InitGrid(columns int, children ...Widget) *Grid {
+	if columns < 1 {
+		panic(fmt.Sprintf("Invalid argument.  Expected at least 1 column, found %v", columns))
+	}
+
+	g := &Grid{
+		Base:     initBase(DefaultTheme(), false),
+		children: append([]Widget(nil), children...),
+		columns:  columns,
+		spacing:  -1,
+	}
+	g.passive = true
+	return g
+}
+
+func (g *Grid) Add(children ...Widget) {
+	g.children = append(g.children, children...)
+}
+
+//Removes the widget from the grid, if it is in it, moving the widgets after it back by a cell
+func (g *Grid) Remove(w Widget) {
+	for i, c := range g.children {
+		if c == w {
+			g.children = append(g.children[:i], g.children[i+1:]...)
+			return
+		}
+	}
+}
+
+func (g *Grid) Clear() {
+	g.children = nil
+}
+
+func (g *Grid) GetChildren() []Widget {
+	return g.children
+}
+
+func (g *Grid) GetColumns() int {
+	return g.columns
+}
+
+//Sets the space left between the cells.  It is defaulted to the spacing of the theme of the UI.
+func (g *Grid) SetSpacing(spacing float64) {
+	g.spacing = spacing
+}
+
+func (g *Grid) GetSpacing() float64 {
+	return spacingOf(&g.Base, g.spacing)
+}
+
+//Sets the space left around the cells, within the bounds of the grid.  It is defaulted to 0.
+func (g *Grid) SetPadding(padding float64) {
+	g.padding = padding
+}
+
+func (g *Grid) GetPadding() float64 {
+	return g.padding
+}
+
+//Returns the width of every column and the height of every row
+func (g *Grid) cells() ([]float64, []float64) {
+	widths := make([]float64, g.columns)
+	heights := make([]float64, (len(g.children)+g.columns-1)/g.columns)
+
+	for i, c := range g.children {
+		if !c.IsVisible() {
+			continue
+		}
+		w, h := c.GetPreferredSize()
+		widths[i%g.columns] = math.Max(widths[i%g.columns], w)
+		heights[i/g.columns] = math.Max(heights[i/g.columns], h)
+	}
+	return widths, heights
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func (g *Grid) GetPreferredSize() (float64, float64) {
+	widths, heights := g.cells()
+	spacing := g.GetSpacing()
+
+	w := sum(widths) + spacing*math.Max(0, float64(len(widths)-1)) + g.padding*2
+	h := sum(heights) + spacing*math.Max(0, float64(len(heights)-1)) + g.padding*2
+	return g.fit(w, h)
+}
+
+func (g *Grid) layout() {
+	widths, heights := g.cells()
+	spacing := g.GetSpacing()
+
+	left := g.width - g.padding*2 - sum(widths) - spacing*float64(len(widths)-1)
+	if left > 0 {
+		for i := range widths {
+			widths[i] += left / float64(len(widths))
+		}
+	}
+
+	y := g.padding
+	for row, h := range heights {
+		x := g.padding
+		for column, w := range widths {
+			i := row*g.columns + column
+			if i >= len(g.children) {
+				break
+			}
+			g.children[i].SetBounds(g.x+x, g.y-y, w, h)
+			x += w + spacing
+		}
+		y += h + spacing
+	}
+}
+
+//Where a widget is placed in an anchor panel
+type anchor struct {
+	widget  Widget
+	stretch bool
+	//the normalized point of the panel and the widget placed on each other, where (0, 0) is the top left corner
+	ax float64
+	ay float64
+	//the offset of the widget from the anchor, or the margins from the left, top, right and bottom sides when stretched
+	margins [4]float64
+}
+
+//Container placing each widget relative to a point of its bounds, such as a corner or the middle, so the widgets keep
+//their place when the panel is resized to another canvas.
+type AnchorPanel struct {
+	Base
+	anchors []anchor
+}
+
+func InitAnchorPanel() *AnchorPanel {
+	p := &AnchorPanel{Base: initBase(DefaultTheme(), false)}
+	p.passive = true
+	return p
+}
+
+//Adds a widget at its preferred size.
+//	Add(widget, x, y)
+//	Add(widget, x, y, offsetX, offsetY)
+//Where:
+//	x and y are the normalized point of the panel the same point of the widget is placed on, where (0, 0) is the top left
+//	corner, (0.5, 0.5) the middle and (1, 1) the bottom right corner.
+//	offsetX and offsetY move the widget from the point, with y going down.
+func (p *AnchorPanel) Add(w Widget, x, y float64, offset ...float64) {
+	a := anchor{widget: w, ax: x, ay: y}
+	switch len(offset) {
+	case 0:
+	case 2:
+		a.margins[0] = offset[0]
+		a.margins[1] = offset[1]
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected 0 or 2 offsets, found %v.", len(offset)))
+	}
+	p.anchors = append(p.anchors, a)
+}
+
+//Adds a widget stretched over the panel.
+//	AddStretched(widget)
+//	AddStretched(widget, margin)
+//	AddStretched(widget, left, top, right, bottom)
+//Where:
+//	margin is the space left between every side of the widget and the panel.
+//	left, top, right and bottom are the spaces left at each side.
+func (p *AnchorPanel) AddStretched(w Widget, margins ...float64) {
+	a := anchor{widget: w, stretch: true}
+	switch len(margins) {
+	case 0:
+	case 1:
+		a.margins = [4]float64{margins[0], margins[0], margins[0], margins[0]}
+	case 4:
+		a.margins = [4]float64{margins[0], margins[1], margins[2], margins[3]}
+	default:
+		panic(fmt.Sprintf("Invalid number of arguments.  Expected 0, 1 or 4 margins, found %v.", len(margins)))
+	}
+	p.anchors = append(p.anchors, a)
+}
+
+func (p *AnchorPanel) Remove(w Widget) {
+	for i, a := range p.anchors {
+		if a.widget == w {
+			p.anchors = append(p.anchors[:i], p.anchors[i+1:]...)
+			return
+		}
+	}
+}
+
+func (p *AnchorPanel) Clear() {
+	p.anchors = nil
+}
+
+func (p *AnchorPanel) GetChildren() []Widget {
+	children := make([]Widget, len(p.anchors))
+	for i, a := range p.anchors {
+		children[i] = a.widget
+	}
+	return children
+}
+
+//Returns the size that holds every widget at its preferred size
+func (p *AnchorPanel) GetPreferredSize() (float64, float64) {
+	width, height := 0.0, 0.0
+	for _, a := range p.anchors {
+		w, h := a.widget.GetPreferredSize()
+		if a.stretch {
+			w += a.margins[0] + a.margins[2]
+			h += a.margins[1] + a.margins[3]
+		} else {
+			w += math.Abs(a.margins[0])
+			h += math.Abs(a.margins[1])
+		}
+		width = math.Max(width, w)
+		height = math.Max(height, h)
+	}
+	return p.fit(width, height)
+}
+
+func (p *AnchorPanel) layout() {
+	for _, a := range p.anchors {
+		if a.stretch {
+			a.widget.SetBounds(
+				p.x+a.margins[0],
+				p.y-a.margins[1],
+				math.Max(0, p.width-a.margins[0]-a.margins[2]),
+				math.Max(0, p.height-a.margins[1]-a.margins[3]),
+			)
+			continue
+		}
+
+		w, h := a.widget.GetPreferredSize()
+		x := p.x + (p.width-w)*a.ax + a.margins[0]
+		y := p.y - (p.height-h)*a.ay - a.margins[1]
+		a.widget.SetBounds(x, y, w, h)
+	}
+}
+
+//Returns the bounds of the canvas, which is given by its center
+func canvasBounds(c framework.Canvas) (float64, float64, float64, float64) {
+	return float64(c.X - c.Width/2), float64(c.Y + c.Height/2), float64(c.Width), float64(c.Height)
+}
@@ -0,0 +1,231 @@
+package ui
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Widget showing items one below the other, one of which can be selected.  The up and down arrow keys move the selection
+//while it has focus.  Place it in a ScrollPanel to show more items than fit.
+type List struct {
+	Base
+	style      string
+	skin       *framework.NineSlice
+	items      []string
+	rows       []*framework.NineSlice
+	labels     []*framework.Text
+	selected   int
+	hoveredRow int
+	onSelect   func(int, string)
+	onActivate func(int, string)
+}
+
+//Creates a list of items with none of them selected.
+//	*InitList(items)
+//	*InitList(items, theme)
+//Where:
+//	theme is the theme the list is drawn with.  It is defaulted to DefaultTheme.
+func InitList(items []string, theme ...*Theme) *List {
+	return initList(items, themeArgument(theme), "list")
+}
+
+//Creates a list drawn with the style, such as the popup of a dropdown
+func initList(items []string, theme *Theme, style string) *List {
+	l := &List{
+		Base:       initBase(theme, true),
+		style:      style,
+		selected:   -1,
+		hoveredRow: -1,
+	}
+
+	l.skin = l.theme.style(l.style).skin()
+	l.SetItems(items)
+	return l
+}
+
+//Replaces the items, keeping the selection if it is still within them.  The rows of the items left over are reused and
+//the ones no longer needed are freed.
+func (l *List) SetItems(items []string) {
+	l.items = append([]string(nil), items...)
+	if l.selected >= len(items) {
+		l.selected = -1
+	}
+
+	for i := len(items); i < len(l.rows); i++ {
+		l.rows[i].Delete()
+		l.labels[i].Delete()
+	}
+	if len(items) < len(l.rows) {
+		l.rows = l.rows[:len(items)]
+		l.labels = l.labels[:len(items)]
+	}
+
+	for i, item := range items {
+		if i < len(l.rows) {
+			l.labels[i].SetText(item)
+			continue
+		}
+		l.rows = append(l.rows, l.theme.style("item").skin())
+		l.labels = append(l.labels, l.text(item))
+	}
+
+	l.reset()
+	l.add("skin", l.skin)
+	for i := range items {
+		l.add("row"+strconv.Itoa(i), l.rows[i])
+		l.add("text"+strconv.Itoa(i), l.labels[i])
+	}
+}
+
+func (l *List) GetItems() []string {
+	return l.items
+}
+
+//Selects the item at the index without calling the function set with OnSelect.  -1 selects none of them.
+func (l *List) SetSelected(index int) {
+	if index < -1 || index >= len(l.items) {
+		index = -1
+	}
+	l.selected = index
+}
+
+//Returns the index of the selected item, or -1 if none of them is selected
+func (l *List) GetSelected() int {
+	return l.selected
+}
+
+//Sets the function called with the index and text of the item the player selects
+func (l *List) OnSelect(f func(int, string)) {
+	l.onSelect = f
+}
+
+//Sets the function called with the index and text of the item the player clicks, or selects and presses Enter on
+func (l *List) OnActivate(f func(int, string)) {
+	l.onActivate = f
+}
+
+func (l *List) selectItem(index int) {
+	if index < 0 || index >= len(l.items) || index == l.selected {
+		return
+	}
+
+	l.selected = index
+	reveal(l, l.rowBounds(index))
+	if l.onSelect != nil {
+		l.onSelect(index, l.items[index])
+	}
+}
+
+//Returns the height of every row
+func (l *List) rowHeight() float64 {
+	return l.theme.font.GetLineHeight() + l.theme.style("item").padding*2
+}
+
+//Returns the index of the row at the height, or -1 if there is none
+func (l *List) rowAt(y float64) int {
+	padding := l.theme.style(l.style).padding
+	row := int(math.Floor((l.y - padding - y) / l.rowHeight()))
+	if row < 0 || row >= len(l.items) {
+		return -1
+	}
+	return row
+}
+
+func (l *List) rowBounds(index int) framework.Bound {
+	padding := l.theme.style(l.style).padding
+	top := l.y - padding - float64(index)*l.rowHeight()
+	return framework.Bound{
+		Left:   float32(l.x),
+		Right:  float32(l.x + l.width),
+		Up:     float32(top),
+		Bottom: float32(top - l.rowHeight()),
+	}
+}
+
+func (l *List) hover(x, y float64) {
+	l.hoveredRow = l.rowAt(y)
+}
+
+func (l *List) press(x, y float64) {
+	l.selectItem(l.rowAt(y))
+}
+
+func (l *List) release(x, y float64, inside bool) {
+	if inside && l.rowAt(y) == l.selected {
+		l.activate()
+	}
+}
+
+func (l *List) activate() {
+	if l.selected >= 0 && l.onActivate != nil {
+		l.onActivate(l.selected, l.items[l.selected])
+	}
+}
+
+//Moves the selection up and down, letting the focus leave the list past its first and last items
+func (l *List) navigate(dx, dy int) bool {
+	if dy == 0 {
+		return false
+	}
+
+	next := l.selected + dy
+	if l.selected < 0 {
+		next = 0
+	}
+	if next < 0 || next >= len(l.items) {
+		return false
+	}
+	l.selectItem(next)
+	return true
+}
+
+func (l *List) GetPreferredSize() (float64, float64) {
+	padding := l.theme.style(l.style).padding
+	itemPadding := l.theme.style("item").padding
+
+	width := 0.0
+	for _, t := range l.labels {
+		w, _ := t.GetSize()
+		width = math.Max(width, w)
+	}
+	return l.fit(width+itemPadding*2+padding*2, float64(len(l.items))*l.rowHeight()+padding*2)
+}
+
+func (l *List) GetArtists() []framework.Artist {
+	s := l.theme.style(l.style)
+	item := l.theme.style("item")
+	state := stateNormal
+	if l.disabled {
+		state = stateDisabled
+	} else if l.focused {
+		state = stateFocused
+	}
+
+	l.skin.SetSize(l.width, l.height)
+	tint(l.skin, s.color(state))
+
+	rowHeight := l.rowHeight()
+	for i := range l.items {
+		rowState := stateNormal
+		switch {
+		case l.disabled:
+			rowState = stateDisabled
+		case i == l.selected:
+			rowState = stateSelected
+		case i == l.hoveredRow && l.hovered:
+			rowState = stateHover
+		}
+
+		top := s.padding + float64(i)*rowHeight
+		l.rows[i].SetSize(math.Max(0, l.width-s.padding*2), rowHeight)
+		place(l.rows[i], s.padding, top)
+		tint(l.rows[i], item.color(rowState))
+
+		place(l.labels[i], s.padding+item.padding, top+item.padding)
+		tint(l.labels[i], item.textColor(rowState))
+	}
+
+	return l.Base.GetArtists()
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Widget showing part of a taller widget, scrolled up and down with the mouse wheel or by dragging its bar.  It scrolls
+//by itself to show the widget that has focus.
+type ScrollPanel struct {
+	Base
+	skin     *framework.NineSlice
+	bar      *framework.NineSlice
+	content  Widget
+	scrollY  float64
+	maxY     float64
+	dragging bool
+	grab     float64
+}
+
+//Creates a scroll panel.
+//	*InitScrollPanel(content)
+//	*InitScrollPanel(content, theme)
+//Where:
+//	content is the widget scrolled within the panel, usually a VBox.
+//	theme is the theme the panel is drawn with.  It is defaulted to DefaultTheme.
+//The panel is as large as its content unless it is given a size with SetMinSize or by its container, so set one.  The
+//size set with SetMinSize is kept even when the content is smaller.
+func InitScrollPanel(content Widget, theme ...*Theme) *ScrollPanel {
+	p := &ScrollPanel{
+		Base:    initBase(themeArgument(theme), false),
+		content: content,
+	}
+
+	p.skin = p.theme.style("panel").skin()
+	p.bar = p.theme.style("scrollBar").skin()
+	p.add("skin", p.skin)
+	p.add("bar", p.bar)
+	return p
+}
+
+func (p *ScrollPanel) SetContent(content Widget) {
+	p.content = content
+	p.scrollY = 0
+}
+
+func (p *ScrollPanel) GetContent() Widget {
+	return p.content
+}
+
+//Sets how far the content is scrolled down from its top
+func (p *ScrollPanel) SetScroll(y float64) {
+	p.scrollY = clamp(y, 0, p.maxY)
+	p.layout()
+}
+
+func (p *ScrollPanel) GetScroll() float64 {
+	return p.scrollY
+}
+
+//Returns how far the content can be scrolled down, as of the last layout
+func (p *ScrollPanel) GetMaxScroll() float64 {
+	return p.maxY
+}
+
+//Scrolls just enough for the bound to be within the panel
+func (p *ScrollPanel) ScrollTo(b framework.Bound) {
+	padding := p.theme.style("panel").padding
+	top := p.y - padding
+	bottom := p.y - p.height + padding
+
+	if float64(b.Up) > top {
+		p.SetScroll(p.scrollY - (float64(b.Up) - top))
+	} else if float64(b.Bottom) < bottom {
+		p.SetScroll(p.scrollY + math.Min(bottom-float64(b.Bottom), top-float64(b.Up)))
+	}
+}
+
+func (p *ScrollPanel) GetChildren() []Widget {
+	if p.content == nil {
+		return nil
+	}
+	return []Widget{p.content}
+}
+
+//Returns the width of the bar, or 0 while the content fits in the panel
+func (p *ScrollPanel) barWidth() float64 {
+	if p.maxY <= 0 {
+		return 0
+	}
+	return p.theme.style("scrollBar").width
+}
+
+func (p *ScrollPanel) layout() {
+	if p.content == nil {
+		p.maxY = 0
+		return
+	}
+
+	padding := p.theme.style("panel").padding
+	width := p.width - padding*2
+	height := p.height - padding*2
+
+	_, ch := p.content.GetPreferredSize()
+	p.maxY = math.Max(0, ch-height)
+	p.scrollY = clamp(p.scrollY, 0, p.maxY)
+
+	p.content.SetBounds(p.x+padding, p.y-padding+p.scrollY, math.Max(0, width-p.barWidth()), math.Max(ch, height))
+}
+
+func (p *ScrollPanel) scroll(dx, dy float64) bool {
+	previous := p.scrollY
+	p.SetScroll(p.scrollY - dy*p.theme.font.GetLineHeight()*3)
+	return p.scrollY != previous
+}
+
+//Returns the top of the bar from the top of the track, and its length
+func (p *ScrollPanel) thumb() (float64, float64) {
+	padding := p.theme.style("panel").padding
+	track := p.height - padding*2
+	if track <= 0 {
+		return 0, 0
+	}
+	length := track * track / (track + p.maxY)
+	if p.maxY <= 0 {
+		return 0, length
+	}
+	return (track - length) * p.scrollY / p.maxY, length
+}
+
+func (p *ScrollPanel) press(x, y float64) {
+	padding := p.theme.style("panel").padding
+	if p.barWidth() == 0 || x < p.x+p.width-padding-p.barWidth() {
+		return
+	}
+
+	//grabbing the bar keeps the point grabbed under the mouse, and clicking the track jumps the middle of the bar there
+	top, length := p.thumb()
+	from := p.y - padding - y
+	if from >= top && from <= top+length {
+		p.grab = from - top
+	} else {
+		p.grab = length / 2
+	}
+	p.dragging = true
+	p.drag(x, y)
+}
+
+func (p *ScrollPanel) drag(x, y float64) {
+	if !p.dragging {
+		return
+	}
+
+	padding := p.theme.style("panel").padding
+	_, length := p.thumb()
+	free := p.height - padding*2 - length
+	if free <= 0 {
+		return
+	}
+	top := p.y - padding - y - p.grab
+	p.SetScroll(clamp(top/free, 0, 1) * p.maxY)
+}
+
+func (p *ScrollPanel) release(x, y float64, inside bool) {
+	p.dragging = false
+}
+
+func (p *ScrollPanel) GetPreferredSize() (float64, float64) {
+	padding := p.theme.style("panel").padding
+	if p.content == nil {
+		return p.fit(padding*2, padding*2)
+	}
+
+	//a size set with SetMinSize is kept rather than grown to the content, or there would be nothing to scroll
+	w, h := p.content.GetPreferredSize()
+	w += padding*2 + p.theme.style("scrollBar").width
+	h += padding * 2
+	if p.minWidth > 0 {
+		w = p.minWidth
+	}
+	if p.minHeight > 0 {
+		h = p.minHeight
+	}
+	return w, h
+}
+
+func (p *ScrollPanel) GetArtists() []framework.Artist {
+	panel := p.theme.style("panel")
+	bar := p.theme.style("scrollBar")
+
+	p.skin.SetSize(p.width, p.height)
+	tint(p.skin, panel.color(p.state()))
+
+	top, length := p.thumb()
+	p.bar.SetSize(p.barWidth(), length)
+	place(p.bar, p.width-panel.padding-p.barWidth(), panel.padding+top)
+	state := stateNormal
+	if p.dragging {
+		state = statePressed
+	} else if p.hovered {
+		state = stateHover
+	}
+	tint(p.bar, bar.color(state))
+
+	return p.Base.GetArtists()
+}
+
+//Returns the bounds the children are drawn and hit within
+func (p *ScrollPanel) clipBounds() framework.Bound {
+	padding := float32(p.theme.style("panel").padding)
+	b := p.GetBounds()
+	return framework.Bound{Left: b.Left + padding, Right: b.Right - padding, Up: b.Up - padding, Bottom: b.Bottom + padding}
+}
@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Widget choosing a number within a range by dragging a handle along a track.  The left and right arrow keys move the
+//handle while it has focus.
+type Slider struct {
+	Base
+	track    *framework.NineSlice
+	handle   *framework.NineSlice
+	min      float64
+	max      float64
+	step     float64
+	value    float64
+	onChange func(float64)
+}
+
+//Creates a slider.
+//	*InitSlider(min, max)
+//	*InitSlider(min, max, theme)
+//Where:
+//	min and max are the values at the left and right ends of the track.  The value starts at min.
+//	theme is the theme the slider is drawn with.  It is defaulted to DefaultTheme.
+func InitSlider(min, max float64, theme ...*Theme) *Slider {
+	if max < min {
+		panic(fmt.Sprintf("Invalid argument.  Expected the max to be at least the min, found %v to %v", min, max))
+	}
+
+	s := &Slider{
+		Base: initBase(themeArgument(theme), true),
+		min:  min,
+		max:  max,
+	}
+
+	s.track = s.theme.style("slider").skin()
+	s.handle = s.theme.style("handle").skin()
+	s.add("track", s.track)
+	s.add("handle", s.handle)
+	return s
+}
+
+//Sets the value without calling the function set with OnChange
+func (s *Slider) SetValue(v float64) {
+	s.value = s.snap(v)
+}
+
+func (s *Slider) GetValue() float64 {
+	return s.value
+}
+
+func (s *Slider) GetRange() (float64, float64) {
+	return s.min, s.max
+}
+
+//Sets the steps the value moves in, from the min.  0 lets it take any value, and the arrow keys move it by a twentieth of
+//the range.
+func (s *Slider) SetStep(step float64) {
+	if step < 0 {
+		panic(fmt.Sprintf("Invalid argument.  Expected a step of 0 or more, found %v", step))
+	}
+	s.step = step
+	s.value = s.snap(s.value)
+}
+
+func (s *Slider) GetStep() float64 {
+	return s.step
+}
+
+//Sets the function called with the new value when the player moves the handle
+func (s *Slider) OnChange(f func(float64)) {
+	s.onChange = f
+}
+
+func (s *Slider) snap(v float64) float64 {
+	if s.step > 0 {
+		v = s.min + math.Round((v-s.min)/s.step)*s.step
+	}
+	return clamp(v, s.min, s.max)
+}
+
+func (s *Slider) change(v float64) {
+	v = s.snap(v)
+	if v == s.value {
+		return
+	}
+
+	s.value = v
+	if s.onChange != nil {
+		s.onChange(v)
+	}
+}
+
+//Returns the length the center of the handle moves along
+func (s *Slider) length() float64 {
+	return math.Max(0, s.width-s.theme.style("handle").width)
+}
+
+func (s *Slider) press(x, y float64) {
+	s.drag(x, y)
+}
+
+func (s *Slider) drag(x, y float64) {
+	length := s.length()
+	if length == 0 {
+		return
+	}
+
+	t := (x - s.x - s.theme.style("handle").width/2) / length
+	s.change(s.min + clamp(t, 0, 1)*(s.max-s.min))
+}
+
+func (s *Slider) navigate(dx, dy int) bool {
+	if dx == 0 {
+		return false
+	}
+
+	step := s.step
+	if step == 0 {
+		step = (s.max - s.min) / 20
+	}
+	s.change(s.value + float64(dx)*step)
+	return true
+}
+
+func (s *Slider) scroll(dx, dy float64) bool {
+	if !s.focused || dy == 0 {
+		return false
+	}
+	return s.navigate(int(math.Copysign(1, dy)), 0)
+}
+
+func (s *Slider) GetPreferredSize() (float64, float64) {
+	track := s.theme.style("slider")
+	handle := s.theme.style("handle")
+	return s.fit(track.width, math.Max(track.height, handle.height))
+}
+
+func (s *Slider) GetArtists() []framework.Artist {
+	track := s.theme.style("slider")
+	handle := s.theme.style("handle")
+	state := s.state()
+
+	s.track.SetSize(s.width, track.height)
+	place(s.track, 0, (s.height-track.height)/2)
+	tint(s.track, track.color(state))
+
+	t := 0.0
+	if s.max > s.min {
+		t = (s.value - s.min) / (s.max - s.min)
+	}
+	s.handle.SetSize(handle.width, handle.height)
+	place(s.handle, t*s.length(), (s.height-handle.height)/2)
+	tint(s.handle, handle.color(state))
+
+	return s.Base.GetArtists()
+}
@@ -0,0 +1,471 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Widget editing a line of text.  The text scrolls sideways to keep the caret in view when it is longer than the field.
+//Shift with the arrow keys or dragging the mouse selects text, and Ctrl with A, C, X and V selects all of it, copies, cuts
+//and pastes.  Text being composed with an input method, as read by the input.Composer set on the input, is shown at the
+//caret, underlined and in the composition colors, until it is committed.
+type TextField struct {
+	Base
+	skin        *framework.NineSlice
+	selection   *framework.NineSlice
+	label       *framework.Text
+	underline   *framework.NineSlice
+	preedit     *framework.Text
+	tail        *framework.Text
+	placeholder *framework.Text
+	caret       *framework.NineSlice
+	value       []rune
+	position    int
+	anchor      int
+	composition []rune
+	compCaret   int
+	offset      float64
+	caretX      float64
+	maxLength   int
+	blink       float64
+	onChange    func(string)
+	onSubmit    func(string)
+}
+
+//Creates an empty text field.
+//	*InitTextField()
+//	*InitTextField(theme)
+//Where:
+//	theme is the theme the text field is drawn with.  It is defaulted to DefaultTheme.
+func InitTextField(theme ...*Theme) *TextField {
+	t := &TextField{Base: initBase(themeArgument(theme), true)}
+
+	t.skin = t.theme.style("textField").skin()
+	t.selection = t.theme.style("selection").skin()
+	t.label = t.text("")
+	t.underline = t.theme.style("composition").skin()
+	t.preedit = t.text("")
+	t.tail = t.text("")
+	t.placeholder = t.text("")
+	t.caret = t.theme.style("caret").skin()
+	t.add("skin", t.skin)
+	t.add("selection", t.selection)
+	t.add("text", t.label)
+	t.add("underline", t.underline)
+	t.add("preedit", t.preedit)
+	t.add("tail", t.tail)
+	t.add("placeholder", t.placeholder)
+	t.add("caret", t.caret)
+	return t
+}
+
+//Sets the text without calling the function set with OnChange, and moves the caret to its end
+func (t *TextField) SetText(text string) {
+	t.value = []rune(text)
+	if t.maxLength > 0 && len(t.value) > t.maxLength {
+		t.value = t.value[:t.maxLength]
+	}
+	t.position = len(t.value)
+	t.anchor = t.position
+	t.refresh()
+}
+
+func (t *TextField) GetText() string {
+	return string(t.value)
+}
+
+//Sets the text shown in the field while it is empty
+func (t *TextField) SetPlaceholder(text string) {
+	t.placeholder.SetText(text)
+}
+
+func (t *TextField) GetPlaceholder() string {
+	return t.placeholder.GetText()
+}
+
+//Sets the most characters the field holds.  0 does not limit them.
+func (t *TextField) SetMaxLength(length int) {
+	t.maxLength = length
+	t.SetText(string(t.value))
+}
+
+func (t *TextField) GetMaxLength() int {
+	return t.maxLength
+}
+
+//Sets the position of the caret, in characters from the start of the text, clearing the selection
+func (t *TextField) SetCaret(position int) {
+	t.moveCaret(position, false)
+}
+
+func (t *TextField) GetCaret() int {
+	return t.position
+}
+
+//Selects the characters from start up to end
+func (t *TextField) Select(start, end int) {
+	t.moveCaret(start, false)
+	t.moveCaret(end, true)
+}
+
+func (t *TextField) SelectAll() {
+	t.Select(0, len(t.value))
+}
+
+//Returns the start and end of the selection, which are the same when nothing is selected
+func (t *TextField) GetSelection() (int, int) {
+	if t.anchor < t.position {
+		return t.anchor, t.position
+	}
+	return t.position, t.anchor
+}
+
+func (t *TextField) GetSelectedText() string {
+	start, end := t.GetSelection()
+	return string(t.value[start:end])
+}
+
+//Returns the text being composed with an input method, which is not part of the text until it is committed
+func (t *TextField) GetComposition() string {
+	return string(t.composition)
+}
+
+//Sets the function called with the new text whenever the player edits it
+func (t *TextField) OnChange(f func(string)) {
+	t.onChange = f
+}
+
+//Sets the function called with the text when the player presses Enter
+func (t *TextField) OnSubmit(f func(string)) {
+	t.onSubmit = f
+}
+
+//Moves the caret, keeping the other end of the selection where it is if extend is true
+func (t *TextField) moveCaret(position int, extend bool) {
+	t.position = int(clamp(float64(position), 0, float64(len(t.value))))
+	if !extend {
+		t.anchor = t.position
+	}
+	t.blink = 0
+	t.refresh()
+}
+
+//Draws the text again, split at the caret while something is being composed
+func (t *TextField) refresh() {
+	if len(t.composition) == 0 {
+		t.label.SetText(string(t.value))
+		t.preedit.SetText("")
+		t.tail.SetText("")
+		return
+	}
+
+	t.label.SetText(string(t.value[:t.position]))
+	t.preedit.SetText(string(t.composition))
+	t.tail.SetText(string(t.value[t.position:]))
+}
+
+//Replaces the selection with the text, as much of it as fits within the max length
+func (t *TextField) insert(text []rune) {
+	start, end := t.GetSelection()
+	if t.maxLength > 0 {
+		room := t.maxLength - (len(t.value) - (end - start))
+		if len(text) > room {
+			text = text[:int(math.Max(0, float64(room)))]
+		}
+	}
+	if len(text) == 0 && start == end {
+		return
+	}
+
+	value := make([]rune, 0, len(t.value)-(end-start)+len(text))
+	value = append(value, t.value[:start]...)
+	value = append(value, text...)
+	value = append(value, t.value[end:]...)
+	t.edit(value, start+len(text))
+}
+
+func (t *TextField) edit(value []rune, position int) {
+	t.value = value
+	t.moveCaret(position, false)
+
+	if t.onChange != nil {
+		t.onChange(string(value))
+	}
+}
+
+func (t *TextField) typed(r rune) {
+	if unicode.IsPrint(r) {
+		t.insert([]rune{r})
+	}
+}
+
+//Shows the text being composed with an input method at the caret
+func (t *TextField) compose(text string, caret int) {
+	if text == string(t.composition) && caret == t.compCaret {
+		return
+	}
+
+	t.composition = []rune(text)
+	t.compCaret = int(clamp(float64(caret), 0, float64(len(t.composition))))
+	t.blink = 0
+	t.refresh()
+}
+
+//Returns the bottom left corner of the caret as it was last drawn
+func (t *TextField) candidatePoint() (float64, float64) {
+	lineHeight := t.theme.font.GetLineHeight()
+	return t.x + t.caretX, t.y - (t.height+lineHeight)/2
+}
+
+//Returns the position of the start of the word before the caret
+func (t *TextField) wordLeft() int {
+	i := t.position
+	for i > 0 && unicode.IsSpace(t.value[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(t.value[i-1]) {
+		i--
+	}
+	return i
+}
+
+//Returns the position of the end of the word after the caret
+func (t *TextField) wordRight() int {
+	i := t.position
+	for i < len(t.value) && unicode.IsSpace(t.value[i]) {
+		i++
+	}
+	for i < len(t.value) && !unicode.IsSpace(t.value[i]) {
+		i++
+	}
+	return i
+}
+
+func (t *TextField) key(k glfw.Key, mods glfw.ModifierKey) bool {
+	//the input method takes the keys while it composes
+	if len(t.composition) > 0 {
+		return true
+	}
+
+	shift := mods&glfw.ModShift != 0
+	//Ctrl, or Command on a mac
+	command := mods&(glfw.ModControl|glfw.ModSuper) != 0
+	start, end := t.GetSelection()
+
+	switch k {
+	case glfw.KeyBackspace:
+		if start == end && command {
+			t.moveCaret(t.wordLeft(), true)
+		} else if start == end {
+			t.moveCaret(t.position-1, true)
+		}
+		t.insert(nil)
+	case glfw.KeyDelete:
+		if start == end && command {
+			t.moveCaret(t.wordRight(), true)
+		} else if start == end {
+			t.moveCaret(t.position+1, true)
+		}
+		t.insert(nil)
+	case glfw.KeyLeft:
+		switch {
+		case command:
+			t.moveCaret(t.wordLeft(), shift)
+		case start != end && !shift:
+			t.moveCaret(start, false)
+		default:
+			t.moveCaret(t.position-1, shift)
+		}
+	case glfw.KeyRight:
+		switch {
+		case command:
+			t.moveCaret(t.wordRight(), shift)
+		case start != end && !shift:
+			t.moveCaret(end, false)
+		default:
+			t.moveCaret(t.position+1, shift)
+		}
+	case glfw.KeyHome:
+		t.moveCaret(0, shift)
+	case glfw.KeyEnd:
+		t.moveCaret(len(t.value), shift)
+	case glfw.KeyA:
+		if !command {
+			return false
+		}
+		t.SelectAll()
+	case glfw.KeyC, glfw.KeyX:
+		if !command {
+			return false
+		}
+		if start != end && t.ui != nil && t.ui.input != nil {
+			t.ui.input.SetClipboard(t.GetSelectedText())
+			if k == glfw.KeyX {
+				t.insert(nil)
+			}
+		}
+	case glfw.KeyV:
+		if !command {
+			return false
+		}
+		if t.ui != nil && t.ui.input != nil {
+			t.paste(t.ui.input.GetClipboard())
+		}
+	case glfw.KeyEnter, glfw.KeyKPEnter:
+		if t.onSubmit != nil {
+			t.onSubmit(string(t.value))
+		}
+	case glfw.KeySpace:
+		//typed as a character, not taken as a click
+	default:
+		return false
+	}
+	return true
+}
+
+//Inserts the text at the caret, on one line and without the characters that cannot be shown
+func (t *TextField) paste(text string) {
+	text = strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ").Replace(text)
+
+	runes := make([]rune, 0, len(text))
+	for _, r := range text {
+		if unicode.IsPrint(r) {
+			runes = append(runes, r)
+		}
+	}
+	t.insert(runes)
+}
+
+//Returns the character boundary nearest the point
+func (t *TextField) positionAt(x float64) int {
+	padding := t.theme.style("textField").padding
+	target := x - t.x - padding + t.offset
+
+	best := 0
+	for i := 1; i <= len(t.value); i++ {
+		if math.Abs(t.measure(i)-target) < math.Abs(t.measure(best)-target) {
+			best = i
+		}
+	}
+	return best
+}
+
+//Moves the caret to the mouse, or selects up to it while Shift is held
+func (t *TextField) press(x, y float64) {
+	if len(t.composition) > 0 {
+		return
+	}
+
+	extend := t.ui != nil && t.ui.input != nil && t.ui.input.GetModifiers()&glfw.ModShift != 0
+	t.moveCaret(t.positionAt(x), extend)
+}
+
+func (t *TextField) drag(x, y float64) {
+	if len(t.composition) > 0 {
+		return
+	}
+	t.moveCaret(t.positionAt(x), true)
+}
+
+//Returns the width of the first n characters
+func (t *TextField) measure(n int) float64 {
+	w, _ := t.theme.font.Measure(string(t.value[:n]))
+	return w
+}
+
+func (t *TextField) update(dt float64) {
+	t.blink += dt
+}
+
+func (t *TextField) GetPreferredSize() (float64, float64) {
+	s := t.theme.style("textField")
+	return t.fit(s.width, t.theme.font.GetLineHeight()+s.padding*2)
+}
+
+func (t *TextField) GetArtists() []framework.Artist {
+	s := t.theme.style("textField")
+	state := t.state()
+	inner := t.width - s.padding*2
+	lineHeight := t.theme.font.GetLineHeight()
+	top := (t.height - lineHeight) / 2
+
+	t.skin.SetSize(t.width, t.height)
+	tint(t.skin, s.color(state))
+
+	head := t.measure(t.position)
+	composed, _ := t.theme.font.Measure(string(t.composition))
+	caret := head
+	if len(t.composition) > 0 {
+		w, _ := t.theme.font.Measure(string(t.composition[:t.compCaret]))
+		caret += w
+	}
+
+	//scroll just enough to keep the caret within the field
+	if caret-t.offset > inner {
+		t.offset = caret - inner
+	}
+	if caret < t.offset {
+		t.offset = caret
+	}
+	full, _ := t.theme.font.Measure(string(t.value))
+	t.offset = clamp(t.offset, 0, math.Max(0, full+composed-inner))
+	left := s.padding - t.offset
+
+	start, end := t.GetSelection()
+	ss := t.theme.style("selection")
+	from := t.measure(start)
+	t.selection.SetSize(t.measure(end)-from, lineHeight)
+	place(t.selection, left+from, top)
+	tint(t.selection, ss.color(stateNormal))
+	if start == end || !t.focused {
+		t.selection.SetAlpha(0)
+	}
+
+	place(t.label, left, top)
+	tint(t.label, s.textColor(state))
+
+	//the composition goes between the text before and after the caret, underlined
+	cs := t.theme.style("composition")
+	place(t.preedit, left+head, top)
+	tint(t.preedit, cs.textColor(stateNormal))
+	t.underline.SetSize(composed, math.Max(1, cs.height))
+	place(t.underline, left+head, top+lineHeight-math.Max(1, cs.height))
+	tint(t.underline, cs.color(stateNormal))
+	if len(t.composition) == 0 {
+		t.underline.SetAlpha(0)
+	}
+	place(t.tail, left+head+composed, top)
+	tint(t.tail, s.textColor(state))
+
+	place(t.placeholder, s.padding, top)
+	tint(t.placeholder, s.textColor("placeholder"))
+	if len(t.value) > 0 || len(t.composition) > 0 {
+		t.placeholder.SetAlpha(0)
+	}
+
+	cc := t.theme.style("caret")
+	t.caret.SetSize(cc.width, lineHeight)
+	t.caretX = left + caret
+	place(t.caret, t.caretX, top)
+	tint(t.caret, cc.color(stateNormal))
+	if !t.focused || math.Mod(t.blink, 1) >= .5 {
+		t.caret.SetAlpha(0)
+	}
+
+	//the text is cut at the padding, so it never spills out of the field while scrolled
+	clip := framework.Bound{
+		Left:   float32(t.x + s.padding),
+		Right:  float32(t.x + t.width - s.padding),
+		Up:     float32(t.y),
+		Bottom: float32(t.y - t.height),
+	}
+	artists := t.Base.GetArtists()
+	for i := 1; i <= 5; i++ {
+		artists[i] = framework.Clip(artists[i], clip)
+	}
+	return artists
+}
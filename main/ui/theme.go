@@ -0,0 +1,333 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/koinuri/game-project/main/asset"
+	"github.com/koinuri/game-project/main/framework"
+	"gopkg.in/yaml.v2"
+)
+
+//Names of the states a widget is drawn in.  A state missing from a style falls back to the next one along
+//stateFallbacks, and to normal at the end.
+const (
+	stateNormal   = "normal"
+	stateHover    = "hover"
+	statePressed  = "pressed"
+	stateFocused  = "focused"
+	stateDisabled = "disabled"
+	stateSelected = "selected"
+)
+
+var stateFallbacks = map[string]string{
+	statePressed:  stateHover,
+	stateFocused:  stateHover,
+	stateSelected: stateFocused,
+}
+
+//Layout of the theme files, shared by json and yaml
+type themeFile struct {
+	Font     string               `json:"font" yaml:"font"`
+	FontSize float64              `json:"fontSize" yaml:"fontSize"`
+	Spacing  *float64             `json:"spacing" yaml:"spacing"`
+	Styles   map[string]styleFile `json:"styles" yaml:"styles"`
+}
+
+type styleFile struct {
+	Image      string               `json:"image" yaml:"image"`
+	Borders    []float64            `json:"borders" yaml:"borders"`
+	Padding    *float64             `json:"padding" yaml:"padding"`
+	Size       []float64            `json:"size" yaml:"size"`
+	Colors     map[string][]float64 `json:"colors" yaml:"colors"`
+	TextColors map[string][]float64 `json:"textColors" yaml:"textColors"`
+}
+
+//How one kind of widget, or one part of it, is drawn
+type style struct {
+	//image stretched over the widget, or nil for a plain rectangle
+	image      *framework.NineSlice
+	padding    float64
+	width      float64
+	height     float64
+	colors     map[string][4]float64
+	textColors map[string][4]float64
+}
+
+//Font, spacing and styles the widgets are drawn with.  Styles are named by the widget or part they are used for:
+//	panel, label, button, imageButton, checkbox, check, slider, handle, textField, caret, selection, composition,
+//	scrollBar, list, item, dropdown and popup.
+//The height of the composition style is the thickness of the line under text being composed with an input method.
+type Theme struct {
+	font    *framework.Font
+	spacing float64
+	styles  map[string]*style
+}
+
+var defaultTheme *Theme
+
+//Returns the theme used when none is given, made of plain rectangles and the font built into the game
+func DefaultTheme() *Theme {
+	if defaultTheme == nil {
+		defaultTheme = &Theme{
+			font:    framework.DefaultFont(),
+			spacing: 6,
+			styles: map[string]*style{
+				"panel": {padding: 8, colors: colors(stateNormal, .12, .12, .14, .9)},
+				"label": {padding: 2, textColors: colors(stateNormal, 1, 1, 1, 1, stateDisabled, .5, .5, .5, 1)},
+				"button": {
+					padding:    8,
+					colors:     colors(stateNormal, .25, .27, .32, 1, stateHover, .32, .35, .42, 1, statePressed, .18, .2, .24, 1, stateFocused, .3, .4, .55, 1, stateDisabled, .2, .2, .2, .6),
+					textColors: colors(stateNormal, 1, 1, 1, 1, stateDisabled, .5, .5, .5, 1),
+				},
+				"imageButton": {padding: 4, colors: colors(stateNormal, .85, .85, .85, 1, stateHover, 1, 1, 1, 1, statePressed, .7, .7, .7, 1, stateDisabled, .4, .4, .4, .5)},
+				"checkbox": {
+					padding:    6,
+					width:      20,
+					height:     20,
+					colors:     colors(stateNormal, .25, .27, .32, 1, stateHover, .32, .35, .42, 1, stateFocused, .3, .4, .55, 1, stateDisabled, .2, .2, .2, .6),
+					textColors: colors(stateNormal, 1, 1, 1, 1, stateDisabled, .5, .5, .5, 1),
+				},
+				"check":  {width: 12, height: 12, colors: colors(stateNormal, .9, .9, .9, 1, stateDisabled, .5, .5, .5, 1)},
+				"slider": {width: 200, height: 6, colors: colors(stateNormal, .2, .2, .24, 1, stateDisabled, .15, .15, .15, .6)},
+				"handle": {
+					width:  14,
+					height: 24,
+					colors: colors(stateNormal, .55, .58, .65, 1, stateHover, .7, .73, .8, 1, statePressed, .45, .48, .55, 1, stateFocused, .45, .6, .85, 1, stateDisabled, .3, .3, .3, .6),
+				},
+				"textField": {
+					padding:    6,
+					width:      200,
+					colors:     colors(stateNormal, .08, .08, .1, 1, stateHover, .1, .1, .12, 1, stateFocused, .1, .12, .18, 1, stateDisabled, .1, .1, .1, .6),
+					textColors: colors(stateNormal, 1, 1, 1, 1, stateDisabled, .5, .5, .5, 1, "placeholder", .45, .45, .5, 1),
+				},
+				"caret":     {width: 2, colors: colors(stateNormal, 1, 1, 1, 1)},
+				"selection": {colors: colors(stateNormal, .3, .45, .75, .6)},
+				"composition": {
+					height:     1,
+					colors:     colors(stateNormal, .95, .85, .45, 1),
+					textColors: colors(stateNormal, .95, .85, .45, 1),
+				},
+				"scrollBar": {width: 8, colors: colors(stateNormal, .35, .35, .4, .8, stateHover, .5, .5, .55, .9, statePressed, .6, .6, .65, 1)},
+				"list":      {padding: 4, colors: colors(stateNormal, .1, .1, .12, 1, stateFocused, .12, .13, .17, 1)},
+				"item": {
+					padding:    4,
+					colors:     colors(stateNormal, 0, 0, 0, 0, stateHover, .25, .27, .32, 1, stateSelected, .3, .4, .55, 1),
+					textColors: colors(stateNormal, 1, 1, 1, 1, stateDisabled, .5, .5, .5, 1),
+				},
+				"dropdown": {
+					padding:    8,
+					colors:     colors(stateNormal, .25, .27, .32, 1, stateHover, .32, .35, .42, 1, statePressed, .18, .2, .24, 1, stateFocused, .3, .4, .55, 1, stateDisabled, .2, .2, .2, .6),
+					textColors: colors(stateNormal, 1, 1, 1, 1, stateDisabled, .5, .5, .5, 1),
+				},
+				"popup": {padding: 4, colors: colors(stateNormal, .14, .15, .18, 1)},
+			},
+		}
+	}
+	return defaultTheme
+}
+
+//Builds a map of colors from a state followed by its red, green, blue and alpha, repeated for each state
+func colors(v ...interface{}) map[string][4]float64 {
+	m := make(map[string][4]float64)
+	for i := 0; i+4 < len(v); i += 5 {
+		var c [4]float64
+		for j := range c {
+			switch f := v[i+1+j].(type) {
+			case int:
+				c[j] = float64(f)
+			case float64:
+				c[j] = f
+			}
+		}
+		m[v[i].(string)] = c
+	}
+	return m
+}
+
+//Loads a theme from a json or yaml file.  Anything the file leaves out is taken from DefaultTheme.
+//	font: fonts/ui.ttf
+//	fontSize: 20
+//	spacing: 8
+//	styles:
+//	  button:
+//	    image: ui/button.png
+//	    borders: [8]
+//	    padding: 10
+//	    colors: {normal: [1, 1, 1, 1], hover: [1, 1, .8, 1], disabled: [.5, .5, .5, .5]}
+//	    textColors: {normal: [0, 0, 0, 1]}
+//Images are nine-slices cut by borders, which holds either one width for every border or the left, top, right and bottom
+//widths.  Colors tint the image, or fill the rectangle drawn when there is no image.  size is the width and height of
+//parts such as the box of a checkbox.
+func LoadTheme(dir string) *Theme {
+	file, err := loadThemeFile(dir)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load the file %v.\n%v", asset.Describe(dir), err))
+	}
+
+	base := DefaultTheme()
+	t := &Theme{
+		font:    base.font,
+		spacing: base.spacing,
+		styles:  make(map[string]*style),
+	}
+
+	if file.Font != "" {
+		size := file.FontSize
+		if size == 0 {
+			size = 16
+		}
+		t.font = framework.LoadFont(file.Font, size)
+	}
+	if file.Spacing != nil {
+		t.spacing = *file.Spacing
+	}
+
+	for name, s := range base.styles {
+		copied := *s
+		t.styles[name] = &copied
+	}
+	for name, fs := range file.Styles {
+		s, succ := t.styles[name]
+		if !succ {
+			s = &style{}
+			t.styles[name] = s
+		}
+		s.apply(name, fs)
+	}
+
+	return t
+}
+
+func loadThemeFile(dir string) (themeFile, error) {
+	var file themeFile
+
+	data, err := asset.ReadFile(dir)
+	if err != nil {
+		return file, err
+	}
+
+	ext := strings.ToLower(path.Ext(dir))
+	if ext == ".yaml" || ext == ".yml" {
+		err = yaml.Unmarshal(data, &file)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+
+	return file, err
+}
+
+//Overrides the style with what the file sets
+func (s *style) apply(name string, fs styleFile) {
+	if fs.Image != "" {
+		borders := fs.Borders
+		if len(borders) == 0 {
+			borders = []float64{0}
+		}
+		s.image = framework.InitNineSlice(fs.Image, borders...)
+	}
+	if fs.Padding != nil {
+		s.padding = *fs.Padding
+	}
+	switch len(fs.Size) {
+	case 0:
+	case 2:
+		s.width = fs.Size[0]
+		s.height = fs.Size[1]
+	default:
+		panic(fmt.Sprintf("Invalid size in the style \"%v\".  Expected a width and a height, found %v", name, fs.Size))
+	}
+
+	s.colors = mergeColors(name, s.colors, fs.Colors)
+	s.textColors = mergeColors(name, s.textColors, fs.TextColors)
+}
+
+func mergeColors(name string, colors map[string][4]float64, file map[string][]float64) map[string][4]float64 {
+	merged := make(map[string][4]float64)
+	for state, c := range colors {
+		merged[state] = c
+	}
+
+	for state, c := range file {
+		switch len(c) {
+		case 3:
+			merged[state] = [4]float64{c[0], c[1], c[2], 1}
+		case 4:
+			merged[state] = [4]float64{c[0], c[1], c[2], c[3]}
+		default:
+			panic(fmt.Sprintf("Invalid color \"%v\" in the style \"%v\".  Expected 3 or 4 numbers, found %v", state, name, c))
+		}
+	}
+	return merged
+}
+
+func (t *Theme) GetFont() *framework.Font {
+	return t.font
+}
+
+func (t *Theme) SetFont(f *framework.Font) {
+	t.font = f
+}
+
+//Returns the space left between the widgets of boxes and grids
+func (t *Theme) GetSpacing() float64 {
+	return t.spacing
+}
+
+func (t *Theme) SetSpacing(spacing float64) {
+	t.spacing = spacing
+}
+
+//Returns the style with the name, falling back to the default theme's, or to an empty style
+func (t *Theme) style(name string) *style {
+	if s, succ := t.styles[name]; succ {
+		return s
+	}
+	if s, succ := DefaultTheme().styles[name]; succ {
+		return s
+	}
+	return &style{}
+}
+
+//Creates the artist drawing the background of the style
+func (s *style) skin() *framework.NineSlice {
+	var n *framework.NineSlice
+	if s.image != nil {
+		n = s.image.Copy()
+	} else {
+		n = framework.InitRectangle(0, 0)
+	}
+	n.SetOrigin(framework.TopLeft)
+	n.SetAnchor(0, 0)
+	return n
+}
+
+//Returns the color of the state
+func (s *style) color(state string) [4]float64 {
+	return pick(s.colors, state)
+}
+
+func (s *style) textColor(state string) [4]float64 {
+	return pick(s.textColors, state)
+}
+
+func pick(colors map[string][4]float64, state string) [4]float64 {
+	for state != "" {
+		if c, succ := colors[state]; succ {
+			return c
+		}
+		state = stateFallbacks[state]
+	}
+	if c, succ := colors[stateNormal]; succ {
+		return c
+	}
+	return [4]float64{1, 1, 1, 1}
+}
+
+//Sets the color and opacity of an artist, such as a nine-slice or a text, to the color of a style
+func tint(a framework.Tintable, c [4]float64) {
+	a.SetColor(c[0], c[1], c[2])
+	a.SetAlpha(c[3])
+}
@@ -0,0 +1,549 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/koinuri/game-project/main/framework"
+	"github.com/koinuri/game-project/main/input"
+)
+
+const (
+	//seconds a direction is held on the gamepad before the focus starts moving again
+	navigationDelay = .4
+	//seconds between moves while the direction stays held
+	navigationRepeat = .1
+)
+
+//Root of a tree of widgets, laid out within a canvas.  Call Update once per frame after the input is updated, then draw
+//the artists returned by GetArtists over the game.
+type UI struct {
+	canvas   framework.Canvas
+	theme    *Theme
+	input    *input.Input
+	root     Widget
+	overlay  Widget
+	owner    Widget
+	widgets  []Widget
+	hovered  Widget
+	pressed  Widget
+	focused  Widget
+	onCancel func()
+	navX     int
+	navY     int
+	navWait  float64
+}
+
+//Creates a UI covering the canvas.
+//	*InitUI(canvas)
+//	*InitUI(canvas, theme)
+//Where:
+//	canvas is the part of the window the root widget is laid out in.
+//	theme is the theme giving the spacing of the containers that are not given one.  It is defaulted to DefaultTheme.
+func InitUI(canvas framework.Canvas, theme ...*Theme) *UI {
+	return &UI{
+		canvas: canvas,
+		theme:  themeArgument(theme),
+	}
+}
+
+//Sets the widget laid out over the whole canvas, usually an AnchorPanel or a box
+func (u *UI) SetRoot(w Widget) {
+	u.root = w
+	u.closeOverlay()
+	u.layout()
+}
+
+func (u *UI) GetRoot() Widget {
+	return u.root
+}
+
+//Sets the part of the window the root widget is laid out in, such as when the window is resized
+func (u *UI) SetCanvas(canvas framework.Canvas) {
+	u.canvas = canvas
+	u.closeOverlay()
+}
+
+func (u *UI) GetCanvas() framework.Canvas {
+	return u.canvas
+}
+
+//Returns the bounds of the canvas
+func (u *UI) GetBounds() framework.Bound {
+	return framework.Bound{
+		Left:   u.canvas.X - u.canvas.Width/2,
+		Right:  u.canvas.X + u.canvas.Width/2,
+		Up:     u.canvas.Y + u.canvas.Height/2,
+		Bottom: u.canvas.Y - u.canvas.Height/2,
+	}
+}
+
+func (u *UI) GetTheme() *Theme {
+	return u.theme
+}
+
+//Sets the function called when Escape or the B button are pressed with no open dropdown to close, such as to leave a menu
+func (u *UI) OnCancel(f func()) {
+	u.onCancel = f
+}
+
+//Gives the focus to the widget, or takes it away from every widget if it is nil
+func (u *UI) Focus(w Widget) {
+	if w == u.focused {
+		return
+	}
+
+	if u.focused != nil {
+		u.focused.base().focused = false
+		if c, succ := u.focused.(composer); succ {
+			c.compose("", 0)
+		}
+	}
+	u.focused = w
+	if w != nil {
+		w.base().focused = true
+		reveal(w, w.GetBounds())
+	}
+}
+
+//Returns the widget that has focus, or nil if none of them has it
+func (u *UI) GetFocus() Widget {
+	return u.focused
+}
+
+//Returns true if the mouse is over a widget, so the game can leave the click to the UI
+func (u *UI) IsMouseOver() bool {
+	return u.hovered != nil
+}
+
+//Shows the widget over every other one until closeOverlay is called, taking the input away from the rest
+func (u *UI) openOverlay(w Widget, owner Widget) {
+	u.overlay = w
+	u.owner = owner
+	u.layout()
+}
+
+//Closes the overlay, giving the focus back to the widget that opened it
+func (u *UI) closeOverlay() {
+	if u.overlay == nil {
+		return
+	}
+
+	owner := u.owner
+	u.overlay = nil
+	u.owner = nil
+	u.layout()
+	if o, succ := owner.(overlayOwner); succ {
+		o.overlayClosed()
+	}
+	if owner != nil && u.isFocusable(owner) {
+		u.Focus(owner)
+	}
+}
+
+//Lays out the widgets and gathers them in the order they are drawn
+func (u *UI) layout() {
+	u.widgets = u.widgets[:0]
+	if u.root != nil {
+		u.root.SetBounds(canvasBounds(u.canvas))
+		u.collect(u.root, nil)
+	}
+	if u.overlay != nil {
+		u.collect(u.overlay, nil)
+	}
+
+	//widgets taken out of the tree let go of the state they had in it
+	if u.hovered != nil && !u.contains(u.hovered) {
+		u.hovered.base().hovered = false
+		u.hovered = nil
+	}
+	if u.pressed != nil && !u.contains(u.pressed) {
+		u.pressed.base().pressed = false
+		u.pressed = nil
+	}
+	if u.focused != nil && !u.contains(u.focused) {
+		u.Focus(nil)
+	}
+}
+
+func (u *UI) collect(w Widget, parent Widget) {
+	if !w.IsVisible() {
+		return
+	}
+
+	b := w.base()
+	b.ui = u
+	b.parent = parent
+	u.widgets = append(u.widgets, w)
+
+	if l, succ := w.(layouter); succ {
+		l.layout()
+	}
+	for _, c := range w.GetChildren() {
+		u.collect(c, w)
+	}
+}
+
+func (u *UI) contains(w Widget) bool {
+	for _, c := range u.widgets {
+		if c == w {
+			return true
+		}
+	}
+	return false
+}
+
+//Returns the bounds the widget is clipped to by the panels holding it, and false if none of them clips it
+func clipOf(w Widget) (framework.Bound, bool) {
+	var clip framework.Bound
+	clipped := false
+
+	for p := w.base().parent; p != nil; p = p.base().parent {
+		c, succ := p.(clipper)
+		if !succ {
+			continue
+		}
+
+		b := c.clipBounds()
+		if clipped {
+			b = intersect(b, clip)
+		}
+		clip = b
+		clipped = true
+	}
+	return clip, clipped
+}
+
+//Returns the area within both bounds
+func intersect(a, b framework.Bound) framework.Bound {
+	return framework.Bound{
+		Left:   float32(math.Max(float64(a.Left), float64(b.Left))),
+		Right:  float32(math.Min(float64(a.Right), float64(b.Right))),
+		Up:     float32(math.Min(float64(a.Up), float64(b.Up))),
+		Bottom: float32(math.Max(float64(a.Bottom), float64(b.Bottom))),
+	}
+}
+
+func inside(b framework.Bound, x, y float64) bool {
+	return x >= float64(b.Left) && x < float64(b.Right) && y <= float64(b.Up) && y > float64(b.Bottom)
+}
+
+//Returns the topmost widget under the point, or nil if there is none
+func (u *UI) hit(x, y float64) Widget {
+	for i := len(u.widgets) - 1; i >= 0; i-- {
+		w := u.widgets[i]
+		if w.base().passive || !w.base().contains(x, y) {
+			continue
+		}
+		if clip, clipped := clipOf(w); clipped && !inside(clip, x, y) {
+			continue
+		}
+		return w
+	}
+	return nil
+}
+
+//Returns true if the widget is the overlay or within it
+func (u *UI) inOverlay(w Widget) bool {
+	for ; w != nil; w = w.base().parent {
+		if w == u.overlay {
+			return true
+		}
+	}
+	return false
+}
+
+//Returns true if the widget can be given focus by navigating
+func (u *UI) isFocusable(w Widget) bool {
+	b := w.base()
+	if !b.focusable || !w.IsEnabled() || !u.contains(w) {
+		return false
+	}
+	return u.overlay == nil || u.inOverlay(w)
+}
+
+//Returns the widgets that can be given focus, in the order they are drawn
+func (u *UI) focusable() []Widget {
+	widgets := make([]Widget, 0)
+	for _, w := range u.widgets {
+		if u.isFocusable(w) {
+			widgets = append(widgets, w)
+		}
+	}
+	return widgets
+}
+
+//Passes the input of the frame to the widgets.  dt is the time since the last call in seconds.
+func (u *UI) Update(in *input.Input, dt float64) {
+	u.input = in
+	u.layout()
+
+	x, y := in.GetMousePosition()
+	hit := u.hit(x, y)
+	if hit != nil && u.overlay != nil && !u.inOverlay(hit) {
+		hit = nil
+	}
+
+	if hit != u.hovered {
+		if u.hovered != nil {
+			u.hovered.base().hovered = false
+		}
+		if hit != nil {
+			hit.base().hovered = true
+		}
+		u.hovered = hit
+	}
+	if h, succ := hit.(hoverer); succ && hit.IsEnabled() {
+		h.hover(x, y)
+	}
+
+	u.mouse(in, hit, x, y)
+	for _, k := range in.GetPressedKeys() {
+		u.key(k, in.GetModifiers())
+	}
+	if t, succ := u.focused.(typer); succ {
+		for _, r := range in.GetTyped() {
+			t.typed(r)
+		}
+	}
+	if c, succ := u.focused.(composer); succ {
+		c.compose(in.GetComposition())
+		in.SetCandidatePosition(c.candidatePoint())
+	}
+	u.gamepad(in, dt)
+
+	for _, w := range u.widgets {
+		if up, succ := w.(updater); succ {
+			up.update(dt)
+		}
+	}
+
+	u.layout()
+}
+
+func (u *UI) mouse(in *input.Input, hit Widget, x, y float64) {
+	if in.WasMousePressed(glfw.MouseButtonLeft) {
+		if u.overlay != nil && !u.inOverlay(u.hit(x, y)) {
+			//a click away from the overlay only closes it
+			u.closeOverlay()
+		} else if hit != nil && hit.IsEnabled() {
+			u.pressed = hit
+			hit.base().pressed = true
+			if u.isFocusable(hit) {
+				u.Focus(hit)
+			} else {
+				u.Focus(nil)
+			}
+			if p, succ := hit.(presser); succ {
+				p.press(x, y)
+			}
+		} else {
+			u.Focus(nil)
+		}
+	}
+
+	if u.pressed != nil && in.IsMouseDown(glfw.MouseButtonLeft) {
+		if d, succ := u.pressed.(dragger); succ {
+			d.drag(x, y)
+		}
+	}
+
+	if u.pressed != nil && in.WasMouseReleased(glfw.MouseButtonLeft) {
+		pressed := u.pressed
+		pressed.base().pressed = false
+		u.pressed = nil
+		if r, succ := pressed.(releaser); succ {
+			r.release(x, y, pressed == hit)
+		}
+	}
+
+	//the scroll goes up from the widget under the mouse to the first one that scrolls
+	if sx, sy := in.GetScroll(); sx != 0 || sy != 0 {
+		for w := hit; w != nil; w = w.base().parent {
+			if s, succ := w.(scroller); succ && w.IsEnabled() && s.scroll(sx, sy) {
+				break
+			}
+		}
+	}
+}
+
+func (u *UI) key(k glfw.Key, mods glfw.ModifierKey) {
+	if h, succ := u.focused.(keyHandler); succ && h.key(k, mods) {
+		return
+	}
+
+	switch k {
+	case glfw.KeyTab:
+		if mods&glfw.ModShift != 0 {
+			u.cycle(-1)
+		} else {
+			u.cycle(1)
+		}
+	case glfw.KeyLeft:
+		u.navigate(-1, 0)
+	case glfw.KeyRight:
+		u.navigate(1, 0)
+	case glfw.KeyUp:
+		u.navigate(0, -1)
+	case glfw.KeyDown:
+		u.navigate(0, 1)
+	case glfw.KeyEnter, glfw.KeyKPEnter, glfw.KeySpace:
+		u.activate()
+	case glfw.KeyEscape:
+		u.cancel()
+	}
+}
+
+//Moves the focus with the directional pad or the left stick, repeating while it is held, and presses with A and B
+func (u *UI) gamepad(in *input.Input, dt float64) {
+	if !in.IsGamepadConnected() {
+		u.navX, u.navY = 0, 0
+		return
+	}
+
+	dx, dy := 0, 0
+	switch {
+	case in.IsGamepadButtonDown(input.GamepadLeft) || in.GetGamepadAxis(input.GamepadLeftX) < -.5:
+		dx = -1
+	case in.IsGamepadButtonDown(input.GamepadRight) || in.GetGamepadAxis(input.GamepadLeftX) > .5:
+		dx = 1
+	case in.IsGamepadButtonDown(input.GamepadUp) || in.GetGamepadAxis(input.GamepadLeftY) < -.5:
+		dy = -1
+	case in.IsGamepadButtonDown(input.GamepadDown) || in.GetGamepadAxis(input.GamepadLeftY) > .5:
+		dy = 1
+	}
+
+	if dx != u.navX || dy != u.navY {
+		u.navX, u.navY = dx, dy
+		u.navWait = navigationDelay
+		if dx != 0 || dy != 0 {
+			u.navigate(dx, dy)
+		}
+	} else if dx != 0 || dy != 0 {
+		u.navWait -= dt
+		for u.navWait <= 0 {
+			u.navigate(dx, dy)
+			u.navWait += navigationRepeat
+		}
+	}
+
+	if in.WasGamepadButtonPressed(input.GamepadA) {
+		u.activate()
+	}
+	if in.WasGamepadButtonPressed(input.GamepadB) {
+		u.cancel()
+	}
+}
+
+func (u *UI) activate() {
+	if a, succ := u.focused.(activator); succ && u.focused.IsEnabled() {
+		a.activate()
+	}
+}
+
+func (u *UI) cancel() {
+	if u.overlay != nil {
+		u.closeOverlay()
+	} else if u.onCancel != nil {
+		u.onCancel()
+	}
+}
+
+//Moves the focus to the next widget in the order they are drawn, or the previous one if dir is negative
+func (u *UI) cycle(dir int) {
+	widgets := u.focusable()
+	if len(widgets) == 0 {
+		return
+	}
+
+	current := -1
+	for i, w := range widgets {
+		if w == u.focused {
+			current = i
+		}
+	}
+
+	if current < 0 {
+		if dir > 0 {
+			u.Focus(widgets[0])
+		} else {
+			u.Focus(widgets[len(widgets)-1])
+		}
+		return
+	}
+	u.Focus(widgets[(current+dir+len(widgets))%len(widgets)])
+}
+
+//Moves the focus to the nearest widget in the direction, where dy is -1 for up, unless the widget that has focus uses it
+func (u *UI) navigate(dx, dy int) {
+	if n, succ := u.focused.(navigator); succ && n.navigate(dx, dy) {
+		return
+	}
+
+	widgets := u.focusable()
+	if u.focused == nil {
+		if len(widgets) > 0 {
+			u.Focus(widgets[0])
+		}
+		return
+	}
+
+	fx, fy := center(u.focused)
+	var best Widget
+	bestScore := math.Inf(1)
+	for _, w := range widgets {
+		if w == u.focused {
+			continue
+		}
+
+		//distances along the direction and across it, with y going down like dy
+		wx, wy := center(w)
+		along := (wx-fx)*float64(dx) + (fy-wy)*float64(dy)
+		across := math.Abs((wx-fx)*float64(dy)) + math.Abs((fy-wy)*float64(dx))
+		if along <= 0 {
+			continue
+		}
+
+		if score := along + across*2; score < bestScore {
+			best = w
+			bestScore = score
+		}
+	}
+
+	if best != nil {
+		u.Focus(best)
+	}
+}
+
+//Returns the center of the part of the widget that is not clipped away, such as the visible part of a long list
+func center(w Widget) (float64, float64) {
+	b := w.GetBounds()
+	if clip, clipped := clipOf(w); clipped {
+		b = intersect(b, clip)
+	}
+	return float64(b.Left+b.Right) / 2, float64(b.Up+b.Bottom) / 2
+}
+
+//Scrolls the panels holding the widget so the bound is within them
+func reveal(w Widget, b framework.Bound) {
+	for p := w.base().parent; p != nil; p = p.base().parent {
+		if s, succ := p.(*ScrollPanel); succ {
+			s.ScrollTo(b)
+		}
+	}
+}
+
+//Returns the artists of every visible widget, with the overlay drawn last
+func (u *UI) GetArtists() []framework.Artist {
+	artists := make([]framework.Artist, 0)
+	for _, w := range u.widgets {
+		clip, clipped := clipOf(w)
+		for _, a := range w.GetArtists() {
+			if clipped {
+				a = framework.Clip(a, clip)
+			}
+			artists = append(artists, a)
+		}
+	}
+	return artists
+}
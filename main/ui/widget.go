@@ -0,0 +1,334 @@
+//Package ui is a retained-mode toolkit of widgets drawn with the framework.  Widgets are created once, placed in layout
+//containers and handed to a UI, which lays them out within its canvas, passes them the input of every frame and returns
+//their artists to draw.
+package ui
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/koinuri/game-project/main/framework"
+)
+
+//Anything placed in a UI.  Widgets are made by embedding Base, which holds their rectangle and state.
+type Widget interface {
+	//Returns the rectangle of the widget in the window's 1600 by 900 coordinate system
+	GetBounds() framework.Bound
+	//Places the top left corner of the widget at (x, y) and sizes it, as its container does when laying it out
+	SetBounds(x, y, width, height float64)
+	//Returns the size the widget needs to show all it holds, or its minimum size if that is larger
+	GetPreferredSize() (float64, float64)
+	GetArtists() []framework.Artist
+	GetChildren() []Widget
+	IsVisible() bool
+	IsEnabled() bool
+	base() *Base
+}
+
+//The optional parts of a widget, found by the UI through type assertions like framework finds anchored artists
+type layouter interface {
+	//Places the children within the bounds of the widget
+	layout()
+}
+
+type clipper interface {
+	//Returns the bounds the children are only drawn and hit within
+	clipBounds() framework.Bound
+}
+
+type hoverer interface {
+	//Called every frame the mouse is over the widget
+	hover(x, y float64)
+}
+
+type presser interface {
+	press(x, y float64)
+}
+
+type dragger interface {
+	drag(x, y float64)
+}
+
+type releaser interface {
+	//inside is true if the mouse is still over the widget
+	release(x, y float64, inside bool)
+}
+
+type scroller interface {
+	//Returns true if the widget scrolled, so the scroll is not passed on to its parents
+	scroll(dx, dy float64) bool
+}
+
+type activator interface {
+	//Called when the widget is clicked, or when Enter, Space or the A button are pressed while it has focus
+	activate()
+}
+
+type navigator interface {
+	//Called with the direction of an arrow key while the widget has focus, where dx is -1 for left and 1 for right and dy
+	//is -1 for up and 1 for down.  Returns true if the widget used it, such as a slider moving its handle, so the focus
+	//does not move.
+	navigate(dx, dy int) bool
+}
+
+type keyHandler interface {
+	//Returns true if the widget used the key, so the UI does not
+	key(k glfw.Key, mods glfw.ModifierKey) bool
+}
+
+type typer interface {
+	typed(r rune)
+}
+
+type composer interface {
+	//Called every frame while the widget has focus with the text being composed with an input method and the position of
+	//its caret, which are empty when nothing is being composed
+	compose(text string, caret int)
+	//Returns where the window of the input method listing the candidates goes, such as under the caret
+	candidatePoint() (float64, float64)
+}
+
+type updater interface {
+	update(dt float64)
+}
+
+type overlayOwner interface {
+	//Called when the overlay the widget opened is closed, whether by the widget or by the player clicking away or pressing
+	//Escape
+	overlayClosed()
+}
+
+type holder interface {
+	//Returns the widgets the widget holds that are not among its children, such as the popup of a dropdown
+	held() []Widget
+}
+
+//Artists holding textures and buffers, such as nine-slices and texts
+type deleter interface {
+	Delete()
+}
+
+//Rectangle and state shared by every widget
+type Base struct {
+	obj       framework.Object
+	artists   []framework.Artist
+	theme     *Theme
+	ui        *UI
+	parent    Widget
+	x         float64
+	y         float64
+	width     float64
+	height    float64
+	minWidth  float64
+	minHeight float64
+	expand    bool
+	hidden    bool
+	disabled  bool
+	focusable bool
+	//containers that draw nothing of their own let the mouse through to what is behind them
+	passive bool
+	hovered bool
+	pressed bool
+	focused bool
+}
+
+func initBase(theme *Theme, focusable bool) Base {
+	if theme == nil {
+		theme = DefaultTheme()
+	}
+
+	b := Base{
+		obj:       framework.InitObject(float32(0), float32(0), framework.TopLeft),
+		theme:     theme,
+		focusable: focusable,
+	}
+	return b
+}
+
+//Returns the theme argument given to a constructor, or the default theme
+func themeArgument(theme []*Theme) *Theme {
+	if len(theme) > 0 && theme[0] != nil {
+		return theme[0]
+	}
+	return DefaultTheme()
+}
+
+func (b *Base) base() *Base {
+	return b
+}
+
+//Adds an artist drawn with the widget, placed by its top left corner relative to the top left corner of the widget
+func (b *Base) add(name string, a framework.Artist) {
+	b.obj.AddArtist(name, a)
+	b.artists = append(b.artists, a)
+}
+
+//Removes every artist added to the widget without freeing them, so they can be added again
+func (b *Base) reset() {
+	b.artists = nil
+	b.obj = framework.InitObject(float32(0), float32(0), framework.TopLeft)
+	b.obj.Move(b.x, b.y)
+	b.obj.Resize(b.width, b.height)
+}
+
+//Frees the textures and buffers of the widget and of every widget it holds, once it is removed from its UI for good.  The
+//widgets draw nothing afterwards.
+func Delete(w Widget) {
+	for _, c := range w.GetChildren() {
+		Delete(c)
+	}
+	if h, succ := w.(holder); succ {
+		for _, c := range h.held() {
+			Delete(c)
+		}
+	}
+
+	b := w.base()
+	for _, a := range b.artists {
+		if d, succ := a.(deleter); succ {
+			d.Delete()
+		}
+	}
+	b.reset()
+}
+
+func (b *Base) GetBounds() framework.Bound {
+	return framework.Bound{
+		Left:   float32(b.x),
+		Right:  float32(b.x + b.width),
+		Up:     float32(b.y),
+		Bottom: float32(b.y - b.height),
+	}
+}
+
+func (b *Base) SetBounds(x, y, width, height float64) {
+	b.x = x
+	b.y = y
+	b.width = width
+	b.height = height
+
+	b.obj.Move(x, y)
+	b.obj.Resize(width, height)
+}
+
+//Returns the width and height of the widget
+func (b *Base) GetSize() (float64, float64) {
+	return b.width, b.height
+}
+
+//Sets the size the widget is never laid out smaller than
+func (b *Base) SetMinSize(width, height float64) {
+	b.minWidth = width
+	b.minHeight = height
+}
+
+func (b *Base) GetMinSize() (float64, float64) {
+	return b.minWidth, b.minHeight
+}
+
+//Returns the size given, grown to the minimum size
+func (b *Base) fit(width, height float64) (float64, float64) {
+	if width < b.minWidth {
+		width = b.minWidth
+	}
+	if height < b.minHeight {
+		height = b.minHeight
+	}
+	return width, height
+}
+
+//Sets whether the widget takes a share of the space left over in a box, instead of keeping its preferred size
+func (b *Base) SetExpand(expand bool) {
+	b.expand = expand
+}
+
+func (b *Base) IsExpanding() bool {
+	return b.expand
+}
+
+func (b *Base) GetChildren() []Widget {
+	return nil
+}
+
+func (b *Base) GetArtists() []framework.Artist {
+	return b.obj.GetArtists()
+}
+
+func (b *Base) SetVisible(visible bool) {
+	b.hidden = !visible
+}
+
+func (b *Base) IsVisible() bool {
+	return !b.hidden
+}
+
+//Sets whether the widget reacts to input.  Disabled widgets are drawn in their disabled colors and cannot have focus.
+func (b *Base) SetEnabled(enabled bool) {
+	b.disabled = !enabled
+}
+
+func (b *Base) IsEnabled() bool {
+	return !b.disabled
+}
+
+func (b *Base) IsHovered() bool {
+	return b.hovered
+}
+
+func (b *Base) IsPressed() bool {
+	return b.pressed
+}
+
+func (b *Base) IsFocused() bool {
+	return b.focused
+}
+
+//Returns the widget holding the widget, or nil before it is first laid out by a UI
+func (b *Base) GetParent() Widget {
+	return b.parent
+}
+
+func (b *Base) GetTheme() *Theme {
+	return b.theme
+}
+
+//Returns the state the widget is drawn in
+func (b *Base) state() string {
+	switch {
+	case b.disabled:
+		return stateDisabled
+	case b.pressed && b.hovered:
+		return statePressed
+	case b.hovered:
+		return stateHover
+	case b.focused:
+		return stateFocused
+	}
+	return stateNormal
+}
+
+//Returns true if the point is within the bounds of the widget
+func (b *Base) contains(x, y float64) bool {
+	return x >= b.x && x < b.x+b.width && y <= b.y && y > b.y-b.height
+}
+
+//Creates a text for the widget, placed by its top left corner relative to the top left corner of the widget
+func (b *Base) text(s string) *framework.Text {
+	t := framework.InitText(s, b.theme.font)
+	t.SetAnchor(0, 0)
+	return t
+}
+
+//Places an artist at (x, y) from the top left corner of the widget, with y going down
+func place(a framework.Transformable, x, y float64) {
+	a.Move(x, -y)
+}
+
+//Returns the value kept within [min, max]
+func clamp(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}